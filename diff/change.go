@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operation is what happened to an element between the two configurations
+type Operation int
+
+const (
+	Added Operation = iota
+	Removed
+	Changed
+)
+
+func (o Operation) String() string {
+	if o == Added {
+		return "added"
+	}
+	if o == Removed {
+		return "removed"
+	}
+	return "changed"
+}
+
+func (o Operation) symbol() string {
+	if o == Added {
+		return "+"
+	}
+	if o == Removed {
+		return "-"
+	}
+	return "~"
+}
+
+// Section is the part of a build configuration a change belongs to
+type Section string
+
+const (
+	NameSection               Section = "name"
+	DescriptionSection        Section = "description"
+	ProjectSection            Section = "project"
+	TemplateSection           Section = "template"
+	VcsRootEntrySection       Section = "vcs root"
+	SettingSection            Section = "setting"
+	ParameterSection          Section = "parameter"
+	StepSection               Section = "step"
+	TriggerSection            Section = "trigger"
+	FeatureSection            Section = "feature"
+	SnapshotDependencySection Section = "snapshot dependency"
+	ArtifactDependencySection Section = "artifact dependency"
+	AgentRequirementSection   Section = "agent requirement"
+)
+
+// Change is a single difference. Key names the element within the section,
+// such as a parameter name or step ID, and Field the attribute that changed,
+// such as "value" or "properties.script.content". Old and New are empty when
+// the element was added or removed respectively.
+type Change struct {
+	Operation Operation
+	Section   Section
+	Key       string
+	Field     string
+	Old       string
+	New       string
+	// Secure marks values that must not be shown, e.g. of password
+	// parameters
+	Secure bool
+}
+
+const maskedValue = "******"
+
+func (c Change) value(v string) string {
+	if c.Secure && v != "" {
+		return maskedValue
+	}
+	return fmt.Sprintf("%q", v)
+}
+
+func (c Change) String() string {
+	subject := string(c.Section)
+	if c.Key != "" {
+		subject += " " + c.Key
+	}
+	if c.Field != "" {
+		subject += " " + c.Field
+	}
+	switch c.Operation {
+	case Added:
+		if c.New != "" {
+			return fmt.Sprintf("+ %s = %s", subject, c.value(c.New))
+		}
+	case Removed:
+		if c.Old != "" {
+			return fmt.Sprintf("- %s = %s", subject, c.value(c.Old))
+		}
+	case Changed:
+		return fmt.Sprintf("~ %s: %s -> %s", subject, c.value(c.Old), c.value(c.New))
+	}
+	return fmt.Sprintf("%s %s", c.Operation.symbol(), subject)
+}
+
+// Changes is the ordered result of Diff
+type Changes []Change
+
+// Empty tells whether the configurations are the same
+func (c Changes) Empty() bool {
+	return len(c) == 0
+}
+
+// Section returns the changes in one section
+func (c Changes) Section(section Section) Changes {
+	ret := make(Changes, 0)
+	for _, change := range c {
+		if change.Section == section {
+			ret = append(ret, change)
+		}
+	}
+	return ret
+}
+
+// String renders the changes one per line, prefixed with +, - or ~
+func (c Changes) String() string {
+	lines := make([]string, 0, len(c))
+	for _, change := range c {
+		lines = append(lines, change.String())
+	}
+	return strings.Join(lines, "\n")
+}
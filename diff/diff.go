@@ -0,0 +1,353 @@
+// Package diff compares build configurations and reports the differences as
+// a typed list of changes that can be inspected or rendered as text.
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// Diff returns the changes that turn a into b. Steps, triggers, features,
+// dependencies and agent requirements are matched by ID, or by position when
+// an ID is missing. Compare OwnSettings() of both sides to leave out what is
+// inherited from templates.
+func Diff(a, b *types.BuildConfiguration) Changes {
+	d := &differ{changes: make(Changes, 0)}
+
+	d.scalar(NameSection, a.Name, b.Name)
+	d.scalar(DescriptionSection, a.Description, b.Description)
+	d.scalar(ProjectSection, a.ProjectID, b.ProjectID)
+	d.templates(a.AttachedTemplates(), b.AttachedTemplates())
+	d.vcsRootEntries(a.VcsRootEntries, b.VcsRootEntries)
+	d.settings(a.Settings, b.Settings)
+	d.parameters(a.Parameters, b.Parameters)
+	d.items(StepSection, stepItems(a.Steps), stepItems(b.Steps), true)
+	d.items(TriggerSection, triggerItems(a.Triggers), triggerItems(b.Triggers), false)
+	d.items(FeatureSection, featureItems(a.Features), featureItems(b.Features), false)
+	d.items(SnapshotDependencySection, snapshotDependencyItems(a.SnapshotDependencies), snapshotDependencyItems(b.SnapshotDependencies), false)
+	d.items(ArtifactDependencySection, artifactDependencyItems(a.ArtifactDependencies), artifactDependencyItems(b.ArtifactDependencies), false)
+	d.items(AgentRequirementSection, agentRequirementItems(a.AgentRequirements), agentRequirementItems(b.AgentRequirements), false)
+
+	return d.changes
+}
+
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) add(change Change) {
+	d.changes = append(d.changes, change)
+}
+
+func (d *differ) scalar(section Section, before, after string) {
+	if before != after {
+		d.add(Change{Operation: Changed, Section: section, Old: before, New: after})
+	}
+}
+
+func (d *differ) templates(before, after types.TemplateIds) {
+	d.keyOrder(TemplateSection, templateKeys(before), templateKeys(after))
+}
+
+func templateKeys(templates types.TemplateIds) []string {
+	keys := make([]string, 0, len(templates))
+	for _, id := range templates {
+		keys = append(keys, string(id))
+	}
+	return keys
+}
+
+// keyOrder reports added and removed keys and, if the common keys moved
+// around, the change of order
+func (d *differ) keyOrder(section Section, before, after []string) {
+	inOld := make(map[string]bool)
+	for _, key := range before {
+		inOld[key] = true
+	}
+	inNew := make(map[string]bool)
+	for _, key := range after {
+		inNew[key] = true
+	}
+	for _, key := range before {
+		if !inNew[key] {
+			d.add(Change{Operation: Removed, Section: section, Key: key})
+		}
+	}
+	for _, key := range after {
+		if !inOld[key] {
+			d.add(Change{Operation: Added, Section: section, Key: key})
+		}
+	}
+
+	commonOld := make([]string, 0)
+	for _, key := range before {
+		if inNew[key] {
+			commonOld = append(commonOld, key)
+		}
+	}
+	commonNew := make([]string, 0)
+	for _, key := range after {
+		if inOld[key] {
+			commonNew = append(commonNew, key)
+		}
+	}
+	if strings.Join(commonOld, "\x00") != strings.Join(commonNew, "\x00") {
+		d.add(Change{
+			Operation: Changed,
+			Section:   section,
+			Field:     "order",
+			Old:       strings.Join(commonOld, ", "),
+			New:       strings.Join(commonNew, ", "),
+		})
+	}
+}
+
+func (d *differ) vcsRootEntries(before, after types.VcsRootEntries) {
+	oldRules := make(map[string]string)
+	for _, entry := range before {
+		oldRules[string(entry.VcsRootID)] = entry.CheckoutRules
+	}
+	newRules := make(map[string]string)
+	for _, entry := range after {
+		newRules[string(entry.VcsRootID)] = entry.CheckoutRules
+	}
+	d.stringMap(VcsRootEntrySection, "checkout rules", oldRules, newRules, nil)
+}
+
+func (d *differ) settings(before, after types.BuildSettings) {
+	oldValues := make(map[string]string)
+	for _, setting := range before {
+		oldValues[setting.Name] = setting.Value
+	}
+	newValues := make(map[string]string)
+	for _, setting := range after {
+		newValues[setting.Name] = setting.Value
+	}
+	d.stringMap(SettingSection, "", oldValues, newValues, nil)
+}
+
+// stringMap compares two maps key by key in sorted order. field names the
+// attribute reported for changed values.
+func (d *differ) stringMap(section Section, field string, before, after map[string]string, secure func(key string) bool) {
+	keys := make([]string, 0)
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		oldValue, inOld := before[key]
+		newValue, inNew := after[key]
+		isSecure := secure != nil && secure(key)
+		switch {
+		case !inOld:
+			d.add(Change{Operation: Added, Section: section, Key: key, New: newValue, Secure: isSecure})
+		case !inNew:
+			d.add(Change{Operation: Removed, Section: section, Key: key, Old: oldValue, Secure: isSecure})
+		case oldValue != newValue:
+			d.add(Change{Operation: Changed, Section: section, Key: key, Field: field, Old: oldValue, New: newValue, Secure: isSecure})
+		}
+	}
+}
+
+func (d *differ) parameters(before, after types.Parameters) {
+	names := make([]string, 0)
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		oldParameter, inOld := before[name]
+		newParameter, inNew := after[name]
+		switch {
+		case !inOld:
+			d.add(Change{Operation: Added, Section: ParameterSection, Key: name, New: newParameter.Value, Secure: newParameter.IsSecure()})
+		case !inNew:
+			d.add(Change{Operation: Removed, Section: ParameterSection, Key: name, Old: oldParameter.Value, Secure: oldParameter.IsSecure()})
+		default:
+			if oldParameter.Value != newParameter.Value {
+				d.add(Change{
+					Operation: Changed,
+					Section:   ParameterSection,
+					Key:       name,
+					Field:     "value",
+					Old:       oldParameter.Value,
+					New:       newParameter.Value,
+					Secure:    oldParameter.IsSecure() || newParameter.IsSecure(),
+				})
+			}
+			if oldSpec, newSpec := oldParameter.SpecString(), newParameter.SpecString(); oldSpec != newSpec {
+				d.add(Change{Operation: Changed, Section: ParameterSection, Key: name, Field: "spec", Old: oldSpec, New: newSpec})
+			}
+		}
+	}
+}
+
+// item is the common shape of steps, triggers, features, dependencies and
+// agent requirements
+type item struct {
+	key        string
+	fields     map[string]string
+	properties types.Properties
+}
+
+func itemKey(id string, idx int) string {
+	if id != "" {
+		return id
+	}
+	return fmt.Sprintf("#%d", idx+1)
+}
+
+func stepItems(steps types.BuildSteps) []item {
+	items := make([]item, 0, len(steps))
+	for idx, step := range steps {
+		items = append(items, item{
+			key:        itemKey(step.ID, idx),
+			fields:     map[string]string{"type": step.Type, "name": step.Name, "disabled": fmt.Sprint(step.Disabled)},
+			properties: step.Properties,
+		})
+	}
+	return items
+}
+
+func triggerItems(triggers types.BuildTriggers) []item {
+	items := make([]item, 0, len(triggers))
+	for idx, trigger := range triggers {
+		items = append(items, item{
+			key:        itemKey(trigger.ID, idx),
+			fields:     map[string]string{"type": trigger.Type, "disabled": fmt.Sprint(trigger.Disabled)},
+			properties: trigger.Properties,
+		})
+	}
+	return items
+}
+
+func featureItems(features types.BuildFeatures) []item {
+	items := make([]item, 0, len(features))
+	for idx, feature := range features {
+		items = append(items, item{
+			key:        itemKey(feature.ID, idx),
+			fields:     map[string]string{"type": feature.Type, "disabled": fmt.Sprint(feature.Disabled)},
+			properties: feature.Properties,
+		})
+	}
+	return items
+}
+
+func snapshotDependencyItems(dependencies types.BuildSnapshotDependencies) []item {
+	items := make([]item, 0, len(dependencies))
+	for idx, dependency := range dependencies {
+		id := dependency.ID
+		if id == "" {
+			id = dependency.SourceBuildType.ID
+		}
+		items = append(items, item{
+			key: itemKey(id, idx),
+			fields: map[string]string{
+				"type":     dependency.Type,
+				"source":   dependency.SourceBuildType.ID,
+				"disabled": fmt.Sprint(dependency.Disabled),
+			},
+			properties: dependency.Properties,
+		})
+	}
+	return items
+}
+
+func artifactDependencyItems(dependencies types.BuildArtifactDependencies) []item {
+	items := make([]item, 0, len(dependencies))
+	for idx, dependency := range dependencies {
+		items = append(items, item{
+			key: itemKey(dependency.ID, idx),
+			fields: map[string]string{
+				"type":     dependency.Type,
+				"source":   dependency.SourceBuildType.ID,
+				"disabled": fmt.Sprint(dependency.Disabled),
+			},
+			properties: dependency.Properties,
+		})
+	}
+	return items
+}
+
+func agentRequirementItems(requirements types.BuildAgentRequirements) []item {
+	items := make([]item, 0, len(requirements))
+	for idx, requirement := range requirements {
+		items = append(items, item{
+			key:        itemKey(requirement.ID, idx),
+			fields:     map[string]string{"type": requirement.Type, "disabled": fmt.Sprint(requirement.Disabled)},
+			properties: requirement.Properties,
+		})
+	}
+	return items
+}
+
+var itemFields = []string{"type", "name", "source", "disabled"}
+
+// items matches elements by key and reports added, removed and changed ones.
+// ordered is set for steps, where the order of execution matters.
+func (d *differ) items(section Section, before, after []item, ordered bool) {
+	oldByKey := make(map[string]item)
+	oldKeys := make([]string, 0, len(before))
+	for _, it := range before {
+		oldByKey[it.key] = it
+		oldKeys = append(oldKeys, it.key)
+	}
+	newByKey := make(map[string]item)
+	newKeys := make([]string, 0, len(after))
+	for _, it := range after {
+		newByKey[it.key] = it
+		newKeys = append(newKeys, it.key)
+	}
+
+	for _, key := range oldKeys {
+		if _, ok := newByKey[key]; !ok {
+			d.add(Change{Operation: Removed, Section: section, Key: key, Old: oldByKey[key].fields["type"]})
+		}
+	}
+	for _, key := range newKeys {
+		newItem := newByKey[key]
+		oldItem, ok := oldByKey[key]
+		if !ok {
+			d.add(Change{Operation: Added, Section: section, Key: key, New: newItem.fields["type"]})
+			continue
+		}
+		for _, field := range itemFields {
+			if oldItem.fields[field] != newItem.fields[field] {
+				d.add(Change{Operation: Changed, Section: section, Key: key, Field: field, Old: oldItem.fields[field], New: newItem.fields[field]})
+			}
+		}
+		properties := &differ{changes: make(Changes, 0)}
+		properties.stringMap(section, "", oldItem.properties, newItem.properties, types.IsSecureProperty)
+		for _, change := range properties.changes {
+			change.Field = "properties." + change.Key
+			change.Key = key
+			change.Operation = Changed
+			d.add(change)
+		}
+	}
+
+	if ordered {
+		common := &differ{changes: make(Changes, 0)}
+		common.keyOrder(section, oldKeys, newKeys)
+		for _, change := range common.changes {
+			if change.Field == "order" {
+				d.add(change)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func baseConfiguration() *types.BuildConfiguration {
+	return &types.BuildConfiguration{
+		ID:        "App_Build",
+		ProjectID: "App",
+		Name:      "Build",
+		Templates: types.TemplateIds{"App_Base"},
+		VcsRootEntries: types.VcsRootEntries{
+			{VcsRootID: "App_Git", CheckoutRules: "+:."},
+		},
+		Settings: types.BuildSettings{
+			{Name: "buildNumberPattern", Value: "%build.counter%"},
+		},
+		Parameters: types.Parameters{
+			"version":   types.Parameter{Value: "1.0"},
+			"env.TOKEN": types.Parameter{Value: "", Spec: &types.ParameterSpec{Type: types.PasswordType{}}},
+			"obsolete":  types.Parameter{Value: "x"},
+		},
+		Steps: types.BuildSteps{
+			{ID: "RUNNER_1", Name: "Compile", Type: "simpleRunner", Properties: types.Properties{"script.content": "make"}},
+			{ID: "RUNNER_2", Name: "Test", Type: "simpleRunner", Properties: types.Properties{"script.content": "make test"}},
+		},
+		Triggers: types.BuildTriggers{
+			{ID: "TRIGGER_1", Type: "vcsTrigger", Properties: types.Properties{"quietPeriodMode": "DO_NOT_USE"}},
+		},
+		SnapshotDependencies: types.BuildSnapshotDependencies{
+			{Type: "snapshot_dependency", SourceBuildType: types.BuildType{ID: "App_Lib"}},
+		},
+	}
+}
+
+func TestDiffEqual(t *testing.T) {
+	changes := Diff(baseConfiguration(), baseConfiguration())
+	assert.True(t, changes.Empty(), "Expected no changes, got\n%s", changes)
+}
+
+func TestDiff(t *testing.T) {
+	a := baseConfiguration()
+	b := baseConfiguration()
+	b.Name = "Build and test"
+	b.Templates = types.TemplateIds{"App_Base", "App_Notify"}
+	b.VcsRootEntries[0] = types.VcsRootEntry{VcsRootID: "App_Git", CheckoutRules: "+:src"}
+	b.Settings = types.BuildSettings{{Name: "buildNumberPattern", Value: "1.%build.counter%"}}
+	b.Parameters = types.Parameters{
+		"version":   types.Parameter{Value: "2.0", Spec: &types.ParameterSpec{Type: types.TextType{ValidationMode: "not_empty"}}},
+		"env.TOKEN": types.Parameter{Value: "credentialsJSON:abc", Spec: &types.ParameterSpec{Type: types.PasswordType{}}},
+		"added":     types.Parameter{Value: "y"},
+	}
+	b.Steps = types.BuildSteps{
+		{ID: "RUNNER_2", Name: "Test", Type: "simpleRunner", Properties: types.Properties{"script.content": "make check"}},
+		{ID: "RUNNER_1", Name: "Compile", Type: "simpleRunner", Disabled: true, Properties: types.Properties{"script.content": "make"}},
+		{ID: "RUNNER_3", Name: "Package", Type: "simpleRunner"},
+	}
+	b.Triggers = types.BuildTriggers{}
+	b.SnapshotDependencies[0].Properties = types.Properties{"run-build-if-dependency-failed": "RUN_ADD_PROBLEM"}
+
+	changes := Diff(a, b)
+	assert.Equal(t, `~ name: "Build" -> "Build and test"
++ template App_Notify
+~ vcs root App_Git checkout rules: "+:." -> "+:src"
+~ setting buildNumberPattern: "%build.counter%" -> "1.%build.counter%"
++ parameter added = "y"
+~ parameter env.TOKEN value: "" -> ******
+- parameter obsolete = "x"
+~ parameter version value: "1.0" -> "2.0"
+~ parameter version spec: "" -> "text display='normal' validationMode='not_empty'"
+~ step RUNNER_2 properties.script.content: "make test" -> "make check"
+~ step RUNNER_1 disabled: "false" -> "true"
++ step RUNNER_3 = "simpleRunner"
+~ step order: "RUNNER_1, RUNNER_2" -> "RUNNER_2, RUNNER_1"
+- trigger TRIGGER_1 = "vcsTrigger"
+~ snapshot dependency App_Lib properties.run-build-if-dependency-failed: "" -> "RUN_ADD_PROBLEM"`, changes.String())
+
+	assert.Len(t, changes.Section(ParameterSection), 5)
+	assert.Equal(t, Change{
+		Operation: Changed,
+		Section:   StepSection,
+		Key:       "RUNNER_1",
+		Field:     "disabled",
+		Old:       "false",
+		New:       "true",
+	}, changes.Section(StepSection)[1])
+}
+
+func TestDiffMatchesByPositionWithoutIDs(t *testing.T) {
+	a := &types.BuildConfiguration{Features: types.BuildFeatures{
+		{Type: "perfmon"},
+	}}
+	b := &types.BuildConfiguration{Features: types.BuildFeatures{
+		{Type: "perfmon"},
+		{Type: "swabra"},
+	}}
+	assert.Equal(t, `+ feature #2 = "swabra"`, Diff(a, b).String())
+}
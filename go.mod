@@ -2,4 +2,7 @@ module github.com/icelander/teamcity-sdk-go
 
 go 1.12
 
-require github.com/stretchr/testify v1.4.0
+require (
+	github.com/stretchr/testify v1.4.0
+	gopkg.in/yaml.v2 v2.2.2
+)
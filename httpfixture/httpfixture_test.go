@@ -0,0 +1,203 @@
+package httpfixture
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/teamcity"
+	"github.com/icelander/teamcity-sdk-go/teamcitytest"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type session struct {
+	server    *types.Server
+	project   *types.Project
+	parameter types.Parameter
+	missing   *types.Project
+}
+
+func exercise(t *testing.T, client *teamcity.Client) session {
+	var s session
+	var err error
+	s.server, err = client.Server()
+	require.NoError(t, err)
+
+	project := &types.Project{Name: "My App", ParentProjectID: "_Root"}
+	require.NoError(t, client.CreateProject(project))
+	s.parameter = types.Parameter{Value: "hunter2", Spec: &types.ParameterSpec{Type: types.PasswordType{}}}
+	require.NoError(t, client.ReplaceProjectParameter(project.ID, "deploy.password", &s.parameter))
+
+	s.project, err = client.GetProject(project.ID)
+	require.NoError(t, err)
+	s.missing, err = client.GetProject("Missing")
+	require.NoError(t, err)
+	return s
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpfixture")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "recorded", "TestClientSession.json")
+
+	server := teamcitytest.NewServer()
+	server.Username = "admin"
+	server.Password = "s3cret"
+	client := server.Client()
+	recorder := NewRecorder(client.HTTPClient.Transport)
+	client.HTTPClient.Transport = recorder
+	recorded := exercise(t, client)
+	server.Close()
+	require.NoError(t, recorder.Save(path))
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "s3cret")
+	assert.NotContains(t, string(content), "hunter2")
+	assert.NotContains(t, string(content), strings.TrimPrefix(server.URL, "http://"))
+	assert.NotContains(t, string(content), "Authorization")
+	assert.Contains(t, string(content), "http://"+Host+"/project.html?projectId=MyApp")
+
+	client = teamcity.New("teamcity.example.com", "admin", "s3cret", "latest")
+	replayer, err := Load(path)
+	require.NoError(t, err)
+	client.HTTPClient.Transport = replayer
+	replayed := exercise(t, client)
+	assert.Empty(t, replayer.Unused())
+
+	assert.Equal(t, recorded.server, replayed.server)
+	recorded.project.WebUrl = "http://" + Host + "/project.html?projectId=MyApp"
+	assert.Equal(t, recorded.project, replayed.project)
+	assert.Nil(t, replayed.missing)
+	assert.Equal(t, "My App", replayed.project.Name)
+}
+
+func TestRecordKeepsPasswordInBodies(t *testing.T) {
+	server := teamcitytest.NewServer()
+	defer server.Close()
+	server.Username = "admin"
+	server.Password = "App"
+	client := server.Client()
+	recorder := NewRecorder(client.HTTPClient.Transport)
+	client.HTTPClient.Transport = recorder
+	recorded := exercise(t, client)
+
+	// the password only travels in the Authorization header, which is not
+	// recorded, and is not replaced in paths and bodies
+	client = teamcity.New("teamcity.example.com", "admin", "another", "latest")
+	replayer := NewReplayer(recorder.Fixture())
+	client.HTTPClient.Transport = replayer
+	replayed := exercise(t, client)
+	assert.Empty(t, replayer.Unused())
+	assert.Equal(t, recorded.project.ID, replayed.project.ID)
+	assert.Equal(t, "My App", replayed.project.Name)
+}
+
+func TestReplayMatchesMethodPathAndBody(t *testing.T) {
+	fixture := &Fixture{Interactions: []Interaction{
+		{
+			Request:  Request{Method: "PUT", Path: "/app/rest/projects/id:A/name", Body: Body("first")},
+			Response: Response{StatusCode: 200, ContentType: "text/plain", Body: Body("first")},
+		},
+		{
+			Request:  Request{Method: "POST", Path: "/app/rest/projects", Body: Body(`{"name":"A","id":"A"}`)},
+			Response: Response{StatusCode: 200, ContentType: "application/json", Body: Body(`{"id":"A"}`)},
+		},
+		{
+			Request:  Request{Method: "GET", Path: "/app/rest/builds?locator=count:1"},
+			Response: Response{StatusCode: 200, ContentType: "application/json", Body: Body(`{"count":0}`)},
+		},
+		{
+			Request:  Request{Method: "GET", Path: "/app/rest/builds?locator=count:1"},
+			Response: Response{StatusCode: 200, ContentType: "application/json", Body: Body(`{"count":1}`)},
+		},
+	}}
+	client := &http.Client{Transport: NewReplayer(fixture)}
+
+	do := func(method, path, body string) (string, error) {
+		req, err := http.NewRequest(method, "http://teamcity.example.com"+path, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		content, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.Header.Get("Content-Type") + " " + string(content), nil
+	}
+
+	// JSON bodies match regardless of key order
+	content, err := do("POST", "/app/rest/projects", `{"id": "A", "name": "A"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `application/json {"id":"A"}`, content)
+
+	// repeated requests get their responses in the order recorded
+	content, err = do("GET", "/app/rest/builds?locator=count:1", "")
+	require.NoError(t, err)
+	assert.Equal(t, `application/json {"count":0}`, content)
+	content, err = do("GET", "/app/rest/builds?locator=count:1", "")
+	require.NoError(t, err)
+	assert.Equal(t, `application/json {"count":1}`, content)
+	_, err = do("GET", "/app/rest/builds?locator=count:1", "")
+	assert.Contains(t, err.Error(), "no recorded response for GET /app/rest/builds?locator=count:1")
+
+	_, err = do("PUT", "/app/rest/projects/id:A/name", "second")
+	assert.Contains(t, err.Error(), "no recorded response for PUT /app/rest/projects/id:A/name with body second")
+	_, err = do("POST", "/app/rest/projects/id:A/name", "first")
+	assert.Error(t, err)
+
+	unused := NewReplayer(fixture).Unused()
+	assert.Len(t, unused, 4)
+}
+
+func TestScrub(t *testing.T) {
+	scrub := scrubber{host: "tc.internal:8111", secrets: []string{"s3cret"}}
+	assert.Equal(t, "/app/rest/users?token="+Scrubbed, scrub.string("/app/rest/users?token=s3cret"))
+	assert.Equal(t, `{"password":"`+Scrubbed+`"}`, string(scrub.body([]byte(`{"password":"s3cret"}`))))
+	assert.Equal(t, `{"webUrl":"http://`+Host+`/project.html"}`,
+		string(scrub.body([]byte(`{"webUrl":"http://tc.internal:8111/project.html"}`))))
+
+	assert.JSONEq(t, `{"property":[
+		{"name":"secure:password","value":"`+Scrubbed+`"},
+		{"name":"deploy.password","value":"`+Scrubbed+`","type":{"rawValue":"password display='hidden'"}},
+		{"name":"url","value":"https://example.com"}
+	]}`, string(scrub.body([]byte(`{"property":[
+		{"name":"secure:password","value":"hunter2"},
+		{"name":"deploy.password","value":"hunter2","type":{"rawValue":"password display='hidden'"}},
+		{"name":"url","value":"https://example.com"}
+	]}`))))
+
+	// bodies without secrets are recorded as they were sent
+	body := `{ "name" : "A" }`
+	assert.Equal(t, body, string(scrub.body([]byte(body))))
+	assert.Equal(t, "plain text", string(scrub.body([]byte("plain text"))))
+}
+
+func TestBodyJSON(t *testing.T) {
+	fixture := Fixture{Interactions: []Interaction{{
+		Request:  Request{Method: "PUT", Path: "/x", Body: Body("true")},
+		Response: Response{StatusCode: 200, Body: Body(`{"id": "A"}`)},
+	}}}
+	dir, err := ioutil.TempDir("", "httpfixture")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fixture.json")
+	require.NoError(t, fixture.WriteFile(path))
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"body": "true"`)
+	assert.Contains(t, string(content), `"id": "A"`)
+
+	read, err := ReadFixture(path)
+	require.NoError(t, err)
+	assert.Equal(t, Body("true"), read.Interactions[0].Request.Body)
+	assert.Equal(t, Body(`{"id":"A"}`), read.Interactions[0].Response.Body)
+}
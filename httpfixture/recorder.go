@@ -0,0 +1,98 @@
+package httpfixture
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper that passes requests on to another
+// transport and records each exchange
+type Recorder struct {
+	// Transport performs the requests; http.DefaultTransport when nil
+	Transport http.RoundTripper
+	// Secrets are replaced with Scrubbed wherever they appear in a recorded
+	// path or body. The basic authentication password is only sent in a
+	// header and is not replaced, since a short password such as admin would
+	// also match user names and hrefs.
+	Secrets []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a Recorder sending requests through transport
+func NewRecorder(transport http.RoundTripper, secrets ...string) *Recorder {
+	return &Recorder{
+		Transport: transport,
+		Secrets:   secrets,
+	}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	forward := new(http.Request)
+	*forward = *req
+	if req.Body != nil {
+		forward.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(forward)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	scrub := newScrubber(req.URL.Host, r.Secrets)
+	r.interactions = append(r.interactions, Interaction{
+		Request: Request{
+			Method:      req.Method,
+			Path:        scrub.string(req.URL.RequestURI()),
+			ContentType: req.Header.Get("Content-Type"),
+			Body:        scrub.body(body),
+		},
+		Response: Response{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        scrub.body(respBody),
+		},
+	})
+	return resp, nil
+}
+
+// Fixture returns the exchanges recorded so far
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	interactions := make([]Interaction, len(r.interactions))
+	copy(interactions, r.interactions)
+	return &Fixture{Interactions: interactions}
+}
+
+// Save writes the exchanges recorded so far to a fixture file
+func (r *Recorder) Save(path string) error {
+	return r.Fixture().WriteFile(path)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return ioutil.ReadAll(req.Body)
+}
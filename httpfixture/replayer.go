@@ -0,0 +1,104 @@
+package httpfixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// Replayer is an http.RoundTripper answering requests from a fixture. A
+// request is matched to the first interaction not replayed yet with the same
+// method, path and body, so repeated requests receive their responses in the
+// order they were recorded. JSON bodies are compared by value.
+type Replayer struct {
+	// Secrets are scrubbed from incoming requests before matching, so they
+	// match the requests scrubbed while recording
+	Secrets []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewReplayer returns a Replayer answering from fixture
+func NewReplayer(fixture *Fixture, secrets ...string) *Replayer {
+	return &Replayer{
+		Secrets:      secrets,
+		interactions: fixture.Interactions,
+		replayed:     make([]bool, len(fixture.Interactions)),
+	}
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	scrub := newScrubber(req.URL.Host, r.Secrets)
+	path := scrub.string(req.URL.RequestURI())
+	body = scrub.body(body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.interactions {
+		if r.replayed[i] || !interaction.Request.matches(req.Method, path, body) {
+			continue
+		}
+		r.replayed[i] = true
+		return interaction.Response.httpResponse(req), nil
+	}
+	if len(body) > 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s with body %s", req.Method, path, body)
+	}
+	return nil, fmt.Errorf("no recorded response for %s %s", req.Method, path)
+}
+
+// Unused returns the interactions that have not been replayed yet
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.replayed[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func (req Request) matches(method, path string, body []byte) bool {
+	return req.Method == method && req.Path == path && sameBody(req.Body, body)
+}
+
+func sameBody(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var aValue, bValue interface{}
+	if json.Unmarshal(a, &aValue) != nil || json.Unmarshal(b, &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+func (resp Response) httpResponse(req *http.Request) *http.Response {
+	header := http.Header{}
+	if resp.ContentType != "" {
+		header.Set("Content-Type", resp.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}
+}
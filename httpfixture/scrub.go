@@ -0,0 +1,96 @@
+package httpfixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Scrubbed replaces credentials in recorded fixtures
+const Scrubbed = "********"
+
+// Host replaces the host name of the recorded server, which appears in the
+// web URLs of most responses
+const Host = "teamcity.example.com"
+
+type scrubber struct {
+	host    string
+	secrets []string
+}
+
+func newScrubber(host string, secrets []string) scrubber {
+	s := scrubber{host: host}
+	for _, secret := range secrets {
+		if secret != "" {
+			s.secrets = append(s.secrets, secret)
+		}
+	}
+	return s
+}
+
+func (s scrubber) string(value string) string {
+	for _, secret := range s.secrets {
+		value = strings.Replace(value, secret, Scrubbed, -1)
+	}
+	if s.host != "" {
+		value = strings.Replace(value, s.host, Host, -1)
+	}
+	return value
+}
+
+func (s scrubber) body(body []byte) []byte {
+	for _, secret := range s.secrets {
+		body = bytes.Replace(body, []byte(secret), []byte(Scrubbed), -1)
+	}
+	if s.host != "" {
+		body = bytes.Replace(body, []byte(s.host), []byte(Host), -1)
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || !scrubSecureValues(value) {
+		return body
+	}
+	scrubbed, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return scrubbed
+}
+
+// scrubSecureValues replaces the values of password parameters and secure
+// properties anywhere in a decoded JSON document and reports whether any were
+// found.
+func scrubSecureValues(value interface{}) bool {
+	found := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if isSecure(v) {
+			if s, ok := v["value"].(string); ok && s != "" && s != Scrubbed {
+				v["value"] = Scrubbed
+				found = true
+			}
+		}
+		for _, child := range v {
+			found = scrubSecureValues(child) || found
+		}
+	case []interface{}:
+		for _, child := range v {
+			found = scrubSecureValues(child) || found
+		}
+	}
+	return found
+}
+
+func isSecure(property map[string]interface{}) bool {
+	if name, ok := property["name"].(string); ok && strings.HasPrefix(name, "secure:") {
+		return true
+	}
+	if spec, ok := property["type"].(map[string]interface{}); ok {
+		if raw, ok := spec["rawValue"].(string); ok && strings.HasPrefix(raw, "password") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,393 @@
+package kotlindsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// settingProperties maps build settings to top level build type properties.
+// Values of the settings in unquotedSettings are numbers or booleans.
+var settingProperties = map[string]string{
+	"artifactRules":       "artifactRules",
+	"buildNumberPattern":  "buildNumberPattern",
+	"maxRunningBuilds":    "maxRunningBuilds",
+	"allowExternalStatus": "allowExternalStatus",
+}
+
+var unquotedSettings = map[string]bool{
+	"maxRunningBuilds":    true,
+	"allowExternalStatus": true,
+}
+
+// nestedSettings are written inside the vcs and failureConditions blocks
+var nestedSettings = map[string]bool{
+	"checkoutDirectory":   true,
+	"checkoutMode":        true,
+	"cleanBuild":          true,
+	"executionTimeoutMin": true,
+}
+
+// failureActions maps the run-build-if-dependency-failed values of the REST
+// API to FailureAction constants
+var failureActions = map[string]string{
+	"RUN":                  "IGNORE",
+	"RUN_ADD_PROBLEM":      "ADD_PROBLEM",
+	"MAKE_FAILED_TO_START": "FAIL_TO_START",
+	"CANCEL":               "CANCEL",
+}
+
+var requirementFunctions = map[string]string{
+	"exists":            "exists",
+	"does-not-exist":    "doesNotExist",
+	"equals":            "equals",
+	"does-not-equal":    "doesNotEqual",
+	"contains":          "contains",
+	"does-not-contain":  "doesNotContain",
+	"starts-with":       "startsWith",
+	"ends-with":         "endsWith",
+	"matches":           "matches",
+	"does-not-match":    "doesNotMatch",
+	"more-than":         "moreThan",
+	"not-more-than":     "noMoreThan",
+	"less-than":         "lessThan",
+	"not-less-than":     "noLessThan",
+	"ver-more-than":     "moreThanVer",
+	"ver-not-more-than": "noMoreThanVer",
+	"ver-less-than":     "lessThanVer",
+	"ver-not-less-than": "noLessThanVer",
+}
+
+func settingValue(setting types.BuildSetting) string {
+	if unquotedSettings[setting.Name] {
+		return setting.Value
+	}
+	return quote(setting.Value)
+}
+
+func (g *generator) buildType(projectID string, config types.BuildConfiguration, template bool) {
+	own := config.OwnSettings()
+	class := "BuildType"
+	if template {
+		class = "Template"
+	}
+
+	templates := own.Templates
+	if len(templates) == 0 && own.TemplateID != "" {
+		templates = types.TemplateIds{own.TemplateID}
+	}
+
+	settings := make(map[string]types.BuildSetting)
+	for _, setting := range own.Settings {
+		settings[setting.Name] = setting
+	}
+
+	w := &writer{}
+	g.header(w, projectID+".buildTypes")
+	w.block(fmt.Sprintf("object %s : %s({", config.ID, class), "})", func() {
+		if len(templates) > 0 {
+			refs := make([]string, 0, len(templates))
+			for _, id := range templates {
+				refs = append(refs, g.buildTypeRef(string(id)))
+			}
+			w.line("templates(%s)", strings.Join(refs, ", "))
+		}
+		w.line("id(%s)", quote(config.ID))
+		w.line("name = %s", quote(config.Name))
+		if config.Description != "" {
+			w.line("description = %s", quote(config.Description))
+		}
+
+		unmapped := make([]types.BuildSetting, 0)
+		for _, setting := range own.Settings {
+			if property, ok := settingProperties[setting.Name]; ok {
+				w.line("%s = %s", property, settingValue(setting))
+			} else if !nestedSettings[setting.Name] {
+				unmapped = append(unmapped, setting)
+			}
+		}
+
+		g.writeVcs(w, own.VcsRootEntries, settings)
+		writeParams(w, own.Parameters)
+		writeSteps(w, own.Steps)
+		writeItems(w, "triggers", "trigger", triggerItems(own.Triggers))
+		writeItems(w, "features", "feature", featureItems(own.Features))
+		if timeout, ok := settings["executionTimeoutMin"]; ok {
+			w.line("")
+			w.block("failureConditions {", "", func() {
+				w.line("executionTimeoutMin = %s", timeout.Value)
+			})
+		}
+		g.writeDependencies(w, own.SnapshotDependencies, own.ArtifactDependencies)
+		writeRequirements(w, own.AgentRequirements)
+
+		if len(unmapped) > 0 {
+			w.line("")
+			for _, setting := range unmapped {
+				w.line("// setting %s = %s has no DSL property", setting.Name, quote(setting.Value))
+			}
+		}
+	})
+	g.files[fmt.Sprintf("%s/buildTypes/%s.kt", projectID, config.ID)] = w.String()
+}
+
+func (g *generator) writeVcs(w *writer, entries types.VcsRootEntries, settings map[string]types.BuildSetting) {
+	checkoutDir, hasCheckoutDir := settings["checkoutDirectory"]
+	cleanBuild, hasCleanBuild := settings["cleanBuild"]
+	checkoutMode, hasCheckoutMode := settings["checkoutMode"]
+	if len(entries) == 0 && !hasCheckoutDir && !hasCleanBuild && !hasCheckoutMode {
+		return
+	}
+	w.line("")
+	w.block("vcs {", "", func() {
+		for _, entry := range entries {
+			ref := g.vcsRootRef(string(entry.VcsRootID))
+			if entry.CheckoutRules != "" {
+				w.line("root(%s, %s)", ref, quote(entry.CheckoutRules))
+			} else {
+				w.line("root(%s)", ref)
+			}
+		}
+		if hasCheckoutMode {
+			w.line("checkoutMode = CheckoutMode.%s", checkoutMode.Value)
+		}
+		if hasCheckoutDir {
+			w.line("checkoutDir = %s", quote(checkoutDir.Value))
+		}
+		if hasCleanBuild {
+			w.line("cleanCheckout = %s", cleanBuild.Value)
+		}
+	})
+}
+
+func writeSteps(w *writer, steps types.BuildSteps) {
+	if len(steps) == 0 {
+		return
+	}
+	w.line("")
+	w.block("steps {", "", func() {
+		for _, step := range steps {
+			w.block("step {", "", func() {
+				if step.Name != "" {
+					w.line("name = %s", quote(step.Name))
+				}
+				if step.ID != "" {
+					w.line("id = %s", quote(step.ID))
+				}
+				w.line("type = %s", quote(step.Type))
+				if step.Disabled {
+					w.line("enabled = false")
+				}
+				writeProperties(w, step.Properties)
+			})
+		}
+	})
+}
+
+// item is the part triggers and features have in common
+type item struct {
+	ID         string
+	Type       string
+	Properties types.Properties
+	Disabled   bool
+}
+
+func triggerItems(triggers types.BuildTriggers) []item {
+	items := make([]item, 0, len(triggers))
+	for _, t := range triggers {
+		items = append(items, item{t.ID, t.Type, t.Properties, t.Disabled})
+	}
+	return items
+}
+
+func featureItems(features types.BuildFeatures) []item {
+	items := make([]item, 0, len(features))
+	for _, f := range features {
+		items = append(items, item{f.ID, f.Type, f.Properties, f.Disabled})
+	}
+	return items
+}
+
+func writeItems(w *writer, section string, function string, items []item) {
+	if len(items) == 0 {
+		return
+	}
+	w.line("")
+	w.block(section+" {", "", func() {
+		for _, it := range items {
+			w.block(function+" {", "", func() {
+				if it.ID != "" {
+					w.line("id = %s", quote(it.ID))
+				}
+				w.line("type = %s", quote(it.Type))
+				if it.Disabled {
+					w.line("enabled = false")
+				}
+				writeProperties(w, it.Properties)
+			})
+		}
+	})
+}
+
+// dependency combines the snapshot and artifact dependencies on one source
+type dependency struct {
+	source    string
+	snapshot  *types.BuildSnapshotDependency
+	artifacts []types.BuildArtifactDependency
+}
+
+func (g *generator) writeDependencies(w *writer, snapshots types.BuildSnapshotDependencies, artifacts types.BuildArtifactDependencies) {
+	dependencies := make([]*dependency, 0)
+	bySource := make(map[string]*dependency)
+	lookup := func(source string) *dependency {
+		if d, ok := bySource[source]; ok {
+			return d
+		}
+		d := &dependency{source: source}
+		bySource[source] = d
+		dependencies = append(dependencies, d)
+		return d
+	}
+	for i := range snapshots {
+		lookup(snapshots[i].SourceBuildType.ID).snapshot = &snapshots[i]
+	}
+	for _, artifact := range artifacts {
+		d := lookup(artifact.SourceBuildType.ID)
+		d.artifacts = append(d.artifacts, artifact)
+	}
+	if len(dependencies) == 0 {
+		return
+	}
+
+	w.line("")
+	w.block("dependencies {", "", func() {
+		for _, d := range dependencies {
+			w.block(fmt.Sprintf("dependency(%s) {", g.buildTypeRef(d.source)), "", func() {
+				if d.snapshot != nil {
+					writeSnapshot(w, *d.snapshot)
+				}
+				for _, artifact := range d.artifacts {
+					writeArtifacts(w, artifact)
+				}
+			})
+		}
+	})
+}
+
+func writeSnapshot(w *writer, snapshot types.BuildSnapshotDependency) {
+	properties := make(types.Properties)
+	for name, value := range snapshot.Properties {
+		properties[name] = value
+	}
+	w.block("snapshot {", "", func() {
+		if snapshot.Disabled {
+			w.line("enabled = false")
+		}
+		if action, ok := failureActions[properties["run-build-if-dependency-failed"]]; ok {
+			w.line("onDependencyFailure = FailureAction.%s", action)
+			delete(properties, "run-build-if-dependency-failed")
+		}
+		if action, ok := failureActions[properties["run-build-if-dependency-failed-to-start"]]; ok {
+			w.line("onDependencyCancel = FailureAction.%s", action)
+			delete(properties, "run-build-if-dependency-failed-to-start")
+		}
+		if value, ok := properties["run-build-on-the-same-agent"]; ok {
+			w.line("runOnSameAgent = %s", value)
+			delete(properties, "run-build-on-the-same-agent")
+		}
+		if value, ok := properties["sync-revisions"]; ok {
+			w.line("synchronizeRevisions = %s", value)
+			delete(properties, "sync-revisions")
+		}
+		reuse, hasReuse := properties["take-started-build-with-same-revisions"]
+		successful, hasSuccessful := properties["take-successful-builds-only"]
+		if hasReuse || hasSuccessful {
+			switch {
+			case reuse == "false":
+				w.line("reuseBuilds = ReuseBuilds.NO")
+			case successful == "false":
+				w.line("reuseBuilds = ReuseBuilds.ANY")
+			default:
+				w.line("reuseBuilds = ReuseBuilds.SUCCESSFUL")
+			}
+			delete(properties, "take-started-build-with-same-revisions")
+			delete(properties, "take-successful-builds-only")
+		}
+		for _, property := range properties.Ordered() {
+			w.line("// property %s = %s has no DSL property", property.Name, quote(property.Value))
+		}
+	})
+}
+
+func writeArtifacts(w *writer, artifact types.BuildArtifactDependency) {
+	properties := make(types.Properties)
+	for name, value := range artifact.Properties {
+		properties[name] = value
+	}
+	w.block("artifacts {", "", func() {
+		if artifact.ID != "" {
+			w.line("id = %s", quote(artifact.ID))
+		}
+		if artifact.Disabled {
+			w.line("enabled = false")
+		}
+		branch := ""
+		if value, ok := properties["revisionBranch"]; ok {
+			branch = quote(value)
+			delete(properties, "revisionBranch")
+		}
+		if rule, ok := properties["revisionName"]; ok {
+			value := properties["revisionValue"]
+			switch rule {
+			case "lastSuccessful", "lastPinned", "lastFinished", "sameChainOrLastFinished":
+				w.line("buildRule = %s(%s)", rule, branch)
+				delete(properties, "revisionName")
+				delete(properties, "revisionValue")
+			case "buildNumber":
+				w.line("buildRule = build(%s)", quote(value))
+				delete(properties, "revisionName")
+				delete(properties, "revisionValue")
+			}
+		}
+		if value, ok := properties["cleanDestinationDirectory"]; ok {
+			w.line("cleanDestination = %s", value)
+			delete(properties, "cleanDestinationDirectory")
+		}
+		if value, ok := properties["pathRules"]; ok {
+			w.line("artifactRules = %s", quote(value))
+			delete(properties, "pathRules")
+		}
+		for _, property := range properties.Ordered() {
+			w.line("// property %s = %s has no DSL property", property.Name, quote(property.Value))
+		}
+	})
+}
+
+func writeRequirements(w *writer, requirements types.BuildAgentRequirements) {
+	if len(requirements) == 0 {
+		return
+	}
+	w.line("")
+	w.block("requirements {", "", func() {
+		for _, requirement := range requirements {
+			args := []string{quote(requirement.Properties["property-name"])}
+			if value, ok := requirement.Properties["property-value"]; ok {
+				args = append(args, quote(value))
+			}
+			function, ok := requirementFunctions[requirement.Type]
+			if !ok {
+				function = requirement.Type
+			}
+			call := fmt.Sprintf("%s(%s)", function, strings.Join(args, ", "))
+			switch {
+			case !ok:
+				w.line("// requirement %s has no DSL function", call)
+			case requirement.Disabled:
+				w.line("// disabled: %s", call)
+			default:
+				w.line("%s", call)
+			}
+		}
+	})
+}
@@ -0,0 +1,245 @@
+// Package kotlindsl renders a project tree as TeamCity Kotlin DSL sources: a
+// settings.kts entry point plus one file per project, build configuration,
+// template and VCS root, laid out the way TeamCity does for non-portable
+// settings.
+//
+// Steps, triggers and features are written in the generic form holding the
+// runner or feature type and its properties, so any type can be expressed.
+package kotlindsl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// DefaultVersion is the DSL version generated when none is given
+const DefaultVersion = "2019.2"
+
+type Options struct {
+	// Version of the Kotlin DSL, e.g. "2019.2"
+	Version string
+}
+
+// Files maps paths relative to the .teamcity directory to their content
+type Files map[string]string
+
+// Names returns the file paths sorted
+func (f Files) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write stores the files below dir, creating directories as needed
+func (f Files) Write(dir string) error {
+	for _, name := range f.Names() {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(f[name]), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+type generator struct {
+	dslPackage string
+	files      Files
+	// owner maps build configuration, template and VCS root IDs to the ID of
+	// the project whose package holds them
+	buildTypeOwner map[string]string
+	vcsRootOwner   map[string]string
+	vcsRoots       map[string][]types.VcsRoot
+}
+
+// Generate renders the project, its subprojects and the given VCS roots. The
+// projects need their build configurations and templates loaded in full, as
+// returned by GetBuildConfiguration. References to build configurations,
+// templates and VCS roots outside the tree are written as AbsoluteId.
+func Generate(project *types.Project, vcsRoots []types.VcsRoot, options Options) (Files, error) {
+	version := options.Version
+	if version == "" {
+		version = DefaultVersion
+	}
+	g := &generator{
+		dslPackage:     "jetbrains.buildServer.configs.kotlin.v" + strings.Replace(version, ".", "_", -1),
+		files:          make(Files),
+		buildTypeOwner: make(map[string]string),
+		vcsRootOwner:   make(map[string]string),
+		vcsRoots:       make(map[string][]types.VcsRoot),
+	}
+
+	projectIDs := make(map[string]bool)
+	if err := g.index(*project, projectIDs); err != nil {
+		return nil, err
+	}
+	for _, root := range vcsRoots {
+		if !identifier.MatchString(root.ID) {
+			return nil, fmt.Errorf("vcs root ID %q is not a valid Kotlin identifier", root.ID)
+		}
+		projectID := string(root.ProjectID)
+		if !projectIDs[projectID] {
+			return nil, fmt.Errorf("vcs root %s belongs to project %s outside the tree", root.ID, projectID)
+		}
+		g.vcsRootOwner[root.ID] = projectID
+		g.vcsRoots[projectID] = append(g.vcsRoots[projectID], root)
+	}
+	for projectID := range g.vcsRoots {
+		roots := g.vcsRoots[projectID]
+		sort.Slice(roots, func(i, j int) bool { return roots[i].ID < roots[j].ID })
+	}
+
+	w := &writer{}
+	w.line("import %s.*", g.dslPackage)
+	w.line("")
+	w.line("version = %s", quote(version))
+	w.line("")
+	w.line("project(%s.Project)", project.ID)
+	g.files["settings.kts"] = w.String()
+
+	g.project(*project)
+	return g.files, nil
+}
+
+// index records where each entity will be generated and checks that every
+// ID can be used as a Kotlin identifier
+func (g *generator) index(project types.Project, projectIDs map[string]bool) error {
+	if !identifier.MatchString(project.ID) {
+		return fmt.Errorf("project ID %q is not a valid Kotlin identifier", project.ID)
+	}
+	projectIDs[project.ID] = true
+	for _, configs := range []types.BuildConfigurations{project.Templates, project.BuildConfigurations} {
+		for id := range configs {
+			if !identifier.MatchString(id) {
+				return fmt.Errorf("build configuration ID %q is not a valid Kotlin identifier", id)
+			}
+			g.buildTypeOwner[id] = project.ID
+		}
+	}
+	for id, child := range project.Projects {
+		child.ID = id
+		if err := g.index(child, projectIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) header(w *writer, pkg string) {
+	w.line("package %s", pkg)
+	w.line("")
+	w.line("import %s.*", g.dslPackage)
+	w.line("")
+}
+
+func (g *generator) buildTypeRef(id string) string {
+	if owner, ok := g.buildTypeOwner[id]; ok {
+		return fmt.Sprintf("%s.buildTypes.%s", owner, id)
+	}
+	return fmt.Sprintf("AbsoluteId(%s)", quote(id))
+}
+
+func (g *generator) vcsRootRef(id string) string {
+	if owner, ok := g.vcsRootOwner[id]; ok {
+		return fmt.Sprintf("%s.vcsRoots.%s", owner, id)
+	}
+	return fmt.Sprintf("AbsoluteId(%s)", quote(id))
+}
+
+func sortedIDs(configs types.BuildConfigurations) []string {
+	ids := make([]string, 0, len(configs))
+	for id := range configs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (g *generator) project(project types.Project) {
+	childIDs := make([]string, 0, len(project.Projects))
+	for id := range project.Projects {
+		childIDs = append(childIDs, id)
+	}
+	sort.Strings(childIDs)
+
+	w := &writer{}
+	g.header(w, project.ID)
+	w.block("object Project : Project({", "})", func() {
+		w.line("id(%s)", quote(project.ID))
+		w.line("name = %s", quote(project.Name))
+		if project.Description != "" {
+			w.line("description = %s", quote(project.Description))
+		}
+		if roots := g.vcsRoots[project.ID]; len(roots) > 0 {
+			w.line("")
+			for _, root := range roots {
+				w.line("vcsRoot(%s)", g.vcsRootRef(root.ID))
+			}
+		}
+		if len(project.Templates) > 0 {
+			w.line("")
+			for _, id := range sortedIDs(project.Templates) {
+				w.line("template(%s)", g.buildTypeRef(id))
+			}
+		}
+		if len(project.BuildConfigurations) > 0 {
+			w.line("")
+			for _, id := range sortedIDs(project.BuildConfigurations) {
+				w.line("buildType(%s)", g.buildTypeRef(id))
+			}
+		}
+		writeParams(w, project.Parameters.Own())
+		if len(childIDs) > 0 {
+			w.line("")
+			for _, id := range childIDs {
+				w.line("subProject(%s.Project)", id)
+			}
+		}
+	})
+	g.files[project.ID+"/Project.kt"] = w.String()
+
+	for _, root := range g.vcsRoots[project.ID] {
+		g.vcsRoot(project.ID, root)
+	}
+	for _, id := range sortedIDs(project.Templates) {
+		template := project.Templates[id]
+		template.ID = id
+		g.buildType(project.ID, template, true)
+	}
+	for _, id := range sortedIDs(project.BuildConfigurations) {
+		config := project.BuildConfigurations[id]
+		config.ID = id
+		g.buildType(project.ID, config, false)
+	}
+	for _, id := range childIDs {
+		child := project.Projects[id]
+		child.ID = id
+		g.project(child)
+	}
+}
+
+func (g *generator) vcsRoot(projectID string, root types.VcsRoot) {
+	w := &writer{}
+	g.header(w, projectID+".vcsRoots")
+	w.block(fmt.Sprintf("object %s : VcsRoot({", root.ID), "})", func() {
+		w.line("id(%s)", quote(root.ID))
+		w.line("name = %s", quote(root.Name))
+		w.line("type = %s", quote(root.VcsName))
+		writeProperties(w, root.Properties)
+	})
+	g.files[fmt.Sprintf("%s/vcsRoots/%s.kt", projectID, root.ID)] = w.String()
+}
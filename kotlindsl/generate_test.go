@@ -0,0 +1,280 @@
+package kotlindsl
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func testTree() (*types.Project, []types.VcsRoot) {
+	base := types.BuildConfiguration{
+		ID:           "App_Base",
+		ProjectID:    "App",
+		TemplateFlag: true,
+		Name:         "Base",
+		Settings: types.BuildSettings{
+			{Name: "checkoutMode", Value: "ON_AGENT"},
+			{Name: "cleanBuild", Value: "true"},
+		},
+		Parameters: types.Parameters{
+			"env.GOFLAGS": types.Parameter{Value: "-mod=vendor"},
+		},
+		Features: types.BuildFeatures{
+			{
+				ID:         "swabra",
+				Type:       "swabra",
+				Properties: types.Properties{"swabra.enabled": "swabra.before.build"},
+			},
+		},
+		AgentRequirements: types.BuildAgentRequirements{
+			{
+				ID:         "RQ_1",
+				Type:       "exists",
+				Properties: types.Properties{"property-name": "env.GOROOT"},
+			},
+			{
+				ID:         "RQ_2",
+				Type:       "does-not-equal",
+				Properties: types.Properties{"property-name": "teamcity.agent.jvm.os.name", "property-value": "Windows 10"},
+				Disabled:   true,
+			},
+		},
+	}
+	lib := types.BuildConfiguration{
+		ID:        "App_Lib",
+		ProjectID: "App",
+		Name:      "Lib",
+		Settings: types.BuildSettings{
+			{Name: "artifactRules", Value: "bin => lib.zip"},
+		},
+		VcsRootEntries: types.VcsRootEntries{
+			{ID: "App_Git", VcsRootID: "App_Git", CheckoutRules: "+:lib"},
+		},
+		Steps: types.BuildSteps{
+			{
+				ID:   "RUNNER_1",
+				Type: "simpleRunner",
+				Name: "Build",
+				Properties: types.Properties{
+					"script.content":     "go build -o bin/ ./...\necho \"$HOME\"",
+					"use.custom.script":  "true",
+					"teamcity.step.mode": "default",
+				},
+			},
+		},
+	}
+	app := types.BuildConfiguration{
+		ID:          "App_Service",
+		ProjectID:   "App",
+		Name:        "Service",
+		Description: "Builds and tests the service",
+		Templates:   types.TemplateIds{"App_Base"},
+		Settings: types.BuildSettings{
+			{Name: "buildNumberPattern", Value: "1.0.%build.counter%"},
+			{Name: "maxRunningBuilds", Value: "2"},
+			{Name: "executionTimeoutMin", Value: "30"},
+			{Name: "shouldFailBuildOnAnyErrorMessage", Value: "true"},
+		},
+		VcsRootEntries: types.VcsRootEntries{
+			{ID: "App_Git", VcsRootID: "App_Git"},
+			{ID: "Shared_Tools", VcsRootID: "Shared_Tools", CheckoutRules: "+:. => tools"},
+		},
+		Parameters: types.Parameters{
+			"env.GOFLAGS": types.Parameter{Value: "-mod=vendor", Inherited: true},
+			"deploy": types.Parameter{
+				Value: "false",
+				Spec: &types.ParameterSpec{
+					Label: "Deploy",
+					Type:  types.CheckboxType{Checked: "true", Unchecked: "false"},
+				},
+			},
+			"region": types.Parameter{
+				Value: "eu",
+				Spec: &types.ParameterSpec{
+					Display: types.Prompt,
+					Type: types.SelectType{Items: []types.SelectItem{
+						{Label: "Europe", Value: "eu"},
+						{Value: "us"},
+					}},
+				},
+			},
+			"version": types.Parameter{
+				Value: "",
+				Spec: &types.ParameterSpec{
+					Description: "Semantic version",
+					Type:        types.TextType{ValidationMode: types.ValidationModeRegex, Regex: `\d+\.\d+`, ValidationMessage: "Use major.minor"},
+				},
+			},
+			"token": types.Parameter{
+				Value: "",
+				Spec:  &types.ParameterSpec{Display: types.Hidden, Type: types.PasswordType{}},
+			},
+		},
+		Steps: types.BuildSteps{
+			{
+				ID:         "RUNNER_2",
+				Type:       "Maven2",
+				Name:       "Test",
+				Properties: types.Properties{"goals": "test"},
+				Disabled:   true,
+			},
+		},
+		Triggers: types.BuildTriggers{
+			{
+				ID:         "vcsTrigger",
+				Type:       "vcsTrigger",
+				Properties: types.Properties{"branchFilter": "+:*"},
+			},
+		},
+		SnapshotDependencies: types.BuildSnapshotDependencies{
+			{
+				ID:              "App_Lib",
+				Type:            "snapshot_dependency",
+				SourceBuildType: types.BuildType{ID: "App_Lib"},
+				Properties: types.Properties{
+					"run-build-if-dependency-failed":          "MAKE_FAILED_TO_START",
+					"run-build-if-dependency-failed-to-start": "RUN_ADD_PROBLEM",
+					"run-build-on-the-same-agent":             "false",
+					"take-started-build-with-same-revisions":  "true",
+					"take-successful-builds-only":             "true",
+				},
+			},
+		},
+		ArtifactDependencies: types.BuildArtifactDependencies{
+			{
+				ID:              "ARTIFACT_DEPENDENCY_1",
+				Type:            "artifact_dependency",
+				SourceBuildType: types.BuildType{ID: "App_Lib"},
+				Properties: types.Properties{
+					"pathRules":                 "lib.zip!** => lib",
+					"revisionName":              "sameChainOrLastFinished",
+					"revisionValue":             "latest.sameChainOrLastFinished",
+					"cleanDestinationDirectory": "true",
+				},
+			},
+			{
+				ID:              "ARTIFACT_DEPENDENCY_2",
+				Type:            "artifact_dependency",
+				SourceBuildType: types.BuildType{ID: "Shared_Tools_Build"},
+				Properties: types.Properties{
+					"pathRules":      "tools.zip",
+					"revisionName":   "lastSuccessful",
+					"revisionValue":  "latest.lastSuccessful",
+					"revisionBranch": "<default>",
+				},
+			},
+		},
+		AgentRequirements: types.BuildAgentRequirements{
+			{
+				ID:         "RQ_1",
+				Type:       "exists",
+				Properties: types.Properties{"property-name": "env.GOROOT"},
+				Inherited:  true,
+			},
+			{
+				ID:         "RQ_3",
+				Type:       "ver-more-than",
+				Properties: types.Properties{"property-name": "docker.version", "property-value": "18"},
+			},
+			{
+				ID:         "RQ_4",
+				Type:       "custom-check",
+				Properties: types.Properties{"property-name": "gpu"},
+			},
+		},
+	}
+
+	project := &types.Project{
+		ID:          "App",
+		Name:        "App",
+		Description: "The application",
+		Parameters: types.Parameters{
+			"env.REGISTRY": types.Parameter{Value: "registry.example.com"},
+		},
+		Templates: types.BuildConfigurations{"App_Base": base},
+		BuildConfigurations: types.BuildConfigurations{
+			"App_Lib":     lib,
+			"App_Service": app,
+		},
+		Projects: types.Projects{
+			"App_Docs": types.Project{
+				ID:   "App_Docs",
+				Name: "Docs",
+			},
+		},
+	}
+	roots := []types.VcsRoot{
+		{
+			ID:        "App_Git",
+			Name:      "app",
+			VcsName:   "jetbrains.git",
+			ProjectID: "App",
+			Properties: types.Properties{
+				"url":             "https://example.com/app.git",
+				"branch":          "refs/heads/master",
+				"secure:password": "",
+			},
+		},
+	}
+	return project, roots
+}
+
+func TestGenerateGolden(t *testing.T) {
+	project, roots := testTree()
+	files, err := Generate(project, roots, Options{})
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "app")
+	if *update {
+		require.NoError(t, os.RemoveAll(golden))
+		require.NoError(t, files.Write(golden))
+	}
+
+	expected := make([]string, 0)
+	err = filepath.Walk(golden, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(golden, path)
+		expected = append(expected, filepath.ToSlash(name))
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, files.Names())
+
+	for _, name := range files.Names() {
+		content, err := ioutil.ReadFile(filepath.Join(golden, filepath.FromSlash(name)))
+		require.NoError(t, err)
+		assert.Equal(t, string(content), files[name], name)
+	}
+}
+
+func TestGenerateVersion(t *testing.T) {
+	project := &types.Project{ID: "Solo", Name: "Solo"}
+	files, err := Generate(project, nil, Options{Version: "2018.2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Solo/Project.kt", "settings.kts"}, files.Names())
+	assert.Equal(t, "import jetbrains.buildServer.configs.kotlin.v2018_2.*\n\nversion = \"2018.2\"\n\nproject(Solo.Project)\n", files["settings.kts"])
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate(&types.Project{ID: "My-Project"}, nil, Options{})
+	assert.EqualError(t, err, `project ID "My-Project" is not a valid Kotlin identifier`)
+
+	roots := []types.VcsRoot{{ID: "Other_Git", ProjectID: "Other"}}
+	_, err = Generate(&types.Project{ID: "App"}, roots, Options{})
+	assert.EqualError(t, err, "vcs root Other_Git belongs to project Other outside the tree")
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"a \"b\" \$c\\d\n"`, quote("a \"b\" $c\\d\n"))
+}
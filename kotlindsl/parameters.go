@@ -0,0 +1,121 @@
+package kotlindsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+const secureValueComment = "// secure value was not exported, set a credentialsJSON token"
+
+func displayName(display types.Display) string {
+	if display == types.Hidden {
+		return "ParameterDisplay.HIDDEN"
+	}
+	if display == types.Prompt {
+		return "ParameterDisplay.PROMPT"
+	}
+	return "ParameterDisplay.NORMAL"
+}
+
+// specArguments returns the named arguments shared by all typed parameters
+func specArguments(spec *types.ParameterSpec) []string {
+	args := make([]string, 0)
+	if spec.Label != "" {
+		args = append(args, "label = "+quote(spec.Label))
+	}
+	if spec.Description != "" {
+		args = append(args, "description = "+quote(spec.Description))
+	}
+	if spec.Display != types.Normal {
+		args = append(args, "display = "+displayName(spec.Display))
+	}
+	if spec.ReadOnly {
+		args = append(args, "readOnly = true")
+	}
+	return args
+}
+
+func parameterCall(name string, parameter types.Parameter) string {
+	args := []string{quote(name), quote(parameter.Value)}
+	spec := parameter.Spec
+	if spec == nil {
+		return fmt.Sprintf("param(%s)", strings.Join(args, ", "))
+	}
+	args = append(args, specArguments(spec)...)
+
+	function := "param"
+	switch t := spec.Type.(type) {
+	case types.PasswordType:
+		function = "password"
+	case types.TextType:
+		function = "text"
+		switch t.ValidationMode {
+		case types.ValidationModeNotEmpty:
+			args = append(args, "allowEmpty = false")
+		case types.ValidationModeRegex:
+			args = append(args, "regex = "+quote(t.Regex))
+		default:
+			args = append(args, "allowEmpty = true")
+		}
+		if t.ValidationMessage != "" {
+			args = append(args, "regexFailureMessage = "+quote(t.ValidationMessage))
+		}
+	case types.CheckboxType:
+		function = "checkbox"
+		if t.Checked != "" {
+			args = append(args, "checked = "+quote(t.Checked))
+		}
+		if t.Unchecked != "" {
+			args = append(args, "unchecked = "+quote(t.Unchecked))
+		}
+	case types.SelectType:
+		function = "select"
+		if t.AllowMultiple {
+			args = append(args, "allowMultiple = true")
+			if t.ValueSeparator != "" {
+				args = append(args, "valueSeparator = "+quote(t.ValueSeparator))
+			}
+		}
+		options := make([]string, 0, len(t.Items))
+		for _, item := range t.Items {
+			if item.Label != "" && item.Label != item.Value {
+				options = append(options, fmt.Sprintf("%s to %s", quote(item.Label), quote(item.Value)))
+			} else {
+				options = append(options, quote(item.Value))
+			}
+		}
+		args = append(args, fmt.Sprintf("options = listOf(%s)", strings.Join(options, ", ")))
+	default:
+		// The DSL has no helper for this type, so only the value is kept
+		return fmt.Sprintf("param(%s, %s) // spec: %s", quote(name), quote(parameter.Value), spec.String())
+	}
+	return fmt.Sprintf("%s(%s)", function, strings.Join(args, ", "))
+}
+
+func writeParams(w *writer, parameters types.Parameters) {
+	if len(parameters) == 0 {
+		return
+	}
+	w.line("")
+	w.block("params {", "", func() {
+		for _, parameter := range parameters.Ordered() {
+			if parameter.IsSecure() && parameter.Value == "" {
+				w.line(secureValueComment)
+			}
+			w.line("%s", parameterCall(parameter.Name, parameter.Parameter))
+		}
+	})
+}
+
+// writeProperties writes param calls for the properties of a step, trigger,
+// feature or VCS root in name order
+func writeProperties(w *writer, properties types.Properties) {
+	for _, property := range properties.Ordered() {
+		if types.IsSecureProperty(property.Name) && property.Value == "" {
+			w.line(secureValueComment)
+		}
+		w.line("param(%s, %s)", quote(property.Name), quote(property.Value))
+	}
+}
@@ -0,0 +1,22 @@
+package App
+
+import jetbrains.buildServer.configs.kotlin.v2019_2.*
+
+object Project : Project({
+    id("App")
+    name = "App"
+    description = "The application"
+
+    vcsRoot(App.vcsRoots.App_Git)
+
+    template(App.buildTypes.App_Base)
+
+    buildType(App.buildTypes.App_Lib)
+    buildType(App.buildTypes.App_Service)
+
+    params {
+        param("env.REGISTRY", "registry.example.com")
+    }
+
+    subProject(App_Docs.Project)
+})
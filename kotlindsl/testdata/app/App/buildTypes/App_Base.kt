@@ -0,0 +1,30 @@
+package App.buildTypes
+
+import jetbrains.buildServer.configs.kotlin.v2019_2.*
+
+object App_Base : Template({
+    id("App_Base")
+    name = "Base"
+
+    vcs {
+        checkoutMode = CheckoutMode.ON_AGENT
+        cleanCheckout = true
+    }
+
+    params {
+        param("env.GOFLAGS", "-mod=vendor")
+    }
+
+    features {
+        feature {
+            id = "swabra"
+            type = "swabra"
+            param("swabra.enabled", "swabra.before.build")
+        }
+    }
+
+    requirements {
+        exists("env.GOROOT")
+        // disabled: doesNotEqual("teamcity.agent.jvm.os.name", "Windows 10")
+    }
+})
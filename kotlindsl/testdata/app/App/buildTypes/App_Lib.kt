@@ -0,0 +1,24 @@
+package App.buildTypes
+
+import jetbrains.buildServer.configs.kotlin.v2019_2.*
+
+object App_Lib : BuildType({
+    id("App_Lib")
+    name = "Lib"
+    artifactRules = "bin => lib.zip"
+
+    vcs {
+        root(App.vcsRoots.App_Git, "+:lib")
+    }
+
+    steps {
+        step {
+            name = "Build"
+            id = "RUNNER_1"
+            type = "simpleRunner"
+            param("script.content", "go build -o bin/ ./...\necho \"\$HOME\"")
+            param("teamcity.step.mode", "default")
+            param("use.custom.script", "true")
+        }
+    }
+})
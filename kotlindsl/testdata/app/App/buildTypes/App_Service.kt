@@ -0,0 +1,78 @@
+package App.buildTypes
+
+import jetbrains.buildServer.configs.kotlin.v2019_2.*
+
+object App_Service : BuildType({
+    templates(App.buildTypes.App_Base)
+    id("App_Service")
+    name = "Service"
+    description = "Builds and tests the service"
+    buildNumberPattern = "1.0.%build.counter%"
+    maxRunningBuilds = 2
+
+    vcs {
+        root(App.vcsRoots.App_Git)
+        root(AbsoluteId("Shared_Tools"), "+:. => tools")
+    }
+
+    params {
+        checkbox("deploy", "false", label = "Deploy", checked = "true", unchecked = "false")
+        select("region", "eu", display = ParameterDisplay.PROMPT, options = listOf("Europe" to "eu", "us"))
+        // secure value was not exported, set a credentialsJSON token
+        password("token", "", display = ParameterDisplay.HIDDEN)
+        text("version", "", description = "Semantic version", regex = "\\d+\\.\\d+", regexFailureMessage = "Use major.minor")
+    }
+
+    steps {
+        step {
+            name = "Test"
+            id = "RUNNER_2"
+            type = "Maven2"
+            enabled = false
+            param("goals", "test")
+        }
+    }
+
+    triggers {
+        trigger {
+            id = "vcsTrigger"
+            type = "vcsTrigger"
+            param("branchFilter", "+:*")
+        }
+    }
+
+    failureConditions {
+        executionTimeoutMin = 30
+    }
+
+    dependencies {
+        dependency(App.buildTypes.App_Lib) {
+            snapshot {
+                onDependencyFailure = FailureAction.FAIL_TO_START
+                onDependencyCancel = FailureAction.ADD_PROBLEM
+                runOnSameAgent = false
+                reuseBuilds = ReuseBuilds.SUCCESSFUL
+            }
+            artifacts {
+                id = "ARTIFACT_DEPENDENCY_1"
+                buildRule = sameChainOrLastFinished()
+                cleanDestination = true
+                artifactRules = "lib.zip!** => lib"
+            }
+        }
+        dependency(AbsoluteId("Shared_Tools_Build")) {
+            artifacts {
+                id = "ARTIFACT_DEPENDENCY_2"
+                buildRule = lastSuccessful("<default>")
+                artifactRules = "tools.zip"
+            }
+        }
+    }
+
+    requirements {
+        moreThanVer("docker.version", "18")
+        // requirement custom-check("gpu") has no DSL function
+    }
+
+    // setting shouldFailBuildOnAnyErrorMessage = "true" has no DSL property
+})
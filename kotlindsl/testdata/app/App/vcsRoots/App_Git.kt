@@ -0,0 +1,13 @@
+package App.vcsRoots
+
+import jetbrains.buildServer.configs.kotlin.v2019_2.*
+
+object App_Git : VcsRoot({
+    id("App_Git")
+    name = "app"
+    type = "jetbrains.git"
+    param("branch", "refs/heads/master")
+    // secure value was not exported, set a credentialsJSON token
+    param("secure:password", "")
+    param("url", "https://example.com/app.git")
+})
@@ -0,0 +1,8 @@
+package App_Docs
+
+import jetbrains.buildServer.configs.kotlin.v2019_2.*
+
+object Project : Project({
+    id("App_Docs")
+    name = "Docs"
+})
@@ -0,0 +1,5 @@
+import jetbrains.buildServer.configs.kotlin.v2019_2.*
+
+version = "2019.2"
+
+project(App.Project)
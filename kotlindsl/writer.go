@@ -0,0 +1,55 @@
+package kotlindsl
+
+import (
+	"fmt"
+	"strings"
+)
+
+const indentUnit = "    "
+
+// writer builds Kotlin source with consistent indentation
+type writer struct {
+	buf    strings.Builder
+	indent int
+}
+
+func (w *writer) line(format string, args ...interface{}) {
+	text := fmt.Sprintf(format, args...)
+	if text == "" {
+		w.buf.WriteString("\n")
+		return
+	}
+	w.buf.WriteString(strings.Repeat(indentUnit, w.indent))
+	w.buf.WriteString(text)
+	w.buf.WriteString("\n")
+}
+
+// block writes header, the body indented one level and a closing brace.
+// closing defaults to "}".
+func (w *writer) block(header string, closing string, body func()) {
+	w.line("%s", header)
+	w.indent++
+	body()
+	w.indent--
+	if closing == "" {
+		closing = "}"
+	}
+	w.line("%s", closing)
+}
+
+func (w *writer) String() string {
+	return w.buf.String()
+}
+
+// quote renders s as a Kotlin string literal
+func quote(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return `"` + r.Replace(s) + `"`
+}
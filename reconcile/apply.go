@@ -0,0 +1,76 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Failure is a change the server rejected
+type Failure struct {
+	Change Change
+	Err    error
+}
+
+// Result reports what happened to each change of a plan. Changes depending
+// on a failed change are skipped rather than attempted.
+type Result struct {
+	Applied []Change
+	Failed  []Failure
+	Skipped []Change
+}
+
+// ApplyError is returned by Apply when some changes could not be made. The
+// changes listed in Result.Applied are in effect on the server.
+type ApplyError struct {
+	Result *Result
+}
+
+func (e *ApplyError) Error() string {
+	messages := make([]string, 0, len(e.Result.Failed))
+	for _, failure := range e.Result.Failed {
+		messages = append(messages, fmt.Sprintf("%s %s %s: %s",
+			failure.Change.Action, failure.Change.Kind, failure.Change.ID, failure.Err))
+	}
+	return fmt.Sprintf("%d of %d changes failed, %d skipped: %s",
+		len(e.Result.Failed), len(e.Result.Applied)+len(e.Result.Failed)+len(e.Result.Skipped),
+		len(e.Result.Skipped), strings.Join(messages, "; "))
+}
+
+// Apply performs the changes in order. It carries on past failures with the
+// changes that do not depend on them, and returns an *ApplyError describing
+// the outcome if anything failed.
+func (p *Plan) Apply(client Client) (*Result, error) {
+	result := &Result{
+		Applied: make([]Change, 0),
+		Failed:  make([]Failure, 0),
+		Skipped: make([]Change, 0),
+	}
+	broken := make(map[string]bool)
+
+	for _, change := range p.Changes {
+		blocked := false
+		for _, key := range change.DependsOn {
+			if broken[key] {
+				blocked = true
+				break
+			}
+		}
+		if blocked {
+			broken[change.Key()] = true
+			result.Skipped = append(result.Skipped, change)
+			continue
+		}
+
+		if err := change.apply(client); err != nil {
+			broken[change.Key()] = true
+			result.Failed = append(result.Failed, Failure{Change: change, Err: err})
+			continue
+		}
+		result.Applied = append(result.Applied, change)
+	}
+
+	if len(result.Failed) > 0 {
+		return result, &ApplyError{Result: result}
+	}
+	return result, nil
+}
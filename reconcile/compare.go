@@ -0,0 +1,304 @@
+package reconcile
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// TeamCity never returns secret values, so a secure property or password
+// parameter that comes back empty is taken to match whatever is desired.
+
+func sameProperties(desired, actual types.Properties) bool {
+	if len(desired) != len(actual) {
+		return false
+	}
+	for name, want := range desired {
+		got, ok := actual[name]
+		if !ok {
+			return false
+		}
+		if want != got && !(got == "" && types.IsSecureProperty(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameParameters(desired, actual types.Parameters) bool {
+	if len(desired) != len(actual) {
+		return false
+	}
+	for name, want := range desired {
+		got, ok := actual[name]
+		if !ok {
+			return false
+		}
+		if want.SpecString() != got.SpecString() {
+			return false
+		}
+		if want.Value != got.Value && !(got.Value == "" && got.IsSecure()) {
+			return false
+		}
+	}
+	return true
+}
+
+// itemKey renders a step, trigger, feature, dependency or requirement without
+// its server assigned ID so that lists can be compared
+func itemKey(kind, name, source string, disabled bool, properties types.Properties) string {
+	names := make([]string, 0, len(properties))
+	for property := range properties {
+		names = append(names, property)
+	}
+	sort.Strings(names)
+
+	key := fmt.Sprintf("%s|%s|%s|%t", kind, name, source, disabled)
+	for _, property := range names {
+		value := properties[property]
+		if types.IsSecureProperty(property) {
+			value = ""
+		}
+		key += fmt.Sprintf("|%s=%s", property, value)
+	}
+	return key
+}
+
+func sameKeys(desired, actual []string) bool {
+	if len(desired) != len(actual) {
+		return false
+	}
+	for idx := range desired {
+		if desired[idx] != actual[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+func stepKeys(steps types.BuildSteps) []string {
+	keys := make([]string, 0, len(steps))
+	for _, step := range steps {
+		keys = append(keys, itemKey(step.Type, step.Name, "", step.Disabled, step.Properties))
+	}
+	return keys
+}
+
+func triggerKeys(triggers types.BuildTriggers) []string {
+	keys := make([]string, 0, len(triggers))
+	for _, trigger := range triggers {
+		keys = append(keys, itemKey(trigger.Type, "", "", trigger.Disabled, trigger.Properties))
+	}
+	return keys
+}
+
+func featureKeys(features types.BuildFeatures) []string {
+	keys := make([]string, 0, len(features))
+	for _, feature := range features {
+		keys = append(keys, itemKey(feature.Type, "", "", feature.Disabled, feature.Properties))
+	}
+	return keys
+}
+
+func snapshotDependencyKeys(dependencies types.BuildSnapshotDependencies) []string {
+	keys := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		keys = append(keys, itemKey(dependency.Type, "", dependency.SourceBuildType.ID, dependency.Disabled, dependency.Properties))
+	}
+	return keys
+}
+
+func artifactDependencyKeys(dependencies types.BuildArtifactDependencies) []string {
+	keys := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		keys = append(keys, itemKey(dependency.Type, "", dependency.SourceBuildType.ID, dependency.Disabled, dependency.Properties))
+	}
+	return keys
+}
+
+func agentRequirementKeys(requirements types.BuildAgentRequirements) []string {
+	keys := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		keys = append(keys, itemKey(requirement.Type, "", "", requirement.Disabled, requirement.Properties))
+	}
+	return keys
+}
+
+func sameTemplates(desired, actual types.TemplateIds) bool {
+	if len(desired) != len(actual) {
+		return false
+	}
+	for idx := range desired {
+		if desired[idx] != actual[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// section is one part of a resource that differs, with the calls that bring
+// it in line
+type section struct {
+	name  string
+	apply func(Client) error
+}
+
+func compareBuildConfiguration(desired, actual types.BuildConfiguration) []section {
+	id := desired.ID
+	own := actual.OwnSettings()
+	sections := make([]section, 0)
+
+	if desired.Name != actual.Name {
+		sections = append(sections, section{"name", func(c Client) error {
+			return c.ReplaceBuildConfigurationField(id, "name", desired.Name)
+		}})
+	}
+	if desired.ProjectID != actual.ProjectID {
+		sections = append(sections, section{"project", func(c Client) error {
+			return c.MoveBuildConfiguration(id, desired.ProjectID)
+		}})
+	}
+	if desired.Description != actual.Description {
+		sections = append(sections, section{"description", func(c Client) error {
+			return c.SetBuildConfigurationDescription(id, desired.Description)
+		}})
+	}
+	if templates := desired.AttachedTemplates(); !desired.TemplateFlag && !sameTemplates(templates, actual.AttachedTemplates()) {
+		sections = append(sections, section{"templates", func(c Client) error {
+			return c.ReplaceAllBuildConfigurationTemplates(id, &templates)
+		}})
+	}
+	if s, ok := compareVcsRootEntries(id, desired.VcsRootEntries, actual.VcsRootEntries); ok {
+		sections = append(sections, s)
+	}
+	if s, ok := compareSettings(id, desired.Settings, actual.Settings); ok {
+		sections = append(sections, s)
+	}
+	if !sameParameters(desired.Parameters, own.Parameters) {
+		sections = append(sections, section{"parameters", func(c Client) error {
+			return c.ReplaceAllBuildConfigurationParameters(id, &desired.Parameters)
+		}})
+	}
+	if !sameKeys(stepKeys(desired.Steps), stepKeys(own.Steps)) {
+		sections = append(sections, section{"steps", func(c Client) error {
+			return c.ReplaceAllBuildConfigurationSteps(id, &desired.Steps)
+		}})
+	}
+	if !sameKeys(triggerKeys(desired.Triggers), triggerKeys(own.Triggers)) {
+		sections = append(sections, section{"triggers", func(c Client) error {
+			return c.ReplaceAllBuildConfigurationTriggers(id, &desired.Triggers)
+		}})
+	}
+	if !sameKeys(featureKeys(desired.Features), featureKeys(own.Features)) {
+		sections = append(sections, section{"features", func(c Client) error {
+			return c.ReplaceAllBuildConfigurationFeatures(id, &desired.Features)
+		}})
+	}
+	if !sameKeys(snapshotDependencyKeys(desired.SnapshotDependencies), snapshotDependencyKeys(own.SnapshotDependencies)) {
+		sections = append(sections, section{"snapshot dependencies", func(c Client) error {
+			return c.ReplaceAllBuildConfigurationSnapshotDependencies(id, &desired.SnapshotDependencies)
+		}})
+	}
+	if !sameKeys(artifactDependencyKeys(desired.ArtifactDependencies), artifactDependencyKeys(own.ArtifactDependencies)) {
+		sections = append(sections, section{"artifact dependencies", func(c Client) error {
+			return c.ReplaceAllBuildConfigurationArtifactDependencies(id, &desired.ArtifactDependencies)
+		}})
+	}
+	if !sameKeys(agentRequirementKeys(desired.AgentRequirements), agentRequirementKeys(own.AgentRequirements)) {
+		sections = append(sections, section{"agent requirements", func(c Client) error {
+			return c.ReplaceAllBuildConfigurationAgentRequirements(id, &desired.AgentRequirements)
+		}})
+	}
+	return sections
+}
+
+// compareVcsRootEntries detaches entries that are gone or whose checkout
+// rules changed and attaches the desired ones that are missing
+func compareVcsRootEntries(id string, desired, actual types.VcsRootEntries) (section, bool) {
+	current := make(map[types.VcsRootId]types.VcsRootEntry)
+	for _, entry := range actual {
+		current[entry.VcsRootID] = entry
+	}
+	wanted := make(map[types.VcsRootId]bool)
+	detach := make([]string, 0)
+	attach := make(types.VcsRootEntries, 0)
+	for _, entry := range desired {
+		wanted[entry.VcsRootID] = true
+		existing, ok := current[entry.VcsRootID]
+		if ok && existing.CheckoutRules == entry.CheckoutRules {
+			continue
+		}
+		if ok {
+			detach = append(detach, string(entry.VcsRootID))
+		}
+		attach = append(attach, entry)
+	}
+	for _, entry := range actual {
+		if !wanted[entry.VcsRootID] {
+			detach = append(detach, string(entry.VcsRootID))
+		}
+	}
+	if len(detach) == 0 && len(attach) == 0 {
+		return section{}, false
+	}
+	return section{"vcs roots", func(c Client) error {
+		for _, vcsRootID := range detach {
+			if err := c.DetachBuildConfigurationVcsRoot(id, vcsRootID); err != nil {
+				return err
+			}
+		}
+		for idx := range attach {
+			entry := attach[idx]
+			if err := c.AttachBuildConfigurationVcsRoot(id, &entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}, true
+}
+
+// compareSettings only looks at the settings that are desired, since the
+// server reports every setting including defaults
+func compareSettings(id string, desired, actual types.BuildSettings) (section, bool) {
+	current := make(map[string]string)
+	for _, setting := range actual {
+		current[setting.Name] = setting.Value
+	}
+	changed := make(types.BuildSettings, 0)
+	for _, setting := range desired {
+		if value, ok := current[setting.Name]; !ok || value != setting.Value {
+			changed = append(changed, setting)
+		}
+	}
+	if len(changed) == 0 {
+		return section{}, false
+	}
+	return section{"settings", func(c Client) error {
+		for _, setting := range changed {
+			if err := c.ReplaceBuildConfigurationSetting(id, setting.Name, setting.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}, true
+}
+
+func sectionNames(sections []section) []string {
+	names := make([]string, 0, len(sections))
+	for _, s := range sections {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+func applySections(sections []section) func(Client) error {
+	return func(c Client) error {
+		for _, s := range sections {
+			if err := s.apply(c); err != nil {
+				return fmt.Errorf("%s: %s", s.name, err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,312 @@
+package reconcile
+
+import (
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// fakeClient keeps projects, VCS roots and build configurations in memory and
+// records every modifying call. Calls listed in fail return that error.
+type fakeClient struct {
+	projects map[string]types.Project
+	vcsRoots map[string]types.VcsRoot
+	configs  map[string]types.BuildConfiguration
+	calls    []string
+	fail     map[string]error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		projects: make(map[string]types.Project),
+		vcsRoots: make(map[string]types.VcsRoot),
+		configs:  make(map[string]types.BuildConfiguration),
+		calls:    make([]string, 0),
+		fail:     make(map[string]error),
+	}
+}
+
+func (f *fakeClient) call(name, id string) error {
+	call := name + " " + id
+	f.calls = append(f.calls, call)
+	return f.fail[call]
+}
+
+func (f *fakeClient) GetProject(projectID string) (*types.Project, error) {
+	project, ok := f.projects[projectID]
+	if !ok {
+		return nil, nil
+	}
+	project.Projects = make(types.Projects)
+	for id, child := range f.projects {
+		if string(child.ParentProjectID) == projectID {
+			project.Projects[id] = child
+		}
+	}
+	project.BuildConfigurations = make(types.BuildConfigurations)
+	project.Templates = make(types.BuildConfigurations)
+	for id, config := range f.configs {
+		if config.ProjectID != projectID {
+			continue
+		}
+		if config.TemplateFlag {
+			project.Templates[id] = config
+		} else {
+			project.BuildConfigurations[id] = config
+		}
+	}
+	return &project, nil
+}
+
+func (f *fakeClient) CreateProject(project *types.Project) error {
+	if err := f.call("CreateProject", project.ID); err != nil {
+		return err
+	}
+	f.projects[project.ID] = *project
+	return nil
+}
+
+func (f *fakeClient) DeleteProject(projectID string) error {
+	if err := f.call("DeleteProject", projectID); err != nil {
+		return err
+	}
+	delete(f.projects, projectID)
+	return nil
+}
+
+func (f *fakeClient) MoveProject(projectID, parentProjectID string) error {
+	if err := f.call("MoveProject", projectID); err != nil {
+		return err
+	}
+	project := f.projects[projectID]
+	project.ParentProjectID = types.ProjectId(parentProjectID)
+	f.projects[projectID] = project
+	return nil
+}
+
+func (f *fakeClient) SetProjectField(projectID, field string, content string) error {
+	if err := f.call("SetProjectField", projectID); err != nil {
+		return err
+	}
+	project := f.projects[projectID]
+	if field == "name" {
+		project.Name = content
+	}
+	f.projects[projectID] = project
+	return nil
+}
+
+func (f *fakeClient) SetProjectDescription(projectID, description string) error {
+	if err := f.call("SetProjectDescription", projectID); err != nil {
+		return err
+	}
+	project := f.projects[projectID]
+	project.Description = description
+	f.projects[projectID] = project
+	return nil
+}
+
+func (f *fakeClient) ReplaceAllProjectParameters(projectID string, parameters *types.Parameters) error {
+	if err := f.call("ReplaceAllProjectParameters", projectID); err != nil {
+		return err
+	}
+	project := f.projects[projectID]
+	project.Parameters = *parameters
+	f.projects[projectID] = project
+	return nil
+}
+
+func (f *fakeClient) GetVcsRoot(vcsRootID string) (*types.VcsRoot, error) {
+	root, ok := f.vcsRoots[vcsRootID]
+	if !ok {
+		return nil, nil
+	}
+	return &root, nil
+}
+
+func (f *fakeClient) GetProjectVcsRoots(projectID string) ([]types.VcsRoot, error) {
+	roots := make([]types.VcsRoot, 0)
+	for _, root := range f.vcsRoots {
+		if string(root.ProjectID) == projectID {
+			roots = append(roots, root)
+		}
+	}
+	return roots, nil
+}
+
+func (f *fakeClient) CreateVcsRoot(vcs *types.VcsRoot) error {
+	if err := f.call("CreateVcsRoot", vcs.ID); err != nil {
+		return err
+	}
+	f.vcsRoots[vcs.ID] = *vcs
+	return nil
+}
+
+func (f *fakeClient) DeleteVcsRoot(vcsRootID string) error {
+	if err := f.call("DeleteVcsRoot", vcsRootID); err != nil {
+		return err
+	}
+	delete(f.vcsRoots, vcsRootID)
+	return nil
+}
+
+func (f *fakeClient) SetVcsRootName(vcsRootID, name string) error {
+	if err := f.call("SetVcsRootName", vcsRootID); err != nil {
+		return err
+	}
+	root := f.vcsRoots[vcsRootID]
+	root.Name = name
+	f.vcsRoots[vcsRootID] = root
+	return nil
+}
+
+func (f *fakeClient) SetVcsRootProject(vcsRootID, projectID string) error {
+	if err := f.call("SetVcsRootProject", vcsRootID); err != nil {
+		return err
+	}
+	root := f.vcsRoots[vcsRootID]
+	root.ProjectID = types.ProjectId(projectID)
+	f.vcsRoots[vcsRootID] = root
+	return nil
+}
+
+func (f *fakeClient) ReplaceAllVcsRootProperties(vcsRootID string, properties *types.Properties) error {
+	if err := f.call("ReplaceAllVcsRootProperties", vcsRootID); err != nil {
+		return err
+	}
+	root := f.vcsRoots[vcsRootID]
+	root.Properties = *properties
+	f.vcsRoots[vcsRootID] = root
+	return nil
+}
+
+func (f *fakeClient) GetBuildConfiguration(buildConfID string) (*types.BuildConfiguration, error) {
+	config, ok := f.configs[buildConfID]
+	if !ok {
+		return nil, nil
+	}
+	return &config, nil
+}
+
+func (f *fakeClient) CreateBuildConfiguration(buildConfig *types.BuildConfiguration) error {
+	if err := f.call("CreateBuildConfiguration", buildConfig.ID); err != nil {
+		return err
+	}
+	f.configs[buildConfig.ID] = *buildConfig
+	return nil
+}
+
+func (f *fakeClient) DeleteBuildConfiguration(buildConfID string) error {
+	if err := f.call("DeleteBuildConfiguration", buildConfID); err != nil {
+		return err
+	}
+	delete(f.configs, buildConfID)
+	return nil
+}
+
+// updateConfig records a call and applies update to the stored configuration
+func (f *fakeClient) updateConfig(name, buildConfID string, update func(*types.BuildConfiguration)) error {
+	if err := f.call(name, buildConfID); err != nil {
+		return err
+	}
+	config := f.configs[buildConfID]
+	update(&config)
+	f.configs[buildConfID] = config
+	return nil
+}
+
+func (f *fakeClient) MoveBuildConfiguration(buildConfID, targetProjectID string) error {
+	return f.updateConfig("MoveBuildConfiguration", buildConfID, func(b *types.BuildConfiguration) {
+		b.ProjectID = targetProjectID
+	})
+}
+
+func (f *fakeClient) ReplaceBuildConfigurationField(buildConfID, name string, value string) error {
+	return f.updateConfig("ReplaceBuildConfigurationField", buildConfID, func(b *types.BuildConfiguration) {
+		if name == "name" {
+			b.Name = value
+		}
+	})
+}
+
+func (f *fakeClient) SetBuildConfigurationDescription(buildConfID, description string) error {
+	return f.updateConfig("SetBuildConfigurationDescription", buildConfID, func(b *types.BuildConfiguration) {
+		b.Description = description
+	})
+}
+
+func (f *fakeClient) ReplaceBuildConfigurationSetting(buildConfID, name string, value string) error {
+	return f.updateConfig("ReplaceBuildConfigurationSetting", buildConfID, func(b *types.BuildConfiguration) {
+		settings := make(types.BuildSettings, 0)
+		for _, setting := range b.Settings {
+			if setting.Name != name {
+				settings = append(settings, setting)
+			}
+		}
+		b.Settings = append(settings, types.BuildSetting{Name: name, Value: value})
+	})
+}
+
+func (f *fakeClient) ReplaceAllBuildConfigurationTemplates(buildConfID string, templates *types.TemplateIds) error {
+	return f.updateConfig("ReplaceAllBuildConfigurationTemplates", buildConfID, func(b *types.BuildConfiguration) {
+		b.Templates = *templates
+	})
+}
+
+func (f *fakeClient) AttachBuildConfigurationVcsRoot(buildConfID string, vcsRoot *types.VcsRootEntry) error {
+	return f.updateConfig("AttachBuildConfigurationVcsRoot", buildConfID, func(b *types.BuildConfiguration) {
+		b.VcsRootEntries = append(b.VcsRootEntries, *vcsRoot)
+	})
+}
+
+func (f *fakeClient) DetachBuildConfigurationVcsRoot(buildConfID string, vcsRootID string) error {
+	return f.updateConfig("DetachBuildConfigurationVcsRoot", buildConfID, func(b *types.BuildConfiguration) {
+		entries := make(types.VcsRootEntries, 0)
+		for _, entry := range b.VcsRootEntries {
+			if string(entry.VcsRootID) != vcsRootID {
+				entries = append(entries, entry)
+			}
+		}
+		b.VcsRootEntries = entries
+	})
+}
+
+func (f *fakeClient) ReplaceAllBuildConfigurationParameters(buildConfID string, parameters *types.Parameters) error {
+	return f.updateConfig("ReplaceAllBuildConfigurationParameters", buildConfID, func(b *types.BuildConfiguration) {
+		b.Parameters = *parameters
+	})
+}
+
+func (f *fakeClient) ReplaceAllBuildConfigurationSteps(buildConfID string, steps *types.BuildSteps) error {
+	return f.updateConfig("ReplaceAllBuildConfigurationSteps", buildConfID, func(b *types.BuildConfiguration) {
+		b.Steps = *steps
+	})
+}
+
+func (f *fakeClient) ReplaceAllBuildConfigurationTriggers(buildConfID string, triggers *types.BuildTriggers) error {
+	return f.updateConfig("ReplaceAllBuildConfigurationTriggers", buildConfID, func(b *types.BuildConfiguration) {
+		b.Triggers = *triggers
+	})
+}
+
+func (f *fakeClient) ReplaceAllBuildConfigurationFeatures(buildConfID string, features *types.BuildFeatures) error {
+	return f.updateConfig("ReplaceAllBuildConfigurationFeatures", buildConfID, func(b *types.BuildConfiguration) {
+		b.Features = *features
+	})
+}
+
+func (f *fakeClient) ReplaceAllBuildConfigurationSnapshotDependencies(buildConfID string, snapshotDependencies *types.BuildSnapshotDependencies) error {
+	return f.updateConfig("ReplaceAllBuildConfigurationSnapshotDependencies", buildConfID, func(b *types.BuildConfiguration) {
+		b.SnapshotDependencies = *snapshotDependencies
+	})
+}
+
+func (f *fakeClient) ReplaceAllBuildConfigurationArtifactDependencies(buildConfID string, artifactDependencies *types.BuildArtifactDependencies) error {
+	return f.updateConfig("ReplaceAllBuildConfigurationArtifactDependencies", buildConfID, func(b *types.BuildConfiguration) {
+		b.ArtifactDependencies = *artifactDependencies
+	})
+}
+
+func (f *fakeClient) ReplaceAllBuildConfigurationAgentRequirements(buildConfID string, agentRequirements *types.BuildAgentRequirements) error {
+	return f.updateConfig("ReplaceAllBuildConfigurationAgentRequirements", buildConfID, func(b *types.BuildConfiguration) {
+		b.AgentRequirements = *agentRequirements
+	})
+}
@@ -0,0 +1,129 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Action is what a change does to a resource
+type Action int
+
+const (
+	Create Action = iota
+	Update
+	Delete
+)
+
+func (a Action) String() string {
+	if a == Create {
+		return "create"
+	}
+	if a == Update {
+		return "update"
+	}
+	return "delete"
+}
+
+func (a Action) symbol() string {
+	if a == Create {
+		return "+"
+	}
+	if a == Update {
+		return "~"
+	}
+	return "-"
+}
+
+// ResourceKind is the kind of TeamCity entity a change applies to
+type ResourceKind int
+
+const (
+	ProjectResource ResourceKind = iota
+	VcsRootResource
+	TemplateResource
+	BuildConfigurationResource
+)
+
+func (k ResourceKind) String() string {
+	if k == ProjectResource {
+		return "project"
+	}
+	if k == VcsRootResource {
+		return "vcs root"
+	}
+	if k == TemplateResource {
+		return "template"
+	}
+	return "build configuration"
+}
+
+// Change is a single step of a plan. Details names the parts of the resource
+// that are set, e.g. "parameters" or "steps".
+type Change struct {
+	Action  Action
+	Kind    ResourceKind
+	ID      string
+	Details []string
+	// DependsOn holds the keys of changes that must succeed first
+	DependsOn []string
+
+	apply func(Client) error
+}
+
+func resourceKey(kind ResourceKind, id string) string {
+	return fmt.Sprintf("%s:%s", kind, id)
+}
+
+// Key identifies the resource the change applies to
+func (c Change) Key() string {
+	return resourceKey(c.Kind, c.ID)
+}
+
+func (c Change) String() string {
+	line := fmt.Sprintf("%s %s %s", c.Action.symbol(), c.Kind, c.ID)
+	if len(c.Details) > 0 {
+		line += fmt.Sprintf(" (%s)", strings.Join(c.Details, ", "))
+	}
+	return line
+}
+
+// Plan is the ordered list of changes needed to reach the desired state
+type Plan struct {
+	Changes []Change
+}
+
+// Empty tells whether the server already matches the desired state
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count returns the number of changes with the given action
+func (p *Plan) Count(action Action) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the plan for review, one change per line followed by a
+// summary
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes."
+	}
+	var out strings.Builder
+	for _, change := range p.Changes {
+		out.WriteString(change.String())
+		out.WriteString("\n")
+	}
+	fmt.Fprintf(&out, "Plan: %d to create, %d to update, %d to delete.",
+		p.Count(Create), p.Count(Update), p.Count(Delete))
+	return out.String()
+}
+
+func (p *Plan) add(change Change) {
+	p.Changes = append(p.Changes, change)
+}
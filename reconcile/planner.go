@@ -0,0 +1,478 @@
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+type desiredProject struct {
+	project  types.Project
+	parentID string
+	existing *types.Project
+}
+
+type planner struct {
+	client   Client
+	options  Options
+	plan     *Plan
+	projects []*desiredProject
+	// desired IDs of every kind, used to avoid pruning resources that only
+	// moved within the desired state
+	projectIDs     map[string]bool
+	vcsRootIDs     map[string]bool
+	buildConfigIDs map[string]bool
+}
+
+// NewPlan compares the desired state with the server and returns the changes
+// that would bring the server in line. Nothing is modified on the server.
+func NewPlan(client Client, desired *Desired, options Options) (*Plan, error) {
+	p := &planner{
+		client:         client,
+		options:        options,
+		plan:           &Plan{Changes: make([]Change, 0)},
+		projectIDs:     make(map[string]bool),
+		vcsRootIDs:     make(map[string]bool),
+		buildConfigIDs: make(map[string]bool),
+	}
+
+	if err := p.collectProjects(desired.Project, string(desired.Project.ParentProjectID)); err != nil {
+		return nil, err
+	}
+	for _, root := range desired.VcsRoots {
+		if root.ID == "" {
+			return nil, errors.New("desired vcs root without ID")
+		}
+		p.vcsRootIDs[root.ID] = true
+	}
+
+	if err := p.planProjects(); err != nil {
+		return nil, err
+	}
+	if err := p.planVcsRoots(desired.VcsRoots); err != nil {
+		return nil, err
+	}
+	if err := p.planBuildConfigurations(); err != nil {
+		return nil, err
+	}
+	if options.Prune {
+		if err := p.planDeletions(); err != nil {
+			return nil, err
+		}
+	}
+	return p.plan, nil
+}
+
+// collectProjects flattens the desired tree, parents before children
+func (p *planner) collectProjects(project types.Project, parentID string) error {
+	if project.ID == "" {
+		return fmt.Errorf("desired project %q without ID", project.Name)
+	}
+	if p.projectIDs[project.ID] {
+		return fmt.Errorf("project %s is desired more than once", project.ID)
+	}
+	p.projectIDs[project.ID] = true
+	p.projects = append(p.projects, &desiredProject{project: project, parentID: parentID})
+
+	for _, configs := range []types.BuildConfigurations{project.Templates, project.BuildConfigurations} {
+		for id := range configs {
+			if p.buildConfigIDs[id] {
+				return fmt.Errorf("build configuration %s is desired more than once", id)
+			}
+			p.buildConfigIDs[id] = true
+		}
+	}
+
+	childIDs := make([]string, 0, len(project.Projects))
+	for id := range project.Projects {
+		childIDs = append(childIDs, id)
+	}
+	sort.Strings(childIDs)
+	for _, id := range childIDs {
+		child := project.Projects[id]
+		child.ID = id
+		if err := p.collectProjects(child, project.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *planner) dependsOnProject(projectID string) []string {
+	if !p.projectIDs[projectID] {
+		return []string{}
+	}
+	return []string{resourceKey(ProjectResource, projectID)}
+}
+
+func (p *planner) planProjects() error {
+	for _, dp := range p.projects {
+		existing, err := p.client.GetProject(dp.project.ID)
+		if err != nil {
+			return err
+		}
+		dp.existing = existing
+		project := dp.project
+		parentID := dp.parentID
+		parameters := project.Parameters.Own()
+
+		if existing == nil {
+			details := make([]string, 0)
+			if len(parameters) > 0 {
+				details = append(details, "parameters")
+			}
+			p.plan.add(Change{
+				Action:    Create,
+				Kind:      ProjectResource,
+				ID:        project.ID,
+				Details:   details,
+				DependsOn: p.dependsOnProject(parentID),
+				apply: func(c Client) error {
+					err := c.CreateProject(&types.Project{
+						ID:              project.ID,
+						Name:            project.Name,
+						Description:     project.Description,
+						ParentProjectID: types.ProjectId(parentID),
+					})
+					if err != nil || len(parameters) == 0 {
+						return err
+					}
+					return c.ReplaceAllProjectParameters(project.ID, &parameters)
+				},
+			})
+			continue
+		}
+
+		sections := make([]section, 0)
+		if project.Name != existing.Name {
+			sections = append(sections, section{"name", func(c Client) error {
+				return c.SetProjectField(project.ID, "name", project.Name)
+			}})
+		}
+		if parentID != "" && parentID != string(existing.ParentProjectID) {
+			sections = append(sections, section{"parent", func(c Client) error {
+				return c.MoveProject(project.ID, parentID)
+			}})
+		}
+		if project.Description != existing.Description {
+			sections = append(sections, section{"description", func(c Client) error {
+				return c.SetProjectDescription(project.ID, project.Description)
+			}})
+		}
+		if !sameParameters(parameters, existing.Parameters.Own()) {
+			sections = append(sections, section{"parameters", func(c Client) error {
+				return c.ReplaceAllProjectParameters(project.ID, &parameters)
+			}})
+		}
+		if len(sections) > 0 {
+			p.plan.add(Change{
+				Action:    Update,
+				Kind:      ProjectResource,
+				ID:        project.ID,
+				Details:   sectionNames(sections),
+				DependsOn: p.dependsOnProject(parentID),
+				apply:     applySections(sections),
+			})
+		}
+	}
+	return nil
+}
+
+func (p *planner) planVcsRoots(roots []types.VcsRoot) error {
+	sorted := append([]types.VcsRoot{}, roots...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	for _, root := range sorted {
+		root := root
+		projectID := string(root.ProjectID)
+		existing, err := p.client.GetVcsRoot(root.ID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			p.plan.add(Change{
+				Action:    Create,
+				Kind:      VcsRootResource,
+				ID:        root.ID,
+				DependsOn: p.dependsOnProject(projectID),
+				apply: func(c Client) error {
+					created := root
+					return c.CreateVcsRoot(&created)
+				},
+			})
+			continue
+		}
+
+		sections := make([]section, 0)
+		if root.Name != existing.Name {
+			sections = append(sections, section{"name", func(c Client) error {
+				return c.SetVcsRootName(root.ID, root.Name)
+			}})
+		}
+		if projectID != string(existing.ProjectID) {
+			sections = append(sections, section{"project", func(c Client) error {
+				return c.SetVcsRootProject(root.ID, projectID)
+			}})
+		}
+		if !sameProperties(root.Properties, existing.Properties) {
+			sections = append(sections, section{"properties", func(c Client) error {
+				return c.ReplaceAllVcsRootProperties(root.ID, &root.Properties)
+			}})
+		}
+		if len(sections) > 0 {
+			p.plan.add(Change{
+				Action:    Update,
+				Kind:      VcsRootResource,
+				ID:        root.ID,
+				Details:   sectionNames(sections),
+				DependsOn: p.dependsOnProject(projectID),
+				apply:     applySections(sections),
+			})
+		}
+	}
+	return nil
+}
+
+// orderedBuildConfigurations returns templates first, then build
+// configurations with the sources of their dependencies before them
+func (p *planner) orderedBuildConfigurations() ([]types.BuildConfiguration, error) {
+	templates := make([]types.BuildConfiguration, 0)
+	configs := make(map[string]types.BuildConfiguration)
+	for _, dp := range p.projects {
+		for id, template := range dp.project.Templates {
+			template.ID = id
+			template.ProjectID = dp.project.ID
+			template.TemplateFlag = true
+			templates = append(templates, template)
+		}
+		for id, config := range dp.project.BuildConfigurations {
+			config.ID = id
+			config.ProjectID = dp.project.ID
+			configs[id] = config
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+
+	ids := make([]string, 0, len(configs))
+	for id := range configs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	ordered := templates
+	state := make(map[string]int)
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		if state[id] == 2 {
+			return nil
+		}
+		if state[id] == 1 {
+			return fmt.Errorf("dependency cycle between build configurations: %v", append(path, id))
+		}
+		state[id] = 1
+		for _, source := range dependencySources(configs[id]) {
+			if _, ok := configs[source]; ok {
+				if err := visit(source, append(path, id)); err != nil {
+					return err
+				}
+			}
+		}
+		state[id] = 2
+		ordered = append(ordered, configs[id])
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+func dependencySources(config types.BuildConfiguration) []string {
+	sources := make([]string, 0)
+	for _, dependency := range config.SnapshotDependencies {
+		sources = append(sources, dependency.SourceBuildType.ID)
+	}
+	for _, dependency := range config.ArtifactDependencies {
+		sources = append(sources, dependency.SourceBuildType.ID)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+func (p *planner) buildConfigurationDependencies(config types.BuildConfiguration) []string {
+	keys := p.dependsOnProject(config.ProjectID)
+	for _, templateID := range config.AttachedTemplates() {
+		keys = append(keys, resourceKey(TemplateResource, string(templateID)))
+	}
+	for _, entry := range config.VcsRootEntries {
+		keys = append(keys, resourceKey(VcsRootResource, string(entry.VcsRootID)))
+	}
+	for _, source := range dependencySources(config) {
+		keys = append(keys, resourceKey(BuildConfigurationResource, source))
+	}
+	return keys
+}
+
+func createdSections(config types.BuildConfiguration) []string {
+	details := make([]string, 0)
+	add := func(name string, count int) {
+		if count > 0 {
+			details = append(details, name)
+		}
+	}
+	add("templates", len(config.AttachedTemplates()))
+	add("vcs roots", len(config.VcsRootEntries))
+	add("settings", len(config.Settings))
+	add("parameters", len(config.Parameters))
+	add("steps", len(config.Steps))
+	add("triggers", len(config.Triggers))
+	add("features", len(config.Features))
+	add("snapshot dependencies", len(config.SnapshotDependencies))
+	add("artifact dependencies", len(config.ArtifactDependencies))
+	add("agent requirements", len(config.AgentRequirements))
+	return details
+}
+
+func (p *planner) planBuildConfigurations() error {
+	configs, err := p.orderedBuildConfigurations()
+	if err != nil {
+		return err
+	}
+
+	for _, config := range configs {
+		config := config
+		kind := BuildConfigurationResource
+		if config.TemplateFlag {
+			kind = TemplateResource
+		}
+		existing, err := p.client.GetBuildConfiguration(config.ID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			p.plan.add(Change{
+				Action:    Create,
+				Kind:      kind,
+				ID:        config.ID,
+				Details:   createdSections(config),
+				DependsOn: p.buildConfigurationDependencies(config),
+				apply: func(c Client) error {
+					created := config
+					return c.CreateBuildConfiguration(&created)
+				},
+			})
+			continue
+		}
+
+		sections := compareBuildConfiguration(config, *existing)
+		if len(sections) > 0 {
+			p.plan.add(Change{
+				Action:    Update,
+				Kind:      kind,
+				ID:        config.ID,
+				Details:   sectionNames(sections),
+				DependsOn: p.buildConfigurationDependencies(config),
+				apply:     applySections(sections),
+			})
+		}
+	}
+	return nil
+}
+
+// planDeletions looks inside the desired projects that exist on the server
+// for resources that are no longer desired. Build configurations go first,
+// then templates, VCS roots and finally projects, which takes everything
+// still inside them along.
+func (p *planner) planDeletions() error {
+	deleteConfigs := make([]Change, 0)
+	deleteTemplates := make([]Change, 0)
+	deleteVcsRoots := make([]Change, 0)
+	deleteProjects := make([]Change, 0)
+
+	for _, dp := range p.projects {
+		if dp.existing == nil {
+			continue
+		}
+		for _, id := range sortedKeys(dp.existing.BuildConfigurations) {
+			if !p.buildConfigIDs[id] {
+				deleteConfigs = append(deleteConfigs, deleteBuildConfigurationChange(BuildConfigurationResource, id))
+			}
+		}
+		for _, id := range sortedKeys(dp.existing.Templates) {
+			if !p.buildConfigIDs[id] {
+				deleteTemplates = append(deleteTemplates, deleteBuildConfigurationChange(TemplateResource, id))
+			}
+		}
+
+		roots, err := p.client.GetProjectVcsRoots(dp.project.ID)
+		if err != nil {
+			return err
+		}
+		sort.Slice(roots, func(i, j int) bool { return roots[i].ID < roots[j].ID })
+		for _, root := range roots {
+			if !p.vcsRootIDs[root.ID] {
+				id := root.ID
+				deleteVcsRoots = append(deleteVcsRoots, Change{
+					Action: Delete,
+					Kind:   VcsRootResource,
+					ID:     id,
+					apply: func(c Client) error {
+						return c.DeleteVcsRoot(id)
+					},
+				})
+			}
+		}
+
+		childIDs := make([]string, 0, len(dp.existing.Projects))
+		for id := range dp.existing.Projects {
+			childIDs = append(childIDs, id)
+		}
+		sort.Strings(childIDs)
+		for _, id := range childIDs {
+			if !p.projectIDs[id] {
+				id := id
+				deleteProjects = append(deleteProjects, Change{
+					Action: Delete,
+					Kind:   ProjectResource,
+					ID:     id,
+					apply: func(c Client) error {
+						return c.DeleteProject(id)
+					},
+				})
+			}
+		}
+	}
+
+	for _, changes := range [][]Change{deleteConfigs, deleteTemplates, deleteVcsRoots, deleteProjects} {
+		for _, change := range changes {
+			p.plan.add(change)
+		}
+	}
+	return nil
+}
+
+func deleteBuildConfigurationChange(kind ResourceKind, id string) Change {
+	return Change{
+		Action: Delete,
+		Kind:   kind,
+		ID:     id,
+		apply: func(c Client) error {
+			return c.DeleteBuildConfiguration(id)
+		},
+	}
+}
+
+func sortedKeys(configs types.BuildConfigurations) []string {
+	ids := make([]string, 0, len(configs))
+	for id := range configs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,65 @@
+// Package reconcile brings projects, templates, build configurations and VCS
+// roots on a TeamCity server in line with a desired state described in Go.
+//
+// NewPlan compares the desired state with the server and returns the creates,
+// updates and deletes needed. The plan can be printed for review and then
+// applied, which performs the changes in dependency order.
+package reconcile
+
+import (
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// Desired is the state to reconcile towards. Project is the root of the tree:
+// subprojects go in Projects, build configurations in BuildConfigurations and
+// templates in Templates. VCS roots are listed separately since they may be
+// shared by build configurations of several projects.
+type Desired struct {
+	Project  types.Project
+	VcsRoots []types.VcsRoot
+}
+
+// Options controls how a plan is made
+type Options struct {
+	// Prune deletes subprojects, build configurations, templates and VCS
+	// roots found on the server inside the desired projects but not in the
+	// desired state
+	Prune bool
+}
+
+// Client is the part of *teamcity.Client used for reconciliation
+type Client interface {
+	GetProject(projectID string) (*types.Project, error)
+	CreateProject(project *types.Project) error
+	DeleteProject(projectID string) error
+	MoveProject(projectID, parentProjectID string) error
+	SetProjectField(projectID, field string, content string) error
+	SetProjectDescription(projectID, description string) error
+	ReplaceAllProjectParameters(projectID string, parameters *types.Parameters) error
+
+	GetVcsRoot(vcsRootID string) (*types.VcsRoot, error)
+	GetProjectVcsRoots(projectID string) ([]types.VcsRoot, error)
+	CreateVcsRoot(vcs *types.VcsRoot) error
+	DeleteVcsRoot(vcsRootID string) error
+	SetVcsRootName(vcsRootID, name string) error
+	SetVcsRootProject(vcsRootID, projectID string) error
+	ReplaceAllVcsRootProperties(vcsRootID string, properties *types.Properties) error
+
+	GetBuildConfiguration(buildConfID string) (*types.BuildConfiguration, error)
+	CreateBuildConfiguration(buildConfig *types.BuildConfiguration) error
+	DeleteBuildConfiguration(buildConfID string) error
+	MoveBuildConfiguration(buildConfID, targetProjectID string) error
+	ReplaceBuildConfigurationField(buildConfID, name string, value string) error
+	SetBuildConfigurationDescription(buildConfID, description string) error
+	ReplaceBuildConfigurationSetting(buildConfID, name string, value string) error
+	ReplaceAllBuildConfigurationTemplates(buildConfID string, templates *types.TemplateIds) error
+	AttachBuildConfigurationVcsRoot(buildConfID string, vcsRoot *types.VcsRootEntry) error
+	DetachBuildConfigurationVcsRoot(buildConfID string, vcsRootID string) error
+	ReplaceAllBuildConfigurationParameters(buildConfID string, parameters *types.Parameters) error
+	ReplaceAllBuildConfigurationSteps(buildConfID string, steps *types.BuildSteps) error
+	ReplaceAllBuildConfigurationTriggers(buildConfID string, triggers *types.BuildTriggers) error
+	ReplaceAllBuildConfigurationFeatures(buildConfID string, features *types.BuildFeatures) error
+	ReplaceAllBuildConfigurationSnapshotDependencies(buildConfID string, snapshotDependencies *types.BuildSnapshotDependencies) error
+	ReplaceAllBuildConfigurationArtifactDependencies(buildConfID string, artifactDependencies *types.BuildArtifactDependencies) error
+	ReplaceAllBuildConfigurationAgentRequirements(buildConfID string, agentRequirements *types.BuildAgentRequirements) error
+}
@@ -0,0 +1,179 @@
+package reconcile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/teamcity"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Client = &teamcity.Client{}
+
+func desiredState() *Desired {
+	return &Desired{
+		Project: types.Project{
+			ID:              "App",
+			Name:            "App",
+			ParentProjectID: "_Root",
+			Parameters: types.Parameters{
+				"env.REGION": types.Parameter{Value: "eu"},
+			},
+			Templates: types.BuildConfigurations{
+				"App_Base": types.BuildConfiguration{
+					Name: "Base",
+					Steps: types.BuildSteps{
+						{Name: "Build", Type: "simpleRunner", Properties: types.Properties{"script.content": "make"}},
+					},
+				},
+			},
+			BuildConfigurations: types.BuildConfigurations{
+				"App_Deploy": types.BuildConfiguration{
+					Name:      "Deploy",
+					Templates: types.TemplateIds{"App_Base"},
+					SnapshotDependencies: types.BuildSnapshotDependencies{
+						{Type: "snapshot_dependency", SourceBuildType: types.BuildType{ID: "App_Build"}},
+					},
+				},
+				"App_Build": types.BuildConfiguration{
+					Name:      "Build",
+					Templates: types.TemplateIds{"App_Base"},
+					VcsRootEntries: types.VcsRootEntries{
+						{VcsRootID: "App_Git"},
+					},
+				},
+			},
+			Projects: types.Projects{
+				"App_Docs": types.Project{Name: "Docs"},
+			},
+		},
+		VcsRoots: []types.VcsRoot{
+			{
+				ID:         "App_Git",
+				Name:       "git",
+				VcsName:    "jetbrains.git",
+				ProjectID:  "App",
+				Properties: types.Properties{"url": "https://example.com/app.git", "secure:password": "credentialsJSON:abc"},
+			},
+		},
+	}
+}
+
+func TestPlanCreate(t *testing.T) {
+	client := newFakeClient()
+	plan, err := NewPlan(client, desiredState(), Options{})
+	require.NoError(t, err, "Expected no error")
+
+	assert.Equal(t, `+ project App (parameters)
++ project App_Docs
++ vcs root App_Git
++ template App_Base (steps)
++ build configuration App_Build (templates, vcs roots)
++ build configuration App_Deploy (templates, snapshot dependencies)
+Plan: 6 to create, 0 to update, 0 to delete.`, plan.String())
+	assert.Empty(t, client.calls, "Expected planning not to modify anything")
+
+	result, err := plan.Apply(client)
+	require.NoError(t, err, "Expected no error")
+	assert.Len(t, result.Applied, 6)
+	assert.Equal(t, []string{
+		"CreateProject App",
+		"ReplaceAllProjectParameters App",
+		"CreateProject App_Docs",
+		"CreateVcsRoot App_Git",
+		"CreateBuildConfiguration App_Base",
+		"CreateBuildConfiguration App_Build",
+		"CreateBuildConfiguration App_Deploy",
+	}, client.calls)
+	assert.Equal(t, types.ProjectId("App"), client.projects["App_Docs"].ParentProjectID)
+	assert.True(t, client.configs["App_Base"].TemplateFlag)
+
+	// The server hides secure values, which must not cause a change
+	root := client.vcsRoots["App_Git"]
+	root.Properties = types.Properties{"url": "https://example.com/app.git", "secure:password": ""}
+	client.vcsRoots["App_Git"] = root
+
+	plan, err = NewPlan(client, desiredState(), Options{Prune: true})
+	require.NoError(t, err, "Expected no error")
+	assert.True(t, plan.Empty(), "Expected no changes, got\n%s", plan)
+	assert.Equal(t, "No changes.", plan.String())
+}
+
+func TestPlanUpdateAndPrune(t *testing.T) {
+	client := newFakeClient()
+	plan, err := NewPlan(client, desiredState(), Options{})
+	require.NoError(t, err, "Expected no error")
+	_, err = plan.Apply(client)
+	require.NoError(t, err, "Expected no error")
+
+	client.configs["App_Old"] = types.BuildConfiguration{ID: "App_Old", ProjectID: "App"}
+	client.projects["App_Legacy"] = types.Project{ID: "App_Legacy", ParentProjectID: "App"}
+	client.vcsRoots["App_Svn"] = types.VcsRoot{ID: "App_Svn", ProjectID: "App"}
+	client.calls = make([]string, 0)
+
+	desired := desiredState()
+	desired.Project.Description = "Application"
+	build := desired.Project.BuildConfigurations["App_Build"]
+	build.VcsRootEntries[0].CheckoutRules = "+:src"
+	build.Parameters = types.Parameters{"version": types.Parameter{Value: "1.0"}}
+	desired.Project.BuildConfigurations["App_Build"] = build
+
+	plan, err = NewPlan(client, desired, Options{Prune: true})
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, `~ project App (description)
+~ build configuration App_Build (vcs roots, parameters)
+- build configuration App_Old
+- vcs root App_Svn
+- project App_Legacy
+Plan: 0 to create, 2 to update, 3 to delete.`, plan.String())
+
+	_, err = plan.Apply(client)
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, []string{
+		"SetProjectDescription App",
+		"DetachBuildConfigurationVcsRoot App_Build",
+		"AttachBuildConfigurationVcsRoot App_Build",
+		"ReplaceAllBuildConfigurationParameters App_Build",
+		"DeleteBuildConfiguration App_Old",
+		"DeleteVcsRoot App_Svn",
+		"DeleteProject App_Legacy",
+	}, client.calls)
+	assert.Equal(t, "+:src", client.configs["App_Build"].VcsRootEntries[0].CheckoutRules)
+}
+
+func TestPlanApplyPartialFailure(t *testing.T) {
+	client := newFakeClient()
+	client.fail["CreateVcsRoot App_Git"] = errors.New("bad url")
+
+	plan, err := NewPlan(client, desiredState(), Options{})
+	require.NoError(t, err, "Expected no error")
+	result, err := plan.Apply(client)
+	require.Error(t, err)
+	assert.EqualError(t, err, "1 of 6 changes failed, 2 skipped: create vcs root App_Git: bad url")
+
+	applied := make([]string, 0)
+	for _, change := range result.Applied {
+		applied = append(applied, change.ID)
+	}
+	skipped := make([]string, 0)
+	for _, change := range result.Skipped {
+		skipped = append(skipped, change.ID)
+	}
+	assert.Equal(t, []string{"App", "App_Docs", "App_Base"}, applied)
+	assert.Equal(t, []string{"App_Build", "App_Deploy"}, skipped)
+	assert.NotContains(t, client.configs, "App_Deploy")
+}
+
+func TestPlanDependencyCycle(t *testing.T) {
+	desired := desiredState()
+	build := desired.Project.BuildConfigurations["App_Build"]
+	build.SnapshotDependencies = types.BuildSnapshotDependencies{
+		{Type: "snapshot_dependency", SourceBuildType: types.BuildType{ID: "App_Deploy"}},
+	}
+	desired.Project.BuildConfigurations["App_Build"] = build
+
+	_, err := NewPlan(newFakeClient(), desired, Options{})
+	assert.EqualError(t, err, "dependency cycle between build configurations: [App_Build App_Deploy App_Build]")
+}
@@ -0,0 +1,135 @@
+// Package snapshot exports a project subtree to a portable YAML or JSON
+// document and imports it into a server, possibly under different IDs.
+//
+// Secure values are never written to a document. Password parameters, secure
+// properties and credentialsJSON tokens are exported as placeholders whose
+// values are supplied again on import.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// FormatVersion is the version of the document layout written by Export
+const FormatVersion = 1
+
+// Document is a snapshot of a project subtree. Projects are listed parents
+// first, so the first project is the root of the subtree.
+type Document struct {
+	Version  int       `json:"version" yaml:"version"`
+	Projects []Project `json:"projects" yaml:"projects"`
+	VcsRoots []VcsRoot `json:"vcsRoots,omitempty" yaml:"vcsRoots,omitempty"`
+}
+
+type Project struct {
+	ID                  string               `json:"id" yaml:"id"`
+	ParentID            string               `json:"parentId,omitempty" yaml:"parentId,omitempty"`
+	Name                string               `json:"name" yaml:"name"`
+	Description         string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters          []Parameter          `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Templates           []BuildConfiguration `json:"templates,omitempty" yaml:"templates,omitempty"`
+	BuildConfigurations []BuildConfiguration `json:"buildConfigurations,omitempty" yaml:"buildConfigurations,omitempty"`
+}
+
+type VcsRoot struct {
+	ID         string     `json:"id" yaml:"id"`
+	ProjectID  string     `json:"projectId" yaml:"projectId"`
+	Name       string     `json:"name" yaml:"name"`
+	VcsName    string     `json:"vcsName" yaml:"vcsName"`
+	Properties []Property `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+type BuildConfiguration struct {
+	ID                   string         `json:"id" yaml:"id"`
+	Name                 string         `json:"name" yaml:"name"`
+	Description          string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Templates            []string       `json:"templates,omitempty" yaml:"templates,omitempty"`
+	VcsRoots             []VcsRootEntry `json:"vcsRoots,omitempty" yaml:"vcsRoots,omitempty"`
+	Settings             []Property     `json:"settings,omitempty" yaml:"settings,omitempty"`
+	Parameters           []Parameter    `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Steps                []Item         `json:"steps,omitempty" yaml:"steps,omitempty"`
+	Triggers             []Item         `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+	Features             []Item         `json:"features,omitempty" yaml:"features,omitempty"`
+	SnapshotDependencies []Item         `json:"snapshotDependencies,omitempty" yaml:"snapshotDependencies,omitempty"`
+	ArtifactDependencies []Item         `json:"artifactDependencies,omitempty" yaml:"artifactDependencies,omitempty"`
+	AgentRequirements    []Item         `json:"agentRequirements,omitempty" yaml:"agentRequirements,omitempty"`
+}
+
+type VcsRootEntry struct {
+	ID            string `json:"id" yaml:"id"`
+	CheckoutRules string `json:"checkoutRules,omitempty" yaml:"checkoutRules,omitempty"`
+}
+
+// Item is a step, trigger, feature, dependency or agent requirement. Source
+// is the build configuration a dependency points at.
+type Item struct {
+	ID         string     `json:"id,omitempty" yaml:"id,omitempty"`
+	Name       string     `json:"name,omitempty" yaml:"name,omitempty"`
+	Type       string     `json:"type" yaml:"type"`
+	Source     string     `json:"source,omitempty" yaml:"source,omitempty"`
+	Disabled   bool       `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	Properties []Property `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// Property is a name and value. Secure marks a placeholder for a value that
+// was not exported.
+type Property struct {
+	Name   string `json:"name" yaml:"name"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
+	Secure bool   `json:"secure,omitempty" yaml:"secure,omitempty"`
+}
+
+// Parameter is a parameter with its raw spec, e.g. "text display='prompt'"
+type Parameter struct {
+	Name   string `json:"name" yaml:"name"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
+	Spec   string `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Secure bool   `json:"secure,omitempty" yaml:"secure,omitempty"`
+}
+
+// WriteJSON writes the document as indented JSON
+func (d *Document) WriteJSON(w io.Writer) error {
+	bytes, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(bytes, '\n'))
+	return err
+}
+
+// WriteYAML writes the document as YAML
+func (d *Document) WriteYAML(w io.Writer) error {
+	bytes, err := yaml.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bytes)
+	return err
+}
+
+// Read parses a document written by WriteJSON or WriteYAML
+func Read(r io.Reader) (*Document, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var doc Document
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.UnmarshalStrict(data, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if doc.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", doc.Version)
+	}
+	return &doc, nil
+}
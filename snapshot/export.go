@@ -0,0 +1,196 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// Source is the part of *teamcity.Client used by Export
+type Source interface {
+	GetProject(projectID string) (*types.Project, error)
+	GetBuildConfiguration(buildConfID string) (*types.BuildConfiguration, error)
+	GetProjectVcsRoots(projectID string) ([]types.VcsRoot, error)
+	GetVcsRoot(vcsRootID string) (*types.VcsRoot, error)
+}
+
+func isSecureProperty(name, value string) bool {
+	return types.IsSecureProperty(name) || strings.HasPrefix(value, types.SecureTokenPrefix)
+}
+
+// Export snapshots a project with all of its subprojects, templates, build
+// configurations and VCS roots. Only settings defined on each entity are
+// included, not those inherited from parents or templates.
+func Export(source Source, projectID string) (*Document, error) {
+	doc := &Document{
+		Version:  FormatVersion,
+		Projects: make([]Project, 0),
+		VcsRoots: make([]VcsRoot, 0),
+	}
+	if err := exportProject(source, projectID, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func exportProject(source Source, projectID string, doc *Document) error {
+	project, err := source.GetProject(projectID)
+	if err != nil {
+		return err
+	}
+	if project == nil {
+		return fmt.Errorf("project %s not found", projectID)
+	}
+
+	exported := Project{
+		ID:          project.ID,
+		ParentID:    string(project.ParentProjectID),
+		Name:        project.Name,
+		Description: project.Description,
+		Parameters:  exportParameters(project.Parameters.Own()),
+	}
+	for _, id := range sortedIDs(project.Templates) {
+		config, err := exportBuildConfiguration(source, id)
+		if err != nil {
+			return err
+		}
+		exported.Templates = append(exported.Templates, *config)
+	}
+	for _, id := range sortedIDs(project.BuildConfigurations) {
+		config, err := exportBuildConfiguration(source, id)
+		if err != nil {
+			return err
+		}
+		exported.BuildConfigurations = append(exported.BuildConfigurations, *config)
+	}
+	doc.Projects = append(doc.Projects, exported)
+
+	roots, err := source.GetProjectVcsRoots(projectID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].ID < roots[j].ID })
+	for _, short := range roots {
+		root, err := source.GetVcsRoot(short.ID)
+		if err != nil {
+			return err
+		}
+		if root == nil {
+			return fmt.Errorf("vcs root %s not found", short.ID)
+		}
+		doc.VcsRoots = append(doc.VcsRoots, VcsRoot{
+			ID:         root.ID,
+			ProjectID:  projectID,
+			Name:       root.Name,
+			VcsName:    root.VcsName,
+			Properties: exportProperties(root.Properties),
+		})
+	}
+
+	childIDs := make([]string, 0, len(project.Projects))
+	for id := range project.Projects {
+		childIDs = append(childIDs, id)
+	}
+	sort.Strings(childIDs)
+	for _, id := range childIDs {
+		if err := exportProject(source, id, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportBuildConfiguration(source Source, buildConfID string) (*BuildConfiguration, error) {
+	config, err := source.GetBuildConfiguration(buildConfID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("build configuration %s not found", buildConfID)
+	}
+	own := config.OwnSettings()
+
+	exported := &BuildConfiguration{
+		ID:          own.ID,
+		Name:        own.Name,
+		Description: own.Description,
+		Parameters:  exportParameters(own.Parameters),
+	}
+	if len(own.Templates) > 0 {
+		for _, id := range own.Templates {
+			exported.Templates = append(exported.Templates, string(id))
+		}
+	} else if own.TemplateID != "" {
+		exported.Templates = []string{string(own.TemplateID)}
+	}
+	for _, entry := range own.VcsRootEntries {
+		exported.VcsRoots = append(exported.VcsRoots, VcsRootEntry{
+			ID:            string(entry.VcsRootID),
+			CheckoutRules: entry.CheckoutRules,
+		})
+	}
+	for _, setting := range own.Settings {
+		exported.Settings = append(exported.Settings, Property{Name: setting.Name, Value: setting.Value})
+	}
+	for _, step := range own.Steps {
+		exported.Steps = append(exported.Steps, Item{ID: step.ID, Name: step.Name, Type: step.Type, Disabled: step.Disabled, Properties: exportProperties(step.Properties)})
+	}
+	for _, trigger := range own.Triggers {
+		exported.Triggers = append(exported.Triggers, Item{ID: trigger.ID, Type: trigger.Type, Disabled: trigger.Disabled, Properties: exportProperties(trigger.Properties)})
+	}
+	for _, feature := range own.Features {
+		exported.Features = append(exported.Features, Item{ID: feature.ID, Type: feature.Type, Disabled: feature.Disabled, Properties: exportProperties(feature.Properties)})
+	}
+	for _, dependency := range own.SnapshotDependencies {
+		exported.SnapshotDependencies = append(exported.SnapshotDependencies, Item{ID: dependency.ID, Type: dependency.Type, Source: dependency.SourceBuildType.ID, Disabled: dependency.Disabled, Properties: exportProperties(dependency.Properties)})
+	}
+	for _, dependency := range own.ArtifactDependencies {
+		exported.ArtifactDependencies = append(exported.ArtifactDependencies, Item{ID: dependency.ID, Type: dependency.Type, Source: dependency.SourceBuildType.ID, Disabled: dependency.Disabled, Properties: exportProperties(dependency.Properties)})
+	}
+	for _, requirement := range own.AgentRequirements {
+		exported.AgentRequirements = append(exported.AgentRequirements, Item{ID: requirement.ID, Type: requirement.Type, Disabled: requirement.Disabled, Properties: exportProperties(requirement.Properties)})
+	}
+	return exported, nil
+}
+
+func exportParameters(parameters types.Parameters) []Parameter {
+	var exported []Parameter
+	for _, parameter := range parameters.Ordered() {
+		p := Parameter{Name: parameter.Name}
+		if parameter.Spec != nil {
+			p.Spec = parameter.Spec.String()
+		}
+		if parameter.IsSecure() {
+			p.Secure = true
+		} else {
+			p.Value = parameter.Value
+		}
+		exported = append(exported, p)
+	}
+	return exported
+}
+
+func exportProperties(properties types.Properties) []Property {
+	var exported []Property
+	for _, property := range properties.Ordered() {
+		p := Property{Name: property.Name}
+		if isSecureProperty(property.Name, property.Value) {
+			p.Secure = true
+		} else {
+			p.Value = property.Value
+		}
+		exported = append(exported, p)
+	}
+	return exported
+}
+
+func sortedIDs(configs types.BuildConfigurations) []string {
+	ids := make([]string, 0, len(configs))
+	for id := range configs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
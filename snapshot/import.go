@@ -0,0 +1,315 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/reconcile"
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// ImportOptions controls how a document is turned into a desired state
+type ImportOptions struct {
+	// MapID returns the ID to use on the target server for a project, build
+	// configuration, template or VCS root of the document. IDs are kept when
+	// nil. References to entities outside the document are never mapped.
+	MapID func(id string) string
+	// ParentProjectID is the project to import the root project into. The
+	// exported parent is kept when empty.
+	ParentProjectID string
+	// Secrets holds the values of secure placeholders, keyed as listed by
+	// Document.SecretKeys
+	Secrets map[string]string
+}
+
+// PrefixMapper returns a MapID function replacing an ID prefix, e.g. to
+// import "Staging_App_Build" as "Prod_App_Build"
+func PrefixMapper(oldPrefix, newPrefix string) func(string) string {
+	return func(id string) string {
+		if strings.HasPrefix(id, oldPrefix) {
+			return newPrefix + strings.TrimPrefix(id, oldPrefix)
+		}
+		return id
+	}
+}
+
+// SecretKey names a secure placeholder. owner is the exported ID of the
+// project, build configuration or VCS root, followed by the step, trigger or
+// other item ID for their properties.
+func SecretKey(owner ...string) string {
+	return strings.Join(owner, "/")
+}
+
+func itemOwner(configID string, item Item, idx int) string {
+	if item.ID != "" {
+		return SecretKey(configID, item.ID)
+	}
+	return SecretKey(configID, fmt.Sprintf("#%d", idx+1))
+}
+
+// SecretKeys lists the secure placeholders that need values on import
+func (d *Document) SecretKeys() []string {
+	keys := make([]string, 0)
+	addParameters := func(owner string, parameters []Parameter) {
+		for _, parameter := range parameters {
+			if parameter.Secure {
+				keys = append(keys, SecretKey(owner, parameter.Name))
+			}
+		}
+	}
+	addProperties := func(owner string, properties []Property) {
+		for _, property := range properties {
+			if property.Secure {
+				keys = append(keys, SecretKey(owner, property.Name))
+			}
+		}
+	}
+	for _, project := range d.Projects {
+		addParameters(project.ID, project.Parameters)
+		for _, config := range append(append([]BuildConfiguration{}, project.Templates...), project.BuildConfigurations...) {
+			addParameters(config.ID, config.Parameters)
+			for _, items := range [][]Item{config.Steps, config.Triggers, config.Features, config.SnapshotDependencies, config.ArtifactDependencies, config.AgentRequirements} {
+				for idx, item := range items {
+					addProperties(itemOwner(config.ID, item, idx), item.Properties)
+				}
+			}
+		}
+	}
+	for _, root := range d.VcsRoots {
+		addProperties(root.ID, root.Properties)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type importer struct {
+	options    ImportOptions
+	known      map[string]bool
+	buildTypes []string
+	missing    []string
+}
+
+func (im *importer) mapID(id string) string {
+	if !im.known[id] || im.options.MapID == nil {
+		return id
+	}
+	return im.options.MapID(id)
+}
+
+// mapValue rewrites %dep.<id>.name% references to mapped build configurations
+func (im *importer) mapValue(value string) string {
+	for _, id := range im.buildTypes {
+		if mapped := im.mapID(id); mapped != id {
+			value = strings.Replace(value, "%dep."+id+".", "%dep."+mapped+".", -1)
+		}
+	}
+	return value
+}
+
+func (im *importer) secret(key string) string {
+	value, ok := im.options.Secrets[key]
+	if !ok {
+		im.missing = append(im.missing, key)
+	}
+	return value
+}
+
+func (im *importer) parameters(owner string, parameters []Parameter) (types.Parameters, error) {
+	ret := make(types.Parameters)
+	for _, parameter := range parameters {
+		p := types.Parameter{Value: im.mapValue(parameter.Value)}
+		if parameter.Secure {
+			p.Value = im.secret(SecretKey(owner, parameter.Name))
+		}
+		if parameter.Spec != "" {
+			spec, err := types.ParseParameterSpec(parameter.Spec)
+			if err != nil {
+				return nil, fmt.Errorf("parameter %s of %s: %s", parameter.Name, owner, err)
+			}
+			p.Spec = spec
+		}
+		ret[parameter.Name] = p
+	}
+	return ret, nil
+}
+
+func (im *importer) properties(owner string, properties []Property) types.Properties {
+	ret := make(types.Properties)
+	for _, property := range properties {
+		if property.Secure {
+			ret[property.Name] = im.secret(SecretKey(owner, property.Name))
+		} else {
+			ret[property.Name] = im.mapValue(property.Value)
+		}
+	}
+	return ret
+}
+
+func (im *importer) buildConfiguration(projectID string, config BuildConfiguration, template bool) (types.BuildConfiguration, error) {
+	parameters, err := im.parameters(config.ID, config.Parameters)
+	if err != nil {
+		return types.BuildConfiguration{}, err
+	}
+	ret := types.BuildConfiguration{
+		ID:           im.mapID(config.ID),
+		ProjectID:    projectID,
+		TemplateFlag: template,
+		Name:         config.Name,
+		Description:  config.Description,
+		Parameters:   parameters,
+	}
+	for _, id := range config.Templates {
+		ret.Templates = append(ret.Templates, types.TemplateId(im.mapID(id)))
+	}
+	for _, entry := range config.VcsRoots {
+		ret.VcsRootEntries = append(ret.VcsRootEntries, types.VcsRootEntry{
+			VcsRootID:     types.VcsRootId(im.mapID(entry.ID)),
+			CheckoutRules: entry.CheckoutRules,
+		})
+	}
+	for _, setting := range config.Settings {
+		ret.Settings = append(ret.Settings, types.BuildSetting{Name: setting.Name, Value: im.mapValue(setting.Value)})
+	}
+	for idx, item := range config.Steps {
+		ret.Steps = append(ret.Steps, types.BuildStep{ID: item.ID, Name: item.Name, Type: item.Type, Disabled: item.Disabled,
+			Properties: im.properties(itemOwner(config.ID, item, idx), item.Properties)})
+	}
+	for idx, item := range config.Triggers {
+		ret.Triggers = append(ret.Triggers, types.BuildTrigger{ID: item.ID, Type: item.Type, Disabled: item.Disabled,
+			Properties: im.properties(itemOwner(config.ID, item, idx), item.Properties)})
+	}
+	for idx, item := range config.Features {
+		ret.Features = append(ret.Features, types.BuildFeature{ID: item.ID, Type: item.Type, Disabled: item.Disabled,
+			Properties: im.properties(itemOwner(config.ID, item, idx), item.Properties)})
+	}
+	for idx, item := range config.SnapshotDependencies {
+		source := im.mapID(item.Source)
+		id := item.ID
+		if id == item.Source {
+			id = source
+		}
+		ret.SnapshotDependencies = append(ret.SnapshotDependencies, types.BuildSnapshotDependency{ID: id, Type: item.Type, Disabled: item.Disabled,
+			SourceBuildType: types.BuildType{ID: source},
+			Properties:      im.properties(itemOwner(config.ID, item, idx), item.Properties)})
+	}
+	for idx, item := range config.ArtifactDependencies {
+		ret.ArtifactDependencies = append(ret.ArtifactDependencies, types.BuildArtifactDependency{ID: item.ID, Type: item.Type, Disabled: item.Disabled,
+			SourceBuildType: types.BuildType{ID: im.mapID(item.Source)},
+			Properties:      im.properties(itemOwner(config.ID, item, idx), item.Properties)})
+	}
+	for idx, item := range config.AgentRequirements {
+		ret.AgentRequirements = append(ret.AgentRequirements, types.BuildAgentRequirement{ID: item.ID, Type: item.Type, Disabled: item.Disabled,
+			Properties: im.properties(itemOwner(config.ID, item, idx), item.Properties)})
+	}
+	return ret, nil
+}
+
+func (im *importer) project(project Project, children map[string][]Project) (types.Project, error) {
+	id := im.mapID(project.ID)
+	parameters, err := im.parameters(project.ID, project.Parameters)
+	if err != nil {
+		return types.Project{}, err
+	}
+	ret := types.Project{
+		ID:                  id,
+		ParentProjectID:     types.ProjectId(im.mapID(project.ParentID)),
+		Name:                project.Name,
+		Description:         project.Description,
+		Parameters:          parameters,
+		Templates:           make(types.BuildConfigurations),
+		BuildConfigurations: make(types.BuildConfigurations),
+		Projects:            make(types.Projects),
+	}
+	for _, template := range project.Templates {
+		config, err := im.buildConfiguration(id, template, true)
+		if err != nil {
+			return types.Project{}, err
+		}
+		ret.Templates[config.ID] = config
+	}
+	for _, buildConfig := range project.BuildConfigurations {
+		config, err := im.buildConfiguration(id, buildConfig, false)
+		if err != nil {
+			return types.Project{}, err
+		}
+		ret.BuildConfigurations[config.ID] = config
+	}
+	for _, child := range children[project.ID] {
+		sub, err := im.project(child, children)
+		if err != nil {
+			return types.Project{}, err
+		}
+		ret.Projects[sub.ID] = sub
+	}
+	return ret, nil
+}
+
+// Desired converts the document into the desired state for the reconcile
+// package, applying ID mapping and filling in secure placeholders. It fails
+// if a placeholder has no value in options.Secrets.
+func (d *Document) Desired(options ImportOptions) (*reconcile.Desired, error) {
+	if len(d.Projects) == 0 {
+		return nil, fmt.Errorf("snapshot has no projects")
+	}
+	im := &importer{
+		options: options,
+		known:   make(map[string]bool),
+		missing: make([]string, 0),
+	}
+	children := make(map[string][]Project)
+	for _, project := range d.Projects {
+		im.known[project.ID] = true
+		for _, config := range append(append([]BuildConfiguration{}, project.Templates...), project.BuildConfigurations...) {
+			im.known[config.ID] = true
+			im.buildTypes = append(im.buildTypes, config.ID)
+		}
+		children[project.ParentID] = append(children[project.ParentID], project)
+	}
+	for _, root := range d.VcsRoots {
+		im.known[root.ID] = true
+	}
+
+	root, err := im.project(d.Projects[0], children)
+	if err != nil {
+		return nil, err
+	}
+	if options.ParentProjectID != "" {
+		root.ParentProjectID = types.ProjectId(options.ParentProjectID)
+	}
+
+	desired := &reconcile.Desired{
+		Project:  root,
+		VcsRoots: make([]types.VcsRoot, 0, len(d.VcsRoots)),
+	}
+	for _, root := range d.VcsRoots {
+		desired.VcsRoots = append(desired.VcsRoots, types.VcsRoot{
+			ID:         im.mapID(root.ID),
+			ProjectID:  types.ProjectId(im.mapID(root.ProjectID)),
+			Name:       root.Name,
+			VcsName:    root.VcsName,
+			Properties: im.properties(root.ID, root.Properties),
+		})
+	}
+
+	if len(im.missing) > 0 {
+		sort.Strings(im.missing)
+		return nil, fmt.Errorf("missing secure values: %s", strings.Join(im.missing, ", "))
+	}
+	return desired, nil
+}
+
+// Import plans and applies the document against a server. The plan is
+// returned along with the result so callers can report what was done.
+func Import(client reconcile.Client, d *Document, options ImportOptions) (*reconcile.Plan, *reconcile.Result, error) {
+	desired, err := d.Desired(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan, err := reconcile.NewPlan(client, desired, reconcile.Options{})
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := plan.Apply(client)
+	return plan, result, err
+}
@@ -0,0 +1,198 @@
+package snapshot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/teamcity"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Source = &teamcity.Client{}
+
+type fakeSource struct {
+	projects map[string]types.Project
+	configs  map[string]types.BuildConfiguration
+	vcsRoots map[string]types.VcsRoot
+}
+
+func (f *fakeSource) GetProject(projectID string) (*types.Project, error) {
+	project, ok := f.projects[projectID]
+	if !ok {
+		return nil, nil
+	}
+	return &project, nil
+}
+
+func (f *fakeSource) GetBuildConfiguration(buildConfID string) (*types.BuildConfiguration, error) {
+	config, ok := f.configs[buildConfID]
+	if !ok {
+		return nil, nil
+	}
+	return &config, nil
+}
+
+func (f *fakeSource) GetProjectVcsRoots(projectID string) ([]types.VcsRoot, error) {
+	roots := make([]types.VcsRoot, 0)
+	for _, root := range f.vcsRoots {
+		if string(root.ProjectID) == projectID {
+			roots = append(roots, types.VcsRoot{ID: root.ID, Name: root.Name})
+		}
+	}
+	return roots, nil
+}
+
+func (f *fakeSource) GetVcsRoot(vcsRootID string) (*types.VcsRoot, error) {
+	root, ok := f.vcsRoots[vcsRootID]
+	if !ok {
+		return nil, nil
+	}
+	return &root, nil
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		projects: map[string]types.Project{
+			"Stage": {
+				ID:              "Stage",
+				Name:            "Stage",
+				ParentProjectID: "_Root",
+				Parameters: types.Parameters{
+					"env.TOKEN": types.Parameter{Value: "", Spec: &types.ParameterSpec{Type: types.PasswordType{}}},
+					"region":    types.Parameter{Value: "eu"},
+					"inherited": types.Parameter{Value: "x", Inherited: true},
+				},
+				Templates:           types.BuildConfigurations{"Stage_Base": {ID: "Stage_Base"}},
+				BuildConfigurations: types.BuildConfigurations{"Stage_Build": {ID: "Stage_Build"}},
+				Projects:            types.Projects{"Stage_Docs": {ID: "Stage_Docs"}},
+			},
+			"Stage_Docs": {
+				ID:                  "Stage_Docs",
+				Name:                "Docs",
+				ParentProjectID:     "Stage",
+				BuildConfigurations: types.BuildConfigurations{"Stage_Docs_Publish": {ID: "Stage_Docs_Publish"}},
+			},
+		},
+		configs: map[string]types.BuildConfiguration{
+			"Stage_Base": {
+				ID:           "Stage_Base",
+				ProjectID:    "Stage",
+				TemplateFlag: true,
+				Name:         "Base",
+				Steps: types.BuildSteps{
+					{ID: "RUNNER_1", Name: "Make", Type: "simpleRunner", Properties: types.Properties{"script.content": "make"}},
+				},
+			},
+			"Stage_Build": {
+				ID:             "Stage_Build",
+				ProjectID:      "Stage",
+				Name:           "Build",
+				Templates:      types.TemplateIds{"Stage_Base"},
+				VcsRootEntries: types.VcsRootEntries{{VcsRootID: "Stage_Git", CheckoutRules: "+:src"}},
+				Steps: types.BuildSteps{
+					{ID: "RUNNER_1", Name: "Make", Type: "simpleRunner", Inherited: true},
+				},
+			},
+			"Stage_Docs_Publish": {
+				ID:        "Stage_Docs_Publish",
+				ProjectID: "Stage_Docs",
+				Name:      "Publish",
+				Parameters: types.Parameters{
+					"version": types.Parameter{Value: "%dep.Stage_Build.build.number%"},
+				},
+				SnapshotDependencies: types.BuildSnapshotDependencies{
+					{ID: "Stage_Build", Type: "snapshot_dependency", SourceBuildType: types.BuildType{ID: "Stage_Build"}},
+				},
+			},
+		},
+		vcsRoots: map[string]types.VcsRoot{
+			"Stage_Git": {
+				ID:        "Stage_Git",
+				Name:      "git",
+				VcsName:   "jetbrains.git",
+				ProjectID: "Stage",
+				Properties: types.Properties{
+					"url":             "https://example.com/app.git",
+					"secure:password": "",
+				},
+			},
+		},
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	doc, err := Export(newFakeSource(), "Stage")
+	require.NoError(t, err, "Expected no error")
+
+	require.Len(t, doc.Projects, 2)
+	assert.Equal(t, "Stage", doc.Projects[0].ID)
+	assert.Equal(t, "Stage_Docs", doc.Projects[1].ID)
+	assert.Equal(t, []Parameter{
+		{Name: "env.TOKEN", Spec: "password display='normal'", Secure: true},
+		{Name: "region", Value: "eu"},
+	}, doc.Projects[0].Parameters)
+	assert.Empty(t, doc.Projects[0].BuildConfigurations[0].Steps, "Expected inherited steps to be left out")
+	assert.Equal(t, []string{"Stage/env.TOKEN", "Stage_Git/secure:password"}, doc.SecretKeys())
+
+	var yamlOut bytes.Buffer
+	require.NoError(t, doc.WriteYAML(&yamlOut))
+	assert.Contains(t, yamlOut.String(), "- name: secure:password\n    secure: true\n")
+	fromYAML, err := Read(&yamlOut)
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, doc, fromYAML)
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, doc.WriteJSON(&jsonOut))
+	fromJSON, err := Read(&jsonOut)
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, doc, fromJSON)
+}
+
+func TestDesiredRemapsIDs(t *testing.T) {
+	doc, err := Export(newFakeSource(), "Stage")
+	require.NoError(t, err, "Expected no error")
+
+	_, err = doc.Desired(ImportOptions{})
+	assert.EqualError(t, err, "missing secure values: Stage/env.TOKEN, Stage_Git/secure:password")
+
+	desired, err := doc.Desired(ImportOptions{
+		MapID:           PrefixMapper("Stage", "Prod"),
+		ParentProjectID: "Live",
+		Secrets: map[string]string{
+			"Stage/env.TOKEN":           "credentialsJSON:prod-token",
+			"Stage_Git/secure:password": "hunter2",
+		},
+	})
+	require.NoError(t, err, "Expected no error")
+
+	root := desired.Project
+	assert.Equal(t, "Prod", root.ID)
+	assert.Equal(t, types.ProjectId("Live"), root.ParentProjectID)
+	assert.Equal(t, "credentialsJSON:prod-token", root.Parameters["env.TOKEN"].Value)
+	assert.Equal(t, types.PasswordType{}, root.Parameters["env.TOKEN"].Spec.Type)
+
+	build := root.BuildConfigurations["Prod_Build"]
+	assert.Equal(t, "Prod", build.ProjectID)
+	assert.Equal(t, types.TemplateIds{"Prod_Base"}, build.Templates)
+	assert.Equal(t, types.VcsRootId("Prod_Git"), build.VcsRootEntries[0].VcsRootID)
+	assert.True(t, root.Templates["Prod_Base"].TemplateFlag)
+
+	docs := root.Projects["Prod_Docs"]
+	publish := docs.BuildConfigurations["Prod_Docs_Publish"]
+	assert.Equal(t, types.ProjectId("Prod"), docs.ParentProjectID)
+	assert.Equal(t, "Prod_Build", publish.SnapshotDependencies[0].SourceBuildType.ID)
+	assert.Equal(t, "Prod_Build", publish.SnapshotDependencies[0].ID)
+	assert.Equal(t, "%dep.Prod_Build.build.number%", publish.Parameters["version"].Value)
+
+	require.Len(t, desired.VcsRoots, 1)
+	assert.Equal(t, "Prod_Git", desired.VcsRoots[0].ID)
+	assert.Equal(t, types.ProjectId("Prod"), desired.VcsRoots[0].ProjectID)
+	assert.Equal(t, "hunter2", desired.VcsRoots[0].Properties["secure:password"])
+}
+
+func TestReadRejectsUnknownVersion(t *testing.T) {
+	_, err := Read(bytes.NewBufferString("version: 7\nprojects: []\n"))
+	assert.EqualError(t, err, "unsupported snapshot version 7")
+}
@@ -0,0 +1,57 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+const auditPageSize = 100
+
+// GetAuditEvents returns the audit events selected by the filter, newest
+// first. Pages are requested until Count events are found or the events are
+// older than Since.
+func (c *Client) GetAuditEvents(filter types.AuditFilter) ([]types.AuditEvent, error) {
+	locator := fmt.Sprintf("count:%d", auditPageSize)
+	if dimensions := filter.Locator(); dimensions != "" {
+		locator = dimensions + "," + locator
+	}
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/audit?locator=%s", c.version, url.QueryEscape(locator))
+
+	events := []types.AuditEvent{}
+	for path != "" {
+		var page struct {
+			Count      int
+			NextHref   string
+			AuditEvent []types.AuditEvent
+		}
+		err := c.doRetryRequest("GET", path, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range page.AuditEvent {
+			if !filter.Since.IsZero() && event.Timestamp.Time().Before(filter.Since) {
+				return events, nil
+			}
+			if !filter.InRange(event) {
+				continue
+			}
+			events = append(events, event)
+			if filter.Count > 0 && len(events) == filter.Count {
+				return events, nil
+			}
+		}
+
+		path = ""
+		if page.NextHref != "" && len(page.AuditEvent) > 0 {
+			path = page.NextHref
+			if !strings.HasPrefix(path, "/httpAuth/") {
+				path = "/httpAuth" + path
+			}
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,64 @@
+package teamcity
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/icelander/teamcity-sdk-go/httpfixture"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetAuditEventsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":1,"auditEvent":[
+		{"id":"1042","timestamp":"20191212T101500+0000",
+		 "action":{"id":"42","name":"build_type_edit_settings"},
+		 "comment":{"text":"changed build step"},
+		 "user":{"id":1,"username":"admin","name":"Administrator"},
+		 "relatedEntities":{"count":1,"entity":[{"type":"buildType","buildType":{"id":"Empty_Build","projectId":"Empty"}}]}}
+	]}`), nil)
+
+	events, err := client.GetAuditEvents(types.AuditFilter{BuildConfigurationID: "Empty_Build"})
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "/httpAuth/app/rest/latest/audit", req.URL.Path)
+	assert.Equal(t, "buildType:(id:Empty_Build),count:100", req.URL.Query().Get("locator"))
+	require.Equal(t, 1, len(events))
+	assert.Equal(t, "build_type_edit_settings", events[0].Action.Name)
+	assert.Equal(t, types.AuditComment("changed build step"), events[0].Comment)
+	assert.Equal(t, "admin", events[0].User.Username)
+	assert.Equal(t, []string{"Empty_Build"}, events[0].BuildConfigurationIDs())
+}
+
+func TestClientGetAuditEventsTimeRange(t *testing.T) {
+	event := func(id, timestamp string) string {
+		return `{"id":"` + id + `","timestamp":"` + timestamp + `","action":{"name":"project_edit"}}`
+	}
+	firstPage := interaction("GET", "/audit?locator="+url.QueryEscape("affectedProject:(id:Empty),count:100"), "", 200,
+		`{"count":2,"nextHref":"/app/rest/audit?locator=affectedProject:(id:Empty),count:100,start:100","auditEvent":[`+
+			event("5", "20191214T090000+0000")+","+event("4", "20191213T090000+0000")+`]}`)
+	secondPage := httpfixture.Interaction{
+		Request: httpfixture.Request{Method: "GET", Path: "/httpAuth/app/rest/audit?locator=affectedProject:(id:Empty),count:100,start:100"},
+		Response: httpfixture.Response{StatusCode: 200, ContentType: "application/json", Body: httpfixture.Body(
+			`{"count":3,"nextHref":"/app/rest/audit?locator=affectedProject:(id:Empty),count:100,start:200","auditEvent":[` +
+				event("3", "20191212T090000+0000") + "," + event("2", "20191211T090000+0000") + "," + event("1", "20191210T090000+0000") + `]}`)},
+	}
+	client, replayer := newVersionTestClient(nil, firstPage, secondPage)
+
+	events, err := client.GetAuditEvents(types.AuditFilter{
+		ProjectID: "Empty",
+		Since:     time.Date(2019, 12, 11, 0, 0, 0, 0, time.UTC),
+		Until:     time.Date(2019, 12, 13, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err, "Expected no error")
+
+	ids := []string{}
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	assert.Equal(t, []string{"4", "3", "2"}, ids)
+	assert.Empty(t, replayer.Unused())
+}
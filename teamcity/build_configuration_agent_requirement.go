@@ -0,0 +1,59 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+func (c *Client) GetBuildConfigurationAgentRequirement(buildConfID, agentRequirementID string) (*types.BuildAgentRequirement, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/agent-requirements/%s", c.version, buildConfID, agentRequirementID)
+	var agentRequirement *types.BuildAgentRequirement
+
+	err := c.doRetryRequest("GET", path, nil, &agentRequirement)
+	if err != nil {
+		return nil, err
+	}
+
+	return agentRequirement, nil
+}
+
+func (c *Client) AddBuildConfigurationAgentRequirement(buildConfID string, agentRequirement *types.BuildAgentRequirement) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/agent-requirements", c.version, buildConfID)
+	var agentRequirementReturn *types.BuildAgentRequirement
+
+	err := c.doRetryRequest("POST", path, agentRequirement, &agentRequirementReturn)
+	if err != nil {
+		return err
+	}
+
+	if agentRequirementReturn == nil {
+		return errors.New("build configuration agent requirement not created")
+	}
+	*agentRequirement = *agentRequirementReturn
+
+	return nil
+}
+
+func (c *Client) ReplaceBuildConfigurationAgentRequirement(buildConfID, agentRequirementID string, agentRequirement *types.BuildAgentRequirement) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/agent-requirements/%s", c.version, buildConfID, agentRequirementID)
+	var agentRequirementReturn *types.BuildAgentRequirement
+
+	err := c.doRetryRequest("PUT", path, agentRequirement, &agentRequirementReturn)
+	if err != nil {
+		return err
+	}
+
+	if agentRequirementReturn == nil {
+		return errors.New("build configuration agent requirement not updated")
+	}
+	*agentRequirement = *agentRequirementReturn
+
+	return nil
+}
+
+func (c *Client) DeleteBuildConfigurationAgentRequirement(buildConfID, agentRequirementID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/agent-requirements/%s", c.version, buildConfID, agentRequirementID)
+	return c.doRetryRequest("DELETE", path, nil, nil)
+}
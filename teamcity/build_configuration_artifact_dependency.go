@@ -0,0 +1,59 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+func (c *Client) GetBuildConfigurationArtifactDependency(buildConfID, artifactDependencyID string) (*types.BuildArtifactDependency, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/artifact-dependencies/%s", c.version, buildConfID, artifactDependencyID)
+	var artifactDependency *types.BuildArtifactDependency
+
+	err := c.doRetryRequest("GET", path, nil, &artifactDependency)
+	if err != nil {
+		return nil, err
+	}
+
+	return artifactDependency, nil
+}
+
+func (c *Client) AddBuildConfigurationArtifactDependency(buildConfID string, artifactDependency *types.BuildArtifactDependency) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/artifact-dependencies", c.version, buildConfID)
+	var artifactDependencyReturn *types.BuildArtifactDependency
+
+	err := c.doRetryRequest("POST", path, artifactDependency, &artifactDependencyReturn)
+	if err != nil {
+		return err
+	}
+
+	if artifactDependencyReturn == nil {
+		return errors.New("build configuration artifact dependency not created")
+	}
+	*artifactDependency = *artifactDependencyReturn
+
+	return nil
+}
+
+func (c *Client) ReplaceBuildConfigurationArtifactDependency(buildConfID, artifactDependencyID string, artifactDependency *types.BuildArtifactDependency) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/artifact-dependencies/%s", c.version, buildConfID, artifactDependencyID)
+	var artifactDependencyReturn *types.BuildArtifactDependency
+
+	err := c.doRetryRequest("PUT", path, artifactDependency, &artifactDependencyReturn)
+	if err != nil {
+		return err
+	}
+
+	if artifactDependencyReturn == nil {
+		return errors.New("build configuration artifact dependency not updated")
+	}
+	*artifactDependency = *artifactDependencyReturn
+
+	return nil
+}
+
+func (c *Client) DeleteBuildConfigurationArtifactDependency(buildConfID, artifactDependencyID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/artifact-dependencies/%s", c.version, buildConfID, artifactDependencyID)
+	return c.doRetryRequest("DELETE", path, nil, nil)
+}
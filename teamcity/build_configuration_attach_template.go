@@ -0,0 +1,42 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// AttachBuildConfigurationTemplate adds a template after any templates already
+// attached to the build configuration. Servers before 2017.2 only accept a
+// template when none is attached yet.
+func (c *Client) AttachBuildConfigurationTemplate(buildConfID, templateID string) error {
+	err := c.requireFeature(FeatureMultipleTemplates)
+	if _, ok := err.(*UnsupportedError); ok {
+		templates, getErr := c.getBuildConfigurationTemplate(buildConfID)
+		if getErr != nil {
+			return getErr
+		}
+		if len(templates) > 0 && string(templates[0]) != templateID {
+			return err
+		}
+		return c.SetBuildConfigurationTemplate(buildConfID, templateID)
+	}
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/templates", c.version, buildConfID)
+	var templateReturn *types.BuildConfigurationShort
+
+	err = c.doRetryRequest("POST", path, types.TemplateId(templateID), &templateReturn)
+	if err != nil {
+		return err
+	}
+
+	if templateReturn == nil {
+		return errors.New("build configuration template not attached")
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package teamcity
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAttachBuildConfigurationTemplateMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id":"Empty_Tempy","name":"Tempy","projectId":"Empty","templateFlag":true}`), nil)
+
+	err := client.AttachBuildConfigurationTemplate("Empty_Build", "Empty_Tempy")
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/buildTypes/id:Empty_Build/templates", req.URL.Path)
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, `{"id":"Empty_Tempy"}`, string(body))
+}
+
+func TestClientReplaceAllBuildConfigurationTemplatesMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":2,"buildType":[{"id":"Empty_Second"},{"id":"Empty_First"}]}`), nil)
+
+	templates := &types.TemplateIds{"Empty_Second", "Empty_First"}
+	err := client.ReplaceAllBuildConfigurationTemplates("Empty_Build", templates)
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "PUT", req.Method)
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, `{"buildType":[{"id":"Empty_Second"},{"id":"Empty_First"}]}`, string(body))
+	assert.Equal(t, &types.TemplateIds{"Empty_Second", "Empty_First"}, templates)
+}
+
+func TestClientGetTemplateUsagesMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":1,"buildType":[{"id":"Empty_Build","name":"Build","projectName":"Empty","projectId":"Empty"}]}`), nil)
+
+	usages, err := client.GetTemplateUsages("Empty_Tempy")
+	require.NoError(t, err, "Expected no error")
+
+	assert.Equal(t, "template:(id:Empty_Tempy)", lastRequest(client).URL.Query().Get("locator"))
+	require.Equal(t, 1, len(usages))
+	assert.Equal(t, "Empty_Build", usages[0].ID)
+
+	client = NewTestClient(newResponse(`{"count":0}`), nil)
+	_, err = client.GetTemplateUsages("Empty_Tempy&x=1")
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, "template:(id:Empty_Tempy&x=1)", lastRequest(client).URL.Query().Get("locator"))
+}
+
+func TestClientAttachBuildConfigurationTemplates(t *testing.T) {
+	client, err := NewRealTestClient(t)
+	require.NoError(t, err, "Expected no error")
+	client.SkipOlder(t, 2017, 2)
+	for _, id := range []string{"Empty_TestClientAttachTemplates", "Empty_TestClientAttachTemplatesFirst", "Empty_TestClientAttachTemplatesSecond"} {
+		err = client.DeleteBuildConfiguration(id)
+		require.NoError(t, err, "Expected no error")
+	}
+	for _, name := range []string{"TestClientAttachTemplatesFirst", "TestClientAttachTemplatesSecond"} {
+		config := &types.BuildConfiguration{
+			ProjectID:    "Empty",
+			Name:         name,
+			TemplateFlag: true,
+		}
+		err = client.CreateBuildConfiguration(config)
+		require.NoError(t, err, "Expected no error")
+	}
+	config := &types.BuildConfiguration{
+		ProjectID: "Empty",
+		Name:      "TestClientAttachTemplates",
+	}
+	err = client.CreateBuildConfiguration(config)
+	require.NoError(t, err, "Expected no error")
+
+	err = client.AttachBuildConfigurationTemplate("Empty_TestClientAttachTemplates", "Empty_TestClientAttachTemplatesFirst")
+	require.NoError(t, err, "Expected no error")
+	err = client.AttachBuildConfigurationTemplate("Empty_TestClientAttachTemplates", "Empty_TestClientAttachTemplatesSecond")
+	require.NoError(t, err, "Expected no error")
+
+	templates, err := client.GetBuildConfigurationTemplates("Empty_TestClientAttachTemplates")
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, types.TemplateIds{"Empty_TestClientAttachTemplatesFirst", "Empty_TestClientAttachTemplatesSecond"}, templates)
+
+	err = client.DetachBuildConfigurationTemplate("Empty_TestClientAttachTemplates", "Empty_TestClientAttachTemplatesFirst")
+	require.NoError(t, err, "Expected no error")
+
+	usages, err := client.GetTemplateUsages("Empty_TestClientAttachTemplatesFirst")
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, 0, len(usages))
+}
@@ -0,0 +1,35 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// CopyBuildConfiguration creates a copy of a build configuration in the target
+// project using TeamCity's native copy, which keeps VCS root entries and
+// generates fresh IDs for steps and other settings. When
+// copyAllAssociatedSettings is set, project-level entities the configuration
+// uses, such as VCS roots, are copied along with it.
+func (c *Client) CopyBuildConfiguration(srcID, targetProjectID, newName, newID string, copyAllAssociatedSettings bool) (*types.BuildConfiguration, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/buildTypes", c.version, targetProjectID)
+	var buildConfigReturn *types.BuildConfiguration
+	description := types.NewBuildTypeDescription{
+		Name:                      newName,
+		ID:                        newID,
+		SourceBuildTypeLocator:    fmt.Sprintf("id:%s", srcID),
+		CopyAllAssociatedSettings: copyAllAssociatedSettings,
+	}
+
+	err := c.doRetryRequest("POST", path, description, &buildConfigReturn)
+	if err != nil {
+		return nil, err
+	}
+
+	if buildConfigReturn == nil {
+		return nil, errors.New("build configuration not copied")
+	}
+
+	return buildConfigReturn, nil
+}
@@ -7,11 +7,29 @@ import (
 	"github.com/icelander/teamcity-sdk-go/types"
 )
 
+// CreateBuildConfiguration creates a build configuration or template. On
+// servers before 2017.2 a single entry in Templates is sent as TemplateID.
 func (c *Client) CreateBuildConfiguration(buildConfig *types.BuildConfiguration) error {
 	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes", c.version)
 	var buildConfigReturn *types.BuildConfiguration
 
-	err := c.doRetryRequest("POST", path, buildConfig, &buildConfigReturn)
+	request := buildConfig
+	if len(buildConfig.Templates) > 0 {
+		err := c.requireFeature(FeatureMultipleTemplates)
+		if _, ok := err.(*UnsupportedError); ok {
+			if len(buildConfig.Templates) > 1 {
+				return err
+			}
+			single := *buildConfig
+			single.TemplateID = buildConfig.Templates[0]
+			single.Templates = nil
+			request = &single
+		} else if err != nil {
+			return err
+		}
+	}
+
+	err := c.doRetryRequest("POST", path, request, &buildConfigReturn)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,25 @@
+package teamcity
+
+import (
+	"fmt"
+)
+
+// DetachBuildConfigurationTemplate removes a template from the build
+// configuration, keeping the other templates attached. Detaching a template
+// that is not attached does nothing on servers before 2017.2.
+func (c *Client) DetachBuildConfigurationTemplate(buildConfID, templateID string) error {
+	err := c.requireFeature(FeatureMultipleTemplates)
+	if _, ok := err.(*UnsupportedError); ok {
+		templates, err := c.getBuildConfigurationTemplate(buildConfID)
+		if err != nil || len(templates) == 0 || string(templates[0]) != templateID {
+			return err
+		}
+		return c.SetBuildConfigurationTemplate(buildConfID, "")
+	}
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/templates/id:%s", c.version, buildConfID, templateID)
+	return c.doRetryRequest("DELETE", path, nil, nil)
+}
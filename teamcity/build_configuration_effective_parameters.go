@@ -0,0 +1,78 @@
+package teamcity
+
+import (
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// ResolveEffectiveParameters computes the parameters a build of the given
+// configuration sees. Parameters are merged from the root project down the
+// ancestry, then from the attached templates and finally from the build
+// configuration itself, recording where each value came from.
+func (c *Client) ResolveEffectiveParameters(buildTypeID string) (types.EffectiveParameters, error) {
+	config, err := c.GetBuildConfiguration(buildTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("build configuration %s not found", buildTypeID)
+	}
+
+	projects, err := c.getProjectAncestry(config.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := make([]types.ParameterLayer, 0, len(projects)+2)
+	for idx := len(projects) - 1; idx >= 0; idx-- {
+		layers = append(layers, types.ParameterLayer{
+			Origin:     types.ParameterOrigin{Source: types.ProjectSource, ID: projects[idx].ID},
+			Parameters: projects[idx].Parameters,
+		})
+	}
+
+	templateIDs := config.Templates
+	if len(templateIDs) == 0 && config.TemplateID != "" {
+		templateIDs = types.TemplateIds{config.TemplateID}
+	}
+	// Earlier templates take priority, so they are merged last
+	for idx := len(templateIDs) - 1; idx >= 0; idx-- {
+		templateID := string(templateIDs[idx])
+		template, err := c.GetBuildConfiguration(templateID)
+		if err != nil {
+			return nil, err
+		}
+		if template == nil {
+			return nil, fmt.Errorf("template %s not found", templateID)
+		}
+		layers = append(layers, types.ParameterLayer{
+			Origin:     types.ParameterOrigin{Source: types.TemplateSource, ID: templateID},
+			Parameters: template.Parameters,
+		})
+	}
+
+	layers = append(layers, types.ParameterLayer{
+		Origin:     types.ParameterOrigin{Source: types.BuildTypeSource, ID: config.ID},
+		Parameters: config.Parameters,
+	})
+
+	return types.MergeParameterLayers(layers...), nil
+}
+
+// getProjectAncestry returns the project and its ancestors, closest first
+func (c *Client) getProjectAncestry(projectID string) ([]*types.Project, error) {
+	projects := make([]*types.Project, 0)
+	for id := projectID; id != ""; {
+		project, err := c.GetProject(id)
+		if err != nil {
+			return nil, err
+		}
+		if project == nil {
+			return nil, fmt.Errorf("project %s not found", id)
+		}
+		projects = append(projects, project)
+		id = string(project.ParentProjectID)
+	}
+	return projects, nil
+}
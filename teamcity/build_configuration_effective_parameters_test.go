@@ -0,0 +1,53 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientResolveEffectiveParameters(t *testing.T) {
+	client, err := NewRealTestClient(t)
+	require.NoError(t, err, "Expected no error")
+	err = client.DeleteProject("Empty2")
+	require.NoError(t, err, "Expected no error")
+	err = client.CreateProject(&types.Project{
+		Name: "Empty2",
+	})
+	require.NoError(t, err, "Expected no error")
+	err = client.ReplaceAllProjectParameters("Empty2", &types.Parameters{
+		"env.AWW":  types.Parameter{Value: "Parent"},
+		"env.DAMM": types.Parameter{Value: "Parent"},
+	})
+	require.NoError(t, err, "Expected no error")
+	err = client.CreateBuildConfiguration(&types.BuildConfiguration{
+		ID:        "Empty2_TestClientResolveEffectiveParameters",
+		ProjectID: "Empty2",
+		Name:      "Test Client Resolve Effective Parameters",
+		Parameters: types.Parameters{
+			"env.DAMM": types.Parameter{Value: "BuildConf"},
+		},
+	})
+	require.NoError(t, err, "Expected no error")
+
+	effective, err := client.ResolveEffectiveParameters("Empty2_TestClientResolveEffectiveParameters")
+	require.NoError(t, err, "Expected no error")
+
+	project := types.ParameterOrigin{Source: types.ProjectSource, ID: "Empty2"}
+	buildType := types.ParameterOrigin{Source: types.BuildTypeSource, ID: "Empty2_TestClientResolveEffectiveParameters"}
+	assert.Equal(t, "Parent", effective["env.AWW"].Value)
+	assert.Equal(t, project, effective["env.AWW"].Origin)
+	assert.Equal(t, "BuildConf", effective["env.DAMM"].Value)
+	assert.Equal(t, buildType, effective["env.DAMM"].Origin)
+	assert.Equal(t, []types.ParameterOrigin{project}, effective["env.DAMM"].Overridden)
+}
+
+func TestClientResolveEffectiveParametersMissing(t *testing.T) {
+	client, err := NewRealTestClient(t)
+	require.NoError(t, err, "Expected no error")
+
+	_, err = client.ResolveEffectiveParameters("Empty2_Missing")
+	assert.EqualError(t, err, "build configuration Empty2_Missing not found")
+}
@@ -0,0 +1,72 @@
+package teamcity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+func (c *Client) GetBuildConfigurationFeature(buildConfID, featureID string) (*types.BuildFeature, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/features/%s", c.version, buildConfID, featureID)
+	var feature *types.BuildFeature
+
+	err := c.doRetryRequest("GET", path, nil, &feature)
+	if err != nil {
+		return nil, err
+	}
+
+	return feature, nil
+}
+
+func (c *Client) AddBuildConfigurationFeature(buildConfID string, feature *types.BuildFeature) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/features", c.version, buildConfID)
+	var featureReturn *types.BuildFeature
+
+	err := c.doRetryRequest("POST", path, feature, &featureReturn)
+	if err != nil {
+		return err
+	}
+
+	if featureReturn == nil {
+		return errors.New("build configuration feature not created")
+	}
+	*feature = *featureReturn
+
+	return nil
+}
+
+func (c *Client) ReplaceBuildConfigurationFeature(buildConfID, featureID string, feature *types.BuildFeature) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/features/%s", c.version, buildConfID, featureID)
+	var featureReturn *types.BuildFeature
+
+	err := c.doRetryRequest("PUT", path, feature, &featureReturn)
+	if err != nil {
+		return err
+	}
+
+	if featureReturn == nil {
+		return errors.New("build configuration feature not updated")
+	}
+	*feature = *featureReturn
+
+	return nil
+}
+
+func (c *Client) DeleteBuildConfigurationFeature(buildConfID, featureID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/features/%s", c.version, buildConfID, featureID)
+	return c.doRetryRequest("DELETE", path, nil, nil)
+}
+
+func (c *Client) SetBuildConfigurationFeatureDisabled(buildConfID, featureID string, disabled bool) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/features/%s/disabled", c.version, buildConfID, featureID)
+
+	body := bytes.NewBuffer([]byte(strconv.FormatBool(disabled)))
+	_, err := c.doNotJSONRequest("PUT", path, "text/plain", "text/plain", body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
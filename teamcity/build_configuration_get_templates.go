@@ -0,0 +1,46 @@
+package teamcity
+
+import (
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetBuildConfigurationTemplates returns the templates attached to the build
+// configuration in order. Servers before 2017.2 return at most one.
+func (c *Client) GetBuildConfigurationTemplates(buildConfID string) (types.TemplateIds, error) {
+	err := c.requireFeature(FeatureMultipleTemplates)
+	if _, ok := err.(*UnsupportedError); ok {
+		return c.getBuildConfigurationTemplate(buildConfID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/templates", c.version, buildConfID)
+	var templates types.TemplateIds
+
+	err = c.doRetryRequest("GET", path, nil, &templates)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// getBuildConfigurationTemplate reads the single template of a server without
+// multiple template support
+func (c *Client) getBuildConfigurationTemplate(buildConfID string) (types.TemplateIds, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/template", c.version, buildConfID)
+	var template types.TemplateId
+
+	err := c.doRetryRequest("GET", path, nil, &template)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == "" {
+		return types.TemplateIds{}, nil
+	}
+	return types.TemplateIds{template}, nil
+}
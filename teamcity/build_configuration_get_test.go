@@ -16,7 +16,7 @@ func TestClientGetBuildConfigurationMock(t *testing.T) {
 	require.NotNil(t, config, "Create to return config")
 
 	assert.Equal(t, types.Parameters{
-		"build.counter": types.Parameter{"12", nil},
+		"build.counter": types.Parameter{Value: "12"},
 	}, config.Parameters, "Parameters")
 }
 
@@ -0,0 +1,25 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// MoveBuildConfiguration moves a build configuration to another project
+func (c *Client) MoveBuildConfiguration(buildConfID, targetProjectID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/project", c.version, buildConfID)
+	var projectReturn *types.ProjectShort
+
+	err := c.doRetryRequest("PUT", path, types.ProjectId(targetProjectID), &projectReturn)
+	if err != nil {
+		return err
+	}
+
+	if projectReturn == nil {
+		return errors.New("build configuration not moved")
+	}
+
+	return nil
+}
@@ -29,7 +29,7 @@ func TestClientAllReplaceBuildConfigurationParameters(t *testing.T) {
 	require.NoError(t, err, "Expected no error")
 
 	parameters := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"aws.hush": types.Parameter{
 			Value: "Bad Job",
 			Spec: &types.ParameterSpec{
@@ -42,7 +42,7 @@ func TestClientAllReplaceBuildConfigurationParameters(t *testing.T) {
 	require.NotNil(t, parameters, "Update to return parameters")
 
 	expected := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"aws.hush": types.Parameter{
 			Value: "",
 			Spec: &types.ParameterSpec{
@@ -81,7 +81,7 @@ func TestClientReplaceAllBuildConfigurationParametersInherited(t *testing.T) {
 			Value: "Parent",
 			Spec: &types.ParameterSpec{
 				Label: "AWW",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"env.DAMM": types.Parameter{
@@ -102,7 +102,7 @@ func TestClientReplaceAllBuildConfigurationParametersInherited(t *testing.T) {
 				Value: "Dink",
 				Spec: &types.ParameterSpec{
 					Label: "Buhhhhh",
-					Type:  types.TextType{"any"},
+					Type:  types.TextType{ValidationMode: "any"},
 				},
 			},
 			"env.AWW": types.Parameter{
@@ -116,7 +116,7 @@ func TestClientReplaceAllBuildConfigurationParametersInherited(t *testing.T) {
 	require.NoError(t, err, "Expected no error")
 
 	parameters := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"config.replace": types.Parameter{
 			Value: "Mink",
 			Spec: &types.ParameterSpec{
@@ -153,7 +153,7 @@ func TestClientReplaceAllBuildConfigurationParametersInherited(t *testing.T) {
 	require.NotNil(t, parameters, "Update to return parameters")
 
 	expected := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"config.replace": types.Parameter{
 			Value: "Mink",
 			Spec: &types.ParameterSpec{
@@ -173,7 +173,7 @@ func TestClientReplaceAllBuildConfigurationParametersInherited(t *testing.T) {
 			Value: "",
 			Spec: &types.ParameterSpec{
 				Label: "AWW",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"env.MUH": types.Parameter{
@@ -185,7 +185,8 @@ func TestClientReplaceAllBuildConfigurationParametersInherited(t *testing.T) {
 			},
 		},
 		"env.DAMM": types.Parameter{
-			Value: "Parent",
+			Value:     "Parent",
+			Inherited: true,
 		},
 	}
 	assert.Equal(t, expected, parameters)
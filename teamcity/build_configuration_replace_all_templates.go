@@ -0,0 +1,51 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// ReplaceAllBuildConfigurationTemplates sets the attached templates to exactly
+// the given list. It is also how templates are reordered. Servers before
+// 2017.2 accept at most one template.
+func (c *Client) ReplaceAllBuildConfigurationTemplates(buildConfID string, templates *types.TemplateIds) error {
+	err := c.requireFeature(FeatureMultipleTemplates)
+	if _, ok := err.(*UnsupportedError); ok {
+		if len(*templates) > 1 {
+			return err
+		}
+		templateID := ""
+		if len(*templates) == 1 {
+			templateID = string((*templates)[0])
+		}
+		if err := c.SetBuildConfigurationTemplate(buildConfID, templateID); err != nil {
+			return err
+		}
+		current, err := c.getBuildConfigurationTemplate(buildConfID)
+		if err != nil {
+			return err
+		}
+		*templates = current
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/templates", c.version, buildConfID)
+	var templatesReturn *types.TemplateIds
+
+	err = c.doRetryRequest("PUT", path, templates, &templatesReturn)
+	if err != nil {
+		return err
+	}
+
+	if templatesReturn == nil {
+		return errors.New("build configuration templates not updated")
+	}
+	*templates = *templatesReturn
+
+	return nil
+}
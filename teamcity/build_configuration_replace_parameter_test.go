@@ -18,7 +18,7 @@ func TestClientAllReplaceBuildConfigurationParameter(t *testing.T) {
 		ProjectID: "Single",
 		Name:      "TestClientAllReplaceBuildConfigurationParameter",
 		Parameters: types.Parameters{
-			"env.HELLO": types.Parameter{"Good job", nil},
+			"env.HELLO": types.Parameter{Value: "Good job"},
 			"env.MUH": types.Parameter{
 				Value: "Hello",
 				Spec: &types.ParameterSpec{
@@ -34,17 +34,17 @@ func TestClientAllReplaceBuildConfigurationParameter(t *testing.T) {
 		&types.Parameter{
 			Value: "Bad Job",
 			Spec: &types.ParameterSpec{
-				Type: types.TextType{"not_empty"},
+				Type: types.TextType{ValidationMode: "not_empty"},
 			},
 		})
 	require.NoError(t, err, "Expected no error")
 
 	expected := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"env.MUH": types.Parameter{
 			Value: "Bad Job",
 			Spec: &types.ParameterSpec{
-				Type: types.TextType{"not_empty"},
+				Type: types.TextType{ValidationMode: "not_empty"},
 			},
 		},
 	}
@@ -0,0 +1,59 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+func (c *Client) GetBuildConfigurationSnapshotDependency(buildConfID, snapshotDependencyID string) (*types.BuildSnapshotDependency, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/snapshot-dependencies/%s", c.version, buildConfID, snapshotDependencyID)
+	var snapshotDependency *types.BuildSnapshotDependency
+
+	err := c.doRetryRequest("GET", path, nil, &snapshotDependency)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshotDependency, nil
+}
+
+func (c *Client) AddBuildConfigurationSnapshotDependency(buildConfID string, snapshotDependency *types.BuildSnapshotDependency) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/snapshot-dependencies", c.version, buildConfID)
+	var snapshotDependencyReturn *types.BuildSnapshotDependency
+
+	err := c.doRetryRequest("POST", path, snapshotDependency, &snapshotDependencyReturn)
+	if err != nil {
+		return err
+	}
+
+	if snapshotDependencyReturn == nil {
+		return errors.New("build configuration snapshot dependency not created")
+	}
+	*snapshotDependency = *snapshotDependencyReturn
+
+	return nil
+}
+
+func (c *Client) ReplaceBuildConfigurationSnapshotDependency(buildConfID, snapshotDependencyID string, snapshotDependency *types.BuildSnapshotDependency) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/snapshot-dependencies/%s", c.version, buildConfID, snapshotDependencyID)
+	var snapshotDependencyReturn *types.BuildSnapshotDependency
+
+	err := c.doRetryRequest("PUT", path, snapshotDependency, &snapshotDependencyReturn)
+	if err != nil {
+		return err
+	}
+
+	if snapshotDependencyReturn == nil {
+		return errors.New("build configuration snapshot dependency not updated")
+	}
+	*snapshotDependency = *snapshotDependencyReturn
+
+	return nil
+}
+
+func (c *Client) DeleteBuildConfigurationSnapshotDependency(buildConfID, snapshotDependencyID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/snapshot-dependencies/%s", c.version, buildConfID, snapshotDependencyID)
+	return c.doRetryRequest("DELETE", path, nil, nil)
+}
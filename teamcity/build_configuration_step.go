@@ -0,0 +1,72 @@
+package teamcity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+func (c *Client) GetBuildConfigurationStep(buildConfID, stepID string) (*types.BuildStep, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/steps/%s", c.version, buildConfID, stepID)
+	var step *types.BuildStep
+
+	err := c.doRetryRequest("GET", path, nil, &step)
+	if err != nil {
+		return nil, err
+	}
+
+	return step, nil
+}
+
+func (c *Client) AddBuildConfigurationStep(buildConfID string, step *types.BuildStep) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/steps", c.version, buildConfID)
+	var stepReturn *types.BuildStep
+
+	err := c.doRetryRequest("POST", path, step, &stepReturn)
+	if err != nil {
+		return err
+	}
+
+	if stepReturn == nil {
+		return errors.New("build configuration step not created")
+	}
+	*step = *stepReturn
+
+	return nil
+}
+
+func (c *Client) ReplaceBuildConfigurationStep(buildConfID, stepID string, step *types.BuildStep) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/steps/%s", c.version, buildConfID, stepID)
+	var stepReturn *types.BuildStep
+
+	err := c.doRetryRequest("PUT", path, step, &stepReturn)
+	if err != nil {
+		return err
+	}
+
+	if stepReturn == nil {
+		return errors.New("build configuration step not updated")
+	}
+	*step = *stepReturn
+
+	return nil
+}
+
+func (c *Client) DeleteBuildConfigurationStep(buildConfID, stepID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/steps/%s", c.version, buildConfID, stepID)
+	return c.doRetryRequest("DELETE", path, nil, nil)
+}
+
+func (c *Client) SetBuildConfigurationStepDisabled(buildConfID, stepID string, disabled bool) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/steps/%s/disabled", c.version, buildConfID, stepID)
+
+	body := bytes.NewBuffer([]byte(strconv.FormatBool(disabled)))
+	_, err := c.doNotJSONRequest("PUT", path, "text/plain", "text/plain", body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package teamcity
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAddBuildConfigurationStepMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id":"RUNNER_2","name":"Echo","type":"simpleRunner","properties":{"property":[{"name":"script.content","value":"env"}]}}`), nil)
+
+	step := &types.BuildStep{
+		Name: "Echo",
+		Type: "simpleRunner",
+		Properties: types.Properties{
+			"script.content": "env",
+		},
+	}
+	err := client.AddBuildConfigurationStep("Single_Normal", step)
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/buildTypes/id:Single_Normal/steps", req.URL.Path)
+	assert.Equal(t, "RUNNER_2", step.ID, "Expected add to return ID")
+}
+
+func TestClientReplaceBuildConfigurationStepMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id":"RUNNER_1","name":"Echo","type":"simpleRunner"}`), nil)
+
+	step := &types.BuildStep{
+		ID:   "RUNNER_1",
+		Name: "Echo",
+		Type: "simpleRunner",
+	}
+	err := client.ReplaceBuildConfigurationStep("Single_Normal", "RUNNER_1", step)
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "PUT", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/buildTypes/id:Single_Normal/steps/RUNNER_1", req.URL.Path)
+}
+
+func TestClientReplaceBuildConfigurationStepMockNotUpdated(t *testing.T) {
+	client := NewTestClient(newResponse(`null`), nil)
+
+	err := client.ReplaceBuildConfigurationStep("Single_Normal", "RUNNER_1", &types.BuildStep{})
+	assert.EqualError(t, err, "build configuration step not updated")
+}
+
+func TestClientSetBuildConfigurationStepDisabledMock(t *testing.T) {
+	client := NewTestClient(newResponse(`true`), nil)
+
+	err := client.SetBuildConfigurationStepDisabled("Single_Normal", "RUNNER_1", true)
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "PUT", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/buildTypes/id:Single_Normal/steps/RUNNER_1/disabled", req.URL.Path)
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, "true", string(body))
+}
+
+func TestClientDeleteBuildConfigurationTriggerMock(t *testing.T) {
+	client := NewTestClient(newResponse(``), nil)
+
+	err := client.DeleteBuildConfigurationTrigger("Single_Normal", "TRIGGER_1")
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "DELETE", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/buildTypes/id:Single_Normal/triggers/TRIGGER_1", req.URL.Path)
+}
+
+func TestClientGetBuildConfigurationSnapshotDependencyMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id":"Single_Base","type":"snapshot_dependency","properties":{"property":[{"name":"run-build-on-the-same-agent","value":"false"}]},"source-buildType":{"id":"Single_Base","name":"Base","projectName":"Single","projectId":"Single"}}`), nil)
+
+	dep, err := client.GetBuildConfigurationSnapshotDependency("Single_Normal", "Single_Base")
+	require.NoError(t, err, "Expected no error")
+	require.NotNil(t, dep, "Get to return dependency")
+
+	assert.Equal(t, "/httpAuth/app/rest/latest/buildTypes/id:Single_Normal/snapshot-dependencies/Single_Base", lastRequest(client).URL.Path)
+	assert.Equal(t, "Single_Base", dep.SourceBuildType.ID)
+	assert.Equal(t, "false", dep.Properties["run-build-on-the-same-agent"])
+}
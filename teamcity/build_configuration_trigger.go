@@ -0,0 +1,72 @@
+package teamcity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+func (c *Client) GetBuildConfigurationTrigger(buildConfID, triggerID string) (*types.BuildTrigger, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/triggers/%s", c.version, buildConfID, triggerID)
+	var trigger *types.BuildTrigger
+
+	err := c.doRetryRequest("GET", path, nil, &trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
+
+func (c *Client) AddBuildConfigurationTrigger(buildConfID string, trigger *types.BuildTrigger) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/triggers", c.version, buildConfID)
+	var triggerReturn *types.BuildTrigger
+
+	err := c.doRetryRequest("POST", path, trigger, &triggerReturn)
+	if err != nil {
+		return err
+	}
+
+	if triggerReturn == nil {
+		return errors.New("build configuration trigger not created")
+	}
+	*trigger = *triggerReturn
+
+	return nil
+}
+
+func (c *Client) ReplaceBuildConfigurationTrigger(buildConfID, triggerID string, trigger *types.BuildTrigger) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/triggers/%s", c.version, buildConfID, triggerID)
+	var triggerReturn *types.BuildTrigger
+
+	err := c.doRetryRequest("PUT", path, trigger, &triggerReturn)
+	if err != nil {
+		return err
+	}
+
+	if triggerReturn == nil {
+		return errors.New("build configuration trigger not updated")
+	}
+	*trigger = *triggerReturn
+
+	return nil
+}
+
+func (c *Client) DeleteBuildConfigurationTrigger(buildConfID, triggerID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/triggers/%s", c.version, buildConfID, triggerID)
+	return c.doRetryRequest("DELETE", path, nil, nil)
+}
+
+func (c *Client) SetBuildConfigurationTriggerDisabled(buildConfID, triggerID string, disabled bool) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes/id:%s/triggers/%s/disabled", c.version, buildConfID, triggerID)
+
+	body := bytes.NewBuffer([]byte(strconv.FormatBool(disabled)))
+	_, err := c.doNotJSONRequest("PUT", path, "text/plain", "text/plain", body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+const buildListPageSize = 100
+
+const buildListFields = "count,nextHref,build(id,buildTypeId,number,status,statusText,state,branchName,personal,href,webUrl,queuedDate,startDate,finishDate,buildType(id,name,projectName,projectId),agent(id,name),triggered(*))"
+
+// GetRunningBuilds returns the running builds on all branches
+func (c *Client) GetRunningBuilds() ([]*types.Build, error) {
+	return c.listBuilds("running:true,defaultFilter:false")
+}
+
+// GetFinishedBuildsSince returns the builds on all branches that finished
+// after the build with the given ID, or after the date when buildID is 0,
+// ordered by ID
+func (c *Client) GetFinishedBuildsSince(buildID int64, since time.Time) ([]*types.Build, error) {
+	locator := "state:finished,defaultFilter:false,"
+	if buildID > 0 {
+		locator += fmt.Sprintf("sinceBuild:(id:%d)", buildID)
+	} else {
+		locator += fmt.Sprintf("sinceDate:%s", since.Format("20060102T150405-0700"))
+	}
+	builds, err := c.listBuilds(locator)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].ID < builds[j].ID })
+	return builds, nil
+}
+
+// listBuilds returns all builds matching the locator, following nextHref
+// through every page
+func (c *Client) listBuilds(locator string) ([]*types.Build, error) {
+	locator = fmt.Sprintf("%s,count:%d", locator, buildListPageSize)
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/builds?locator=%s&fields=%s", c.version, url.QueryEscape(locator), buildListFields)
+
+	builds := []*types.Build{}
+	for path != "" {
+		var page struct {
+			Count    int64
+			NextHref string
+			Build    []*types.Build
+		}
+		err := c.doRetryRequest("GET", path, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		builds = append(builds, page.Build...)
+
+		path = ""
+		if page.NextHref != "" && len(page.Build) > 0 {
+			path = page.NextHref
+			if !strings.HasPrefix(path, "/httpAuth/") {
+				path = "/httpAuth" + path
+			}
+		}
+	}
+	return builds, nil
+}
@@ -10,11 +10,16 @@ import (
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/icelander/teamcity-sdk-go/types"
 )
 
+// ErrBuildNotFound is returned by GetBuild for a build the server does not
+// know
+var ErrBuildNotFound = errors.New("build not found")
+
 // Client to access a TeamCity API
 type Client struct {
 	HTTPClient *http.Client
@@ -23,6 +28,9 @@ type Client struct {
 	host       string
 	version    string
 	retries    int
+
+	serverVersionMu sync.Mutex
+	serverVersion   *ServerVersion
 }
 
 // New TeamCity client
@@ -155,7 +163,7 @@ func (c *Client) GetBuild(buildID string) (*types.Build, error) {
 	}
 
 	if build == nil {
-		return nil, errors.New("build not found")
+		return nil, ErrBuildNotFound
 	}
 
 	return build, nil
@@ -6,6 +6,7 @@ import (
 
 	"github.com/icelander/teamcity-sdk-go/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClientGetBuildProperties(t *testing.T) {
@@ -69,7 +70,7 @@ func TestClientGetAgentStats(t *testing.T) {
 	client := NewTestClient(newResponse(string(jsonFixture)), nil)
 
 	agents, err := client.GetAgentStats()
-	
+
 	if err != nil {
 		t.Fatal("Expected no error, got", err)
 	}
@@ -95,7 +96,7 @@ func TestClientGetAgentStatsNoBuild(t *testing.T) {
 	client := NewTestClient(newResponse(string(jsonFixture)), nil)
 
 	agents, err := client.GetAgentStats()
-	
+
 	if err != nil {
 		t.Fatal("Expected no error, got", err)
 	}
@@ -122,7 +123,7 @@ func TestClientGetBuildQueue(t *testing.T) {
 
 	builds, err := client.GetBuildQueue()
 	build := builds[0]
-	
+
 	if err != nil {
 		t.Fatal("Expected no error, got", err)
 	}
@@ -146,7 +147,7 @@ func TestClientGetBuildType(t *testing.T) {
 	client := NewTestClient(newResponse(string(jsonFixture)), nil)
 
 	buildType, err := client.GetBuildType("MattermostTeamcityPlugin_TestBuild")
-	
+
 	if err != nil {
 		t.Fatal("Expected no error, got", err)
 	}
@@ -164,7 +165,7 @@ func TestClientInvalidBuildType(t *testing.T) {
 	client := NewTestClient(newCodeResponse("404 (Not Found)", 404, respBody), nil)
 
 	buildType, err := client.GetBuildType("MattermostTeamcityPlugin_TestBuild")
-	
+
 	if err != nil {
 		t.Fatal("Expected no error, got", err)
 	}
@@ -186,10 +187,52 @@ func TestClientGetEmptyBuildQueue(t *testing.T) {
 	client := NewTestClient(newResponse(string(jsonFixture)), nil)
 
 	builds, err := client.GetBuildQueue()
-	
+
 	if err != nil {
 		t.Fatal("Expected no error, got", err)
 	}
 
 	assert.Equal(len(builds), 0)
-}
\ No newline at end of file
+}
+
+func TestClientRecordedProject(t *testing.T) {
+	client, done := NewFixtureClient(t, "TestClientRecordedProject")
+	defer done()
+
+	server, err := client.Server()
+	require.NoError(t, err)
+	assert.NotEmpty(t, server.Version)
+
+	project := &types.Project{Name: "Recorded Fixture", ParentProjectID: "_Root"}
+	require.NoError(t, client.CreateProject(project))
+	assert.Equal(t, "RecordedFixture", project.ID)
+
+	parameter := types.Parameter{Value: "hunter2", Spec: &types.ParameterSpec{Type: types.PasswordType{}}}
+	require.NoError(t, client.ReplaceProjectParameter(project.ID, "deploy.password", &parameter))
+
+	actual, err := client.GetProject(project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Recorded Fixture", actual.Name)
+	assert.Equal(t, types.ProjectId("_Root"), actual.ParentProjectID)
+	require.Contains(t, actual.Parameters, "deploy.password")
+	assert.Equal(t, "", actual.Parameters["deploy.password"].Value)
+
+	require.NoError(t, client.DeleteProject(project.ID))
+	actual, err = client.GetProject(project.ID)
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+}
+
+func TestClientServerGlobalSettingsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"version":"2019.2 (build 71499)","versionMajor":2019,"versionMinor":2,
+		"webUrl":"https://teamcity.example.com","artifactsUrl":"https://artifacts.example.com",
+		"internalId":"c2e3e4b8-4a07-4a6e-9c4e-0d6f0a0b5b7e","role":"main_node"}`), nil)
+
+	server, err := client.Server()
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://teamcity.example.com", server.WebURL)
+	assert.Equal(t, "https://artifacts.example.com", server.ArtifactsURL)
+	assert.Equal(t, "c2e3e4b8-4a07-4a6e-9c4e-0d6f0a0b5b7e", server.InternalID)
+	assert.Equal(t, "main_node", server.Role)
+}
@@ -0,0 +1,27 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetHealthItems returns the server health items selected by the filter
+func (c *Client) GetHealthItems(filter types.HealthFilter) ([]types.HealthItem, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/health", c.version)
+	if locator := filter.Locator(); locator != "" {
+		path += "?locator=" + url.QueryEscape(locator)
+	}
+	var items struct {
+		Count      int
+		HealthItem []types.HealthItem
+	}
+
+	err := c.doRetryRequest("GET", path, nil, &items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items.HealthItem, nil
+}
@@ -0,0 +1,33 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetHealthItemsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":2,"healthItem":[
+		{"identity":"unusedVcsRoot_Empty_Git","severity":"WARN",
+		 "healthCategory":{"id":"unusedVcsRoots","name":"Unused VCS roots"},
+		 "project":{"id":"Empty"},"vcsRoot":{"id":"Empty_Git"}},
+		{"identity":"disconnectedAgent_3","severity":"ERROR",
+		 "healthCategory":{"id":"disconnectedAgents","name":"Disconnected agents"},
+		 "agent":{"id":3,"name":"agent-3"}}
+	]}`), nil)
+
+	items, err := client.GetHealthItems(types.HealthFilter{MinimumSeverity: types.HealthWarning, ProjectID: "Empty"})
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "/httpAuth/app/rest/latest/health", req.URL.Path)
+	assert.Equal(t, "minimumSeverity:WARN,affectedProject:(id:Empty)", req.URL.Query().Get("locator"))
+	require.Equal(t, 2, len(items))
+	assert.Equal(t, "unusedVcsRoots", items[0].Category.ID)
+	assert.Equal(t, types.ProjectId("Empty"), items[0].Project)
+	assert.Equal(t, types.VcsRootId("Empty_Git"), items[0].VcsRoot)
+	assert.Equal(t, types.HealthError, items[1].Severity)
+	assert.Equal(t, "agent-3", items[1].Agent.Name)
+}
@@ -2,8 +2,12 @@ package teamcity
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/httpfixture"
 )
 
 func NewTestClient(replyResp *http.Response, err error) *Client {
@@ -13,6 +17,8 @@ func NewTestClient(replyResp *http.Response, err error) *Client {
 		host:     "host.example.com",
 		version:  "latest",
 		retries:  8,
+		// Known up front so version checks do not consume the mock response
+		serverVersion: &ServerVersion{Major: 2019, Minor: 2},
 	}
 	httpClient := &http.Client{}
 	httpClient.Transport = &MockTransport{
@@ -48,3 +54,39 @@ func newCodeResponse(status string, httpCode int, body string) *http.Response {
 
 	return resp
 }
+
+func lastRequest(client *Client) *http.Request {
+	return client.HTTPClient.Transport.(*MockTransport).req
+}
+
+// NewFixtureClient returns a client answering from the recorded fixture
+// fixtures/recorded/<name>.json. With -record the client talks to the server
+// given by -host instead and the fixture is rewritten when done is called.
+func NewFixtureClient(t *testing.T, name string) (client *Client, done func()) {
+	path := fmt.Sprintf("../fixtures/recorded/%s.json", name)
+	if *record {
+		client, err := NewRealTestClient(t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorder := httpfixture.NewRecorder(client.HTTPClient.Transport)
+		client.HTTPClient.Transport = recorder
+		return client, func() {
+			if err := recorder.Save(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	replayer, err := httpfixture.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = NewTestClient(nil, nil)
+	client.HTTPClient.Transport = replayer
+	return client, func() {
+		for _, interaction := range replayer.Unused() {
+			t.Errorf("Request not replayed: %s %s", interaction.Request.Method, interaction.Request.Path)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// CopyProject creates a copy of a project, including its subprojects and build
+// configurations, below the given parent project.
+func (c *Client) CopyProject(srcID, parentProjectID, newName, newID string, copyAllAssociatedSettings bool) (*types.Project, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects", c.version)
+	var projectReturn *types.Project
+	description := types.NewProjectDescription{
+		Name: newName,
+		ID:   newID,
+		ParentProject: &types.ProjectLocator{
+			Locator: fmt.Sprintf("id:%s", parentProjectID),
+		},
+		SourceProject: &types.ProjectLocator{
+			Locator: fmt.Sprintf("id:%s", srcID),
+		},
+		CopyAllAssociatedSettings: copyAllAssociatedSettings,
+	}
+
+	err := c.doRetryRequest("POST", path, description, &projectReturn)
+	if err != nil {
+		return nil, err
+	}
+
+	if projectReturn == nil {
+		return nil, errors.New("project not copied")
+	}
+
+	return projectReturn, nil
+}
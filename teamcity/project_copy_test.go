@@ -0,0 +1,62 @@
+package teamcity
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCopyProjectMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id": "Empty_Copy", "parentProjectId":"Empty","name":"Copy"}`), nil)
+
+	project, err := client.CopyProject("Single", "Empty", "Copy", "Empty_Copy", true)
+	require.NoError(t, err, "Expected no error")
+	require.NotNil(t, project, "Copy to return project")
+
+	req := lastRequest(client)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/projects", req.URL.Path)
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err, "Expected no error")
+	assert.JSONEq(t, `{
+		"name": "Copy",
+		"id": "Empty_Copy",
+		"parentProject": {"locator": "id:Empty"},
+		"sourceProject": {"locator": "id:Single"},
+		"copyAllAssociatedSettings": true
+	}`, string(body))
+	assert.Equal(t, "Empty_Copy", project.ID, "Expected copy to return ID")
+}
+
+func TestClientCopyBuildConfigurationMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id": "Empty_Normal", "projectId":"Empty","name":"Normal"}`), nil)
+
+	config, err := client.CopyBuildConfiguration("Single_Normal", "Empty", "Normal", "Empty_Normal", false)
+	require.NoError(t, err, "Expected no error")
+	require.NotNil(t, config, "Copy to return config")
+
+	req := lastRequest(client)
+	assert.Equal(t, "/httpAuth/app/rest/latest/projects/id:Empty/buildTypes", req.URL.Path)
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err, "Expected no error")
+	assert.JSONEq(t, `{
+		"name": "Normal",
+		"id": "Empty_Normal",
+		"sourceBuildTypeLocator": "id:Single_Normal",
+		"copyAllAssociatedSettings": false
+	}`, string(body))
+	assert.Equal(t, "Empty_Normal", config.ID, "Expected copy to return ID")
+}
+
+func TestClientMoveProjectMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id": "Single_Moved", "parentProjectId":"Single","name":"Moved"}`), nil)
+
+	err := client.MoveProject("Empty_Moved", "Single")
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "PUT", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/projects/id:Empty_Moved/parentProject", req.URL.Path)
+}
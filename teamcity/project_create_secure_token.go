@@ -0,0 +1,29 @@
+package teamcity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// CreateSecureToken stores a secret in the project and returns the token to
+// use in its place in parameters and settings. It needs TeamCity 2017.1 or
+// newer.
+func (c *Client) CreateSecureToken(projectID, value string) (types.SecureValue, error) {
+	if err := c.requireFeature(FeatureSecureTokens); err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/secure/tokens", c.version, projectID)
+
+	body := bytes.NewBuffer([]byte(value))
+	ret, err := c.doNotJSONRequest("POST", path, "text/plain", "text/plain", body)
+	if err != nil {
+		return "", err
+	}
+	if ret == nil {
+		return "", errors.New("secure token not created")
+	}
+	return types.ParseSecureValue(string(ret))
+}
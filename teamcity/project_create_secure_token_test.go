@@ -0,0 +1,36 @@
+package teamcity
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCreateSecureTokenMock(t *testing.T) {
+	client := NewTestClient(newResponse(`credentialsJSON:c3e1f2d0-17a4`), nil)
+
+	token, err := client.CreateSecureToken("Empty", "hunter2")
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, types.SecureValue("c3e1f2d0-17a4"), token)
+
+	req := lastRequest(client)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/projects/id:Empty/secure/tokens", req.URL.Path)
+	assert.Equal(t, "text/plain", req.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, "hunter2", string(body))
+}
+
+func TestClientCreateSecureToken(t *testing.T) {
+	client, err := NewRealTestClient(t)
+	require.NoError(t, err, "Expected no error")
+	client.SkipOlder(t, 2017, 1)
+
+	token, err := client.CreateSecureToken("_Root", "hunter2")
+	require.NoError(t, err, "Expected no error")
+	assert.True(t, types.IsSecureReference(token.String()), "Expected a secure token")
+}
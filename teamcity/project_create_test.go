@@ -45,7 +45,8 @@ func TestClientCreateProjectMinimal(t *testing.T) {
 	assert.Equal(t, "", project.Description, "Expected create to return Description")
 	assert.Equal(t, types.Parameters{
 		"env.MUH": types.Parameter{
-			Value: client.VersionParameterValue(t, "env.MUH"),
+			Value:     client.VersionParameterValue(t, "env.MUH"),
+			Inherited: true,
 			Spec: &types.ParameterSpec{
 				Label:       "Muh value",
 				Description: "The Muh value that does all the Muhing",
@@ -63,7 +64,8 @@ func TestClientCreateProjectMinimal(t *testing.T) {
 	assert.Equal(t, "", project.Description, "Expected Empty to return Description")
 	assert.Equal(t, types.Parameters{
 		"env.MUH": types.Parameter{
-			Value: client.VersionParameterValue(t, "env.MUH"),
+			Value:     client.VersionParameterValue(t, "env.MUH"),
+			Inherited: true,
 			Spec: &types.ParameterSpec{
 				Label:       "Muh value",
 				Description: "The Muh value that does all the Muhing",
@@ -97,7 +99,8 @@ func TestClientCreateProjectIgnoresParameters(t *testing.T) {
 	assert.Equal(t, "Empty_Full", project.ID, "Expected create to return ID")
 	assert.Equal(t, types.Parameters{
 		"env.MUH": types.Parameter{
-			Value: client.VersionParameterValue(t, "env.MUH"),
+			Value:     client.VersionParameterValue(t, "env.MUH"),
+			Inherited: true,
 			Spec: &types.ParameterSpec{
 				Label:       "Muh value",
 				Description: "The Muh value that does all the Muhing",
@@ -0,0 +1,212 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// ProjectDeletionReport lists what deleting a project would remove
+type ProjectDeletionReport struct {
+	ProjectID              string
+	ChildProjects          []string
+	BuildConfigurations    []string
+	Templates              []string
+	VcsRoots               []string
+	Builds                 int
+	ExternalDependents     []ExternalSnapshotDependency
+	ExternalTemplateUsages []ExternalTemplateUsage
+}
+
+// ExternalSnapshotDependency is a build configuration outside the deleted
+// subtree with a snapshot dependency on a build configuration inside it.
+type ExternalSnapshotDependency struct {
+	BuildConfigurationID string
+	DependentID          string
+	DependentProjectID   string
+}
+
+// ExternalTemplateUsage is a build configuration outside the deleted subtree
+// attached to a template inside it.
+type ExternalTemplateUsage struct {
+	TemplateID           string
+	BuildConfigurationID string
+	ProjectID            string
+}
+
+func (r *ProjectDeletionReport) String() string {
+	lines := []string{
+		fmt.Sprintf("Deleting project %s removes:", r.ProjectID),
+		fmt.Sprintf("  %d child projects %s", len(r.ChildProjects), strings.Join(r.ChildProjects, ", ")),
+		fmt.Sprintf("  %d build configurations %s", len(r.BuildConfigurations), strings.Join(r.BuildConfigurations, ", ")),
+		fmt.Sprintf("  %d templates %s", len(r.Templates), strings.Join(r.Templates, ", ")),
+		fmt.Sprintf("  %d VCS roots %s", len(r.VcsRoots), strings.Join(r.VcsRoots, ", ")),
+		fmt.Sprintf("  %d builds", r.Builds),
+	}
+	for _, dep := range r.ExternalDependents {
+		lines = append(lines, fmt.Sprintf("  %s (project %s) depends on %s", dep.DependentID, dep.DependentProjectID, dep.BuildConfigurationID))
+	}
+	for _, usage := range r.ExternalTemplateUsages {
+		lines = append(lines, fmt.Sprintf("  %s (project %s) uses template %s", usage.BuildConfigurationID, usage.ProjectID, usage.TemplateID))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PreviewProjectDeletion reports what DeleteProject would remove without
+// deleting anything.
+func (c *Client) PreviewProjectDeletion(projectID string) (*ProjectDeletionReport, error) {
+	tree, err := c.GetProjectTree(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ProjectDeletionReport{
+		ProjectID:              projectID,
+		ChildProjects:          make([]string, 0),
+		BuildConfigurations:    make([]string, 0),
+		Templates:              make([]string, 0),
+		ExternalDependents:     make([]ExternalSnapshotDependency, 0),
+		ExternalTemplateUsages: make([]ExternalTemplateUsage, 0),
+	}
+	inSubtree := make(map[string]bool)
+	tree.Walk(func(node *types.ProjectTree, depth int) error {
+		inSubtree[node.Project.ID] = true
+		if depth > 0 {
+			report.ChildProjects = append(report.ChildProjects, node.Project.ID)
+		}
+		for id := range node.Project.BuildConfigurations {
+			report.BuildConfigurations = append(report.BuildConfigurations, id)
+		}
+		for id := range node.Project.Templates {
+			report.Templates = append(report.Templates, id)
+		}
+		return nil
+	}, nil)
+	sort.Strings(report.BuildConfigurations)
+	sort.Strings(report.Templates)
+
+	vcsRoots, err := c.GetVcsRoots(fmt.Sprintf("affectedProject:(id:%s)", projectID))
+	if err != nil {
+		return nil, err
+	}
+	report.VcsRoots = make([]string, 0, len(vcsRoots))
+	for _, vcsRoot := range vcsRoots {
+		report.VcsRoots = append(report.VcsRoots, vcsRoot.ID)
+	}
+	sort.Strings(report.VcsRoots)
+
+	report.Builds, err = c.countProjectBuilds(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	buildConfIDs := make([]string, 0, len(report.BuildConfigurations)+len(report.Templates))
+	buildConfIDs = append(buildConfIDs, report.BuildConfigurations...)
+	buildConfIDs = append(buildConfIDs, report.Templates...)
+	for _, buildConfID := range buildConfIDs {
+		dependents, err := c.getSnapshotDependents(buildConfID)
+		if err != nil {
+			return nil, err
+		}
+		for _, dependent := range dependents {
+			if !inSubtree[dependent.ProjectID] {
+				report.ExternalDependents = append(report.ExternalDependents, ExternalSnapshotDependency{
+					BuildConfigurationID: buildConfID,
+					DependentID:          dependent.ID,
+					DependentProjectID:   dependent.ProjectID,
+				})
+			}
+		}
+	}
+
+	for _, templateID := range report.Templates {
+		usages, err := c.GetTemplateUsages(templateID)
+		if err != nil {
+			return nil, err
+		}
+		for _, usage := range usages {
+			if !inSubtree[usage.ProjectID] {
+				report.ExternalTemplateUsages = append(report.ExternalTemplateUsages, ExternalTemplateUsage{
+					TemplateID:           templateID,
+					BuildConfigurationID: usage.ID,
+					ProjectID:            usage.ProjectID,
+				})
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// DeleteProjectGuarded deletes a project only if no build configuration
+// outside of it has a snapshot dependency on one inside it or is attached to
+// one of its templates. The report of what was, or would have been, removed
+// is returned either way.
+func (c *Client) DeleteProjectGuarded(projectID string) (*ProjectDeletionReport, error) {
+	report, err := c.PreviewProjectDeletion(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(report.ExternalDependents) > 0 {
+		return report, fmt.Errorf("project %s not deleted: %d build configurations outside the project depend on it", projectID, len(report.ExternalDependents))
+	}
+	if len(report.ExternalTemplateUsages) > 0 {
+		return report, fmt.Errorf("project %s not deleted: %d build configurations outside the project use its templates", projectID, len(report.ExternalTemplateUsages))
+	}
+
+	return report, c.DeleteProject(projectID)
+}
+
+const projectBuildsPageSize = 1000
+
+// countProjectBuilds counts the builds of the project and its subprojects,
+// following nextHref through every page
+func (c *Client) countProjectBuilds(projectID string) (int, error) {
+	locator := url.QueryEscape(fmt.Sprintf("affectedProject:(id:%s),defaultFilter:false,count:%d", projectID, projectBuildsPageSize))
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/builds?locator=%s&fields=count,nextHref", c.version, locator)
+
+	total := 0
+	for path != "" {
+		var builds struct {
+			Count    int
+			NextHref string
+		}
+		err := c.doRetryRequest("GET", path, nil, &builds)
+		if err != nil {
+			return 0, err
+		}
+		total += builds.Count
+
+		path = ""
+		if builds.NextHref != "" && builds.Count > 0 {
+			path = builds.NextHref
+			if !strings.HasPrefix(path, "/httpAuth/") {
+				path = "/httpAuth" + path
+			}
+		}
+	}
+	return total, nil
+}
+
+// getSnapshotDependents returns the build configurations with a snapshot
+// dependency on buildConfID. snapshotDependency:(to:X) would list the ones X
+// depends on instead.
+func (c *Client) getSnapshotDependents(buildConfID string) ([]types.BuildType, error) {
+	locator := url.QueryEscape(fmt.Sprintf("snapshotDependency:(from:(id:%s),recursive:false)", buildConfID))
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes?locator=%s", c.version, locator)
+	var buildTypes struct {
+		Count     int64
+		BuildType []types.BuildType
+	}
+
+	err := c.doRetryRequest("GET", path, nil, &buildTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTypes.BuildType, nil
+}
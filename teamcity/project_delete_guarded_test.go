@@ -0,0 +1,106 @@
+package teamcity
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/icelander/teamcity-sdk-go/httpfixture"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientSetProjectArchivedMock(t *testing.T) {
+	client := NewTestClient(newResponse(`true`), nil)
+
+	err := client.ArchiveProject("Empty_Old")
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "PUT", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/projects/id:Empty_Old/archived", req.URL.Path)
+}
+
+func TestClientDeleteProjectGuarded(t *testing.T) {
+	client, err := NewRealTestClient(t)
+	require.NoError(t, err, "Expected no error")
+	err = client.DeleteProject("Empty_Guarded")
+	require.NoError(t, err, "Expected no error")
+	err = client.DeleteBuildConfiguration("Empty_GuardedDependent")
+	require.NoError(t, err, "Expected no error")
+	time.Sleep(5 * time.Second)
+
+	err = client.CreateProject(&types.Project{
+		ParentProjectID: "Empty",
+		Name:            "Guarded",
+	})
+	require.NoError(t, err, "Expected no error")
+	err = client.CreateBuildConfiguration(&types.BuildConfiguration{
+		ProjectID: "Empty_Guarded",
+		Name:      "Base",
+	})
+	require.NoError(t, err, "Expected no error")
+	err = client.CreateBuildConfiguration(&types.BuildConfiguration{
+		ProjectID: "Empty",
+		Name:      "GuardedDependent",
+		SnapshotDependencies: types.BuildSnapshotDependencies{
+			types.BuildSnapshotDependency{
+				ID:   "Empty_Guarded_Base",
+				Type: "snapshot_dependency",
+				SourceBuildType: types.BuildType{
+					ID: "Empty_Guarded_Base",
+				},
+			},
+		},
+	})
+	require.NoError(t, err, "Expected no error")
+
+	report, err := client.DeleteProjectGuarded("Empty_Guarded")
+	require.Error(t, err, "Expected delete to be refused")
+	require.NotNil(t, report, "Expected report")
+	assert.Equal(t, []string{"Empty_Guarded_Base"}, report.BuildConfigurations)
+	assert.Equal(t, []ExternalSnapshotDependency{{
+		BuildConfigurationID: "Empty_Guarded_Base",
+		DependentID:          "Empty_GuardedDependent",
+		DependentProjectID:   "Empty",
+	}}, report.ExternalDependents)
+
+	err = client.DeleteBuildConfiguration("Empty_GuardedDependent")
+	require.NoError(t, err, "Expected no error")
+
+	_, err = client.DeleteProjectGuarded("Empty_Guarded")
+	require.NoError(t, err, "Expected no error")
+	project, err := client.GetProject("Empty_Guarded")
+	require.NoError(t, err, "Expected no error")
+	assert.Nil(t, project, "Expected project to be deleted")
+}
+
+func TestClientGetSnapshotDependentsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":1,"buildType":[{"id":"Other_Build","projectId":"Other"}]}`), nil)
+
+	dependents, err := client.getSnapshotDependents("App_Build")
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, "snapshotDependency:(from:(id:App_Build),recursive:false)", lastRequest(client).URL.Query().Get("locator"))
+	require.Equal(t, 1, len(dependents))
+	assert.Equal(t, "Other_Build", dependents[0].ID)
+}
+
+func TestClientCountProjectBuildsPages(t *testing.T) {
+	locator := "affectedProject:(id:App),defaultFilter:false,count:1000"
+	next := "/app/rest/builds?locator=affectedProject:(id:App),defaultFilter:false,count:1000,start:1000&fields=count,nextHref"
+	client, replayer := newVersionTestClient(nil,
+		interaction("GET", "/builds?locator="+url.QueryEscape(locator)+"&fields=count,nextHref", "", 200,
+			`{"count":1000,"nextHref":"`+next+`"}`),
+		httpfixture.Interaction{
+			Request: httpfixture.Request{Method: "GET", Path: "/httpAuth" + next},
+			Response: httpfixture.Response{StatusCode: 200, ContentType: "application/json",
+				Body: httpfixture.Body(`{"count":234}`)},
+		},
+	)
+
+	count, err := client.countProjectBuilds("App")
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, 1234, count)
+	assert.Empty(t, replayer.Unused())
+}
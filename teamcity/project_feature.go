@@ -0,0 +1,71 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+func (c *Client) GetProjectFeatures(projectID string) (types.ProjectFeatures, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/projectFeatures", c.version, projectID)
+	var features types.ProjectFeatures
+
+	err := c.doRetryRequest("GET", path, nil, &features)
+	if err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+func (c *Client) GetProjectFeature(projectID, featureID string) (*types.ProjectFeature, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/projectFeatures/id:%s", c.version, projectID, featureID)
+	var feature *types.ProjectFeature
+
+	err := c.doRetryRequest("GET", path, nil, &feature)
+	if err != nil {
+		return nil, err
+	}
+
+	return feature, nil
+}
+
+func (c *Client) CreateProjectFeature(projectID string, feature *types.ProjectFeature) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/projectFeatures", c.version, projectID)
+	var featureReturn *types.ProjectFeature
+
+	err := c.doRetryRequest("POST", path, feature, &featureReturn)
+	if err != nil {
+		return err
+	}
+
+	if featureReturn == nil {
+		return errors.New("project feature not created")
+	}
+	*feature = *featureReturn
+
+	return nil
+}
+
+func (c *Client) ReplaceProjectFeature(projectID, featureID string, feature *types.ProjectFeature) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/projectFeatures/id:%s", c.version, projectID, featureID)
+	var featureReturn *types.ProjectFeature
+
+	err := c.doRetryRequest("PUT", path, feature, &featureReturn)
+	if err != nil {
+		return err
+	}
+
+	if featureReturn == nil {
+		return errors.New("project feature not updated")
+	}
+	*feature = *featureReturn
+
+	return nil
+}
+
+func (c *Client) DeleteProjectFeature(projectID, featureID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/projectFeatures/id:%s", c.version, projectID, featureID)
+	return c.doRetryRequest("DELETE", path, nil, nil)
+}
@@ -0,0 +1,70 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetProjectFeaturesMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":1,"projectFeature":[{"id":"PROJECT_EXT_1","type":"IssueTracker","properties":{"property":[{"name":"type","value":"GithubIssues"}]}}]}`), nil)
+
+	features, err := client.GetProjectFeatures("Single")
+	require.NoError(t, err, "Expected no error")
+
+	assert.Equal(t, "/httpAuth/app/rest/latest/projects/id:Single/projectFeatures", lastRequest(client).URL.Path)
+	assert.Equal(t, types.ProjectFeatures{
+		types.ProjectFeature{
+			ID:   "PROJECT_EXT_1",
+			Type: "IssueTracker",
+			Properties: types.Properties{
+				"type": "GithubIssues",
+			},
+		},
+	}, features)
+}
+
+func TestClientReplaceProjectFeatureMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id":"PROJECT_EXT_1","type":"IssueTracker","properties":{"property":[{"name":"type","value":"YouTrack"}]}}`), nil)
+
+	feature := &types.ProjectFeature{
+		Type: "IssueTracker",
+		Properties: types.Properties{
+			"type": "YouTrack",
+		},
+	}
+	err := client.ReplaceProjectFeature("Single", "PROJECT_EXT_1", feature)
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "PUT", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/projects/id:Single/projectFeatures/id:PROJECT_EXT_1", req.URL.Path)
+	assert.Equal(t, "PROJECT_EXT_1", feature.ID)
+}
+
+func TestClientGetProjectVersionedSettingsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":1,"projectFeature":[{"id":"PROJECT_EXT_2","type":"versionedSettings","properties":{"property":[{"name":"enabled","value":"true"},{"name":"rootId","value":"Single_Settings"},{"name":"format","value":"xml"},{"name":"buildSettings","value":"ALWAYS_USE_CURRENT"}]}}]}`), nil)
+
+	settings, err := client.GetProjectVersionedSettings("Single")
+	require.NoError(t, err, "Expected no error")
+	require.NotNil(t, settings, "Expected versioned settings")
+
+	assert.True(t, settings.Enabled)
+	assert.Equal(t, "Single_Settings", settings.VcsRootID)
+	assert.Equal(t, types.VersionedSettingsXML, settings.Format)
+	assert.Equal(t, types.AlwaysUseCurrent, settings.SyncMode)
+}
+
+func TestClientSetProjectVersionedSettingsKeepsProperties(t *testing.T) {
+	client, replayer := newVersionTestClient(nil,
+		interaction("GET", "/projects/id:Single/projectFeatures", "", 200, `{"count":1,"projectFeature":[{"id":"PROJECT_EXT_2","type":"versionedSettings","properties":{"property":[{"name":"enabled","value":"true"},{"name":"rootId","value":"Single_Settings"},{"name":"allowUIEditing","value":"true"},{"name":"twoWaySynchronization","value":"false"}]}}]}`),
+		interaction("PUT", "/projects/id:Single/projectFeatures/id:PROJECT_EXT_2", `{"id":"PROJECT_EXT_2","type":"versionedSettings","properties":{"property":[{"name":"allowUIEditing","value":"true"},{"name":"enabled","value":"false"},{"name":"rootId","value":"Single_Settings"},{"name":"showChanges","value":"false"},{"name":"twoWaySynchronization","value":"false"},{"name":"useRelativeIds","value":"false"}]}}`,
+			200, `{"id":"PROJECT_EXT_2","type":"versionedSettings"}`),
+	)
+
+	err := client.SetProjectVersionedSettings("Single", &types.VersionedSettings{VcsRootID: "Single_Settings"})
+	require.NoError(t, err)
+	assert.Empty(t, replayer.Unused())
+}
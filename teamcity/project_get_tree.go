@@ -0,0 +1,39 @@
+package teamcity
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetProjectTree loads a project and all of its subprojects recursively and
+// links them into a tree. Each level carries its build configurations and
+// templates as returned by GetProject.
+func (c *Client) GetProjectTree(rootID string) (*types.ProjectTree, error) {
+	project, err := c.GetProject(rootID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project %s not found", rootID)
+	}
+
+	tree := types.NewProjectTree(project, nil)
+	childIDs := make([]string, 0, len(project.Projects))
+	for id := range project.Projects {
+		childIDs = append(childIDs, id)
+	}
+	sort.Strings(childIDs)
+
+	for _, id := range childIDs {
+		child, err := c.GetProjectTree(id)
+		if err != nil {
+			return nil, err
+		}
+		project.Projects[id] = *child.Project
+		tree.AddChild(child)
+	}
+
+	return tree, nil
+}
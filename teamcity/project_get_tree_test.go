@@ -0,0 +1,39 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetProjectTree(t *testing.T) {
+	client, err := NewRealTestClient(t)
+	require.NoError(t, err, "Expected no error")
+
+	tree, err := client.GetProjectTree("_Root")
+	require.NoError(t, err, "Expected no error")
+	require.NotNil(t, tree, "Expected tree")
+
+	single := tree.Find("Single")
+	require.NotNil(t, single, "Expected to find Single project")
+	assert.Equal(t, "_Root", single.Parent.Project.ID)
+	assert.Contains(t, single.Project.BuildConfigurations, "Single_Normal")
+
+	count := 0
+	err = tree.Walk(func(node *types.ProjectTree, depth int) error {
+		count++
+		return nil
+	}, nil)
+	require.NoError(t, err, "Expected no error")
+	assert.True(t, count > 2, "Expected to visit more than two projects")
+}
+
+func TestClientGetProjectTreeMissing(t *testing.T) {
+	client, err := NewRealTestClient(t)
+	require.NoError(t, err, "Expected no error")
+
+	_, err = client.GetProjectTree("Empt")
+	assert.EqualError(t, err, "project Empt not found")
+}
@@ -0,0 +1,25 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// MoveProject changes the parent of a project
+func (c *Client) MoveProject(projectID, parentProjectID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/parentProject", c.version, projectID)
+	var projectReturn *types.Project
+
+	err := c.doRetryRequest("PUT", path, types.ProjectId(parentProjectID), &projectReturn)
+	if err != nil {
+		return err
+	}
+
+	if projectReturn == nil {
+		return errors.New("project not moved")
+	}
+
+	return nil
+}
@@ -28,7 +28,7 @@ func TestClientReplaceAllProjectParameters(t *testing.T) {
 	require.NoError(t, err, "Expected no error")
 
 	parameters := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"aws.hush": types.Parameter{
 			Value: "Bad Job",
 			Spec: &types.ParameterSpec{
@@ -41,7 +41,7 @@ func TestClientReplaceAllProjectParameters(t *testing.T) {
 	require.NotNil(t, parameters, "Update to return parameters")
 
 	expected := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"aws.hush": types.Parameter{
 			Value: "",
 			Spec: &types.ParameterSpec{
@@ -79,7 +79,7 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 			Value: "Parent",
 			Spec: &types.ParameterSpec{
 				Label: "AWW",
-				Type:  types.CheckboxType{"Hello", "Copperhead"},
+				Type:  types.CheckboxType{Checked: "Hello", Unchecked: "Copperhead"},
 			},
 		},
 		"config.inherited2": types.Parameter{
@@ -89,7 +89,7 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 			Value: "Parent",
 			Spec: &types.ParameterSpec{
 				Label: "AWW",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"env.DAMM": types.Parameter{
@@ -112,14 +112,14 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 			Value: "Dink",
 			Spec: &types.ParameterSpec{
 				Label: "Buhhhhh",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"config.inherited": types.Parameter{
 			Value: "Dink",
 			Spec: &types.ParameterSpec{
 				Label: "Buhhhhh",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"config.inherited2": types.Parameter{
@@ -135,7 +135,7 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 	require.NoError(t, err, "Expected no error")
 
 	parameters := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"config.replace": types.Parameter{
 			Value: "Mink",
 			Spec: &types.ParameterSpec{
@@ -149,14 +149,14 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 			Value: "Dink",
 			Spec: &types.ParameterSpec{
 				Label: "Buhhhhh",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"config.inherited2": types.Parameter{
 			Value: "Dink",
 			Spec: &types.ParameterSpec{
 				Label: "Buhhhhh",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"aws.hush": types.Parameter{
@@ -186,7 +186,7 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 	require.NotNil(t, parameters, "Update to return parameters")
 
 	expected := types.Parameters{
-		"env.HELLO": types.Parameter{"Good job", nil},
+		"env.HELLO": types.Parameter{Value: "Good job"},
 		"config.replace": types.Parameter{
 			Value: "Mink",
 			Spec: &types.ParameterSpec{
@@ -200,14 +200,14 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 			Value: "Dink",
 			Spec: &types.ParameterSpec{
 				Label: "AWW",
-				Type:  types.CheckboxType{"Hello", "Copperhead"},
+				Type:  types.CheckboxType{Checked: "Hello", Unchecked: "Copperhead"},
 			},
 		},
 		"config.inherited2": types.Parameter{
 			Value: "Dink",
 			Spec: &types.ParameterSpec{
 				Label: "Buhhhhh",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"aws.hush": types.Parameter{
@@ -220,7 +220,7 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 			Value: "",
 			Spec: &types.ParameterSpec{
 				Label: "AWW",
-				Type:  types.TextType{"any"},
+				Type:  types.TextType{ValidationMode: "any"},
 			},
 		},
 		"env.MUH": types.Parameter{
@@ -232,7 +232,8 @@ func TestClientReplaceProjectParametersInherited(t *testing.T) {
 			},
 		},
 		"env.DAMM": types.Parameter{
-			Value: "Parent",
+			Value:     "Parent",
+			Inherited: true,
 		},
 	}
 	assert.Equal(t, expected, parameters)
@@ -0,0 +1,23 @@
+package teamcity
+
+import (
+	"strconv"
+)
+
+// ArchiveProject archives a project and its subprojects. Archived projects
+// keep their history but are hidden and do not run builds.
+func (c *Client) ArchiveProject(projectID string) error {
+	return c.SetProjectArchived(projectID, true)
+}
+
+func (c *Client) UnarchiveProject(projectID string) error {
+	return c.SetProjectArchived(projectID, false)
+}
+
+func (c *Client) SetProjectArchived(projectID string, archived bool) error {
+	err := c.SetProjectField(projectID, "archived", strconv.FormatBool(archived))
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package teamcity
+
+import (
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetProjectVersionedSettings returns the versioned settings configured on the
+// project itself, or nil if the project has none.
+func (c *Client) GetProjectVersionedSettings(projectID string) (*types.VersionedSettings, error) {
+	feature, err := c.findVersionedSettingsFeature(projectID)
+	if err != nil || feature == nil {
+		return nil, err
+	}
+	return types.ParseVersionedSettings(*feature)
+}
+
+// SetProjectVersionedSettings updates the versioned settings feature of the
+// project, creating it if the project does not have one yet. Properties of an
+// existing feature that VersionedSettings does not model are kept.
+func (c *Client) SetProjectVersionedSettings(projectID string, settings *types.VersionedSettings) error {
+	existing, err := c.findVersionedSettingsFeature(projectID)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		feature := settings.ProjectFeature("")
+		return c.CreateProjectFeature(projectID, &feature)
+	}
+	feature := settings.UpdateProjectFeature(*existing)
+	return c.ReplaceProjectFeature(projectID, existing.ID, &feature)
+}
+
+func (c *Client) findVersionedSettingsFeature(projectID string) (*types.ProjectFeature, error) {
+	features, err := c.GetProjectFeatures(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, feature := range features.OfType(types.VersionedSettingsFeatureType) {
+		if !feature.Inherited {
+			return &feature, nil
+		}
+	}
+	return nil, nil
+}
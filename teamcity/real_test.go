@@ -10,6 +10,7 @@ import (
 )
 
 var host = flag.String("host", "localhost", "hostname to test against")
+var record = flag.Bool("record", false, "record fixtures for replay tests against -host")
 
 func (c *Client) WaitForReady() error {
 	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects", c.version)
@@ -38,27 +39,22 @@ func IsRealTestSkip(t *testing.T) {
 }
 
 func (c *Client) SkipOlder(t *testing.T, major uint, minor uint) {
-	server, err := c.Server()
-	if err != nil || server == nil {
+	version, err := c.ServerVersion()
+	if err != nil {
 		t.Fatal("Unexpected error getting server info", err)
 	}
-	if server.VersionMajor < major || (server.VersionMajor == major && server.VersionMinor < minor) {
-		t.Skipf("Version to old for test %d.%d < %d.%d", server.VersionMajor, server.VersionMinor,
-			major, minor)
+	required := ServerVersion{Major: major, Minor: minor}
+	if !version.AtLeast(required) {
+		t.Skipf("Version to old for test %s < %s", version, required)
 	}
 }
 
 func (c *Client) VersionParameterValue(t *testing.T, parameter string) string {
-	server, err := c.Server()
-	if err != nil || server == nil {
+	value, err := c.PasswordParameterValue(parameter)
+	if err != nil {
 		t.Fatal("Unexpected error getting server info", err)
 	}
-	if server.VersionMajor < 10 {
-		fmt.Printf("Found major version %d.%d", server.VersionMajor, server.VersionMinor)
-		return ""
-	}
-	fmt.Printf("Secure major version %d.%d", server.VersionMajor, server.VersionMinor)
-	return fmt.Sprintf("%%secure:teamcity.password.%s%%", parameter)
+	return value
 }
 
 func NewRealTestClient(t *testing.T) (*Client, error) {
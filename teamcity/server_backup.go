@@ -0,0 +1,53 @@
+package teamcity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// StartBackup starts a server backup and returns the name of the file it is
+// written to
+func (c *Client) StartBackup(options types.BackupOptions) (string, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/server/backup?%s", c.version, options.Query().Encode())
+
+	ret, err := c.doNotJSONRequest("POST", path, "text/plain", "text/plain", nil)
+	if err != nil {
+		return "", err
+	}
+	if ret == nil {
+		return "", errors.New("backup not started")
+	}
+	return string(ret), nil
+}
+
+// GetBackupStatus returns the status of the current or last backup
+func (c *Client) GetBackupStatus() (types.BackupStatus, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/server/backup", c.version)
+
+	ret, err := c.doNotJSONRequest("GET", path, "text/plain", "text/plain", nil)
+	if err != nil {
+		return "", err
+	}
+	return types.BackupStatus(ret), nil
+}
+
+// WaitForBackup polls the backup status every interval until no backup is in
+// progress and returns the final status. When ctx is done first the last
+// status is returned with the context error.
+func (c *Client) WaitForBackup(ctx context.Context, interval time.Duration) (types.BackupStatus, error) {
+	for {
+		status, err := c.GetBackupStatus()
+		if err != nil || !status.InProgress() {
+			return status, err
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return status, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package teamcity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/icelander/teamcity-sdk-go/httpfixture"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientStartBackupMock(t *testing.T) {
+	client := NewTestClient(newResponse(`TeamCity_Backup_20191212_101500.zip`), nil)
+
+	fileName, err := client.StartBackup(types.BackupOptions{
+		FileName:        "TeamCity_Backup",
+		AddTimestamp:    true,
+		IncludeConfigs:  true,
+		IncludeDatabase: true,
+	})
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, "TeamCity_Backup_20191212_101500.zip", fileName)
+
+	req := lastRequest(client)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/server/backup", req.URL.Path)
+	query := req.URL.Query()
+	assert.Equal(t, "TeamCity_Backup", query.Get("fileName"))
+	assert.Equal(t, "true", query.Get("includeConfigs"))
+	assert.Equal(t, "true", query.Get("includeDatabase"))
+	assert.Equal(t, "false", query.Get("includeBuildLogs"))
+}
+
+func TestClientWaitForBackup(t *testing.T) {
+	status := func(value string) httpfixture.Interaction {
+		backup := interaction("GET", "/server/backup", "", 200, value)
+		backup.Response.ContentType = "text/plain"
+		return backup
+	}
+	client, replayer := newVersionTestClient(nil, status("Running"), status("Running"), status("Idle"))
+
+	final, err := client.WaitForBackup(context.Background(), 0)
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, types.BackupIdle, final)
+	assert.Empty(t, replayer.Unused())
+
+	client, _ = newVersionTestClient(nil, status("Running"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	final, err = client.WaitForBackup(ctx, time.Hour)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, types.BackupStatus("Running"), final)
+}
@@ -0,0 +1,39 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetCleanupSettings returns the server-wide clean-up settings
+func (c *Client) GetCleanupSettings() (*types.CleanupSettings, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/server/cleanup", c.version)
+	var settings *types.CleanupSettings
+
+	err := c.doRetryRequest("GET", path, nil, &settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// ReplaceCleanupSettings replaces the server-wide clean-up settings
+func (c *Client) ReplaceCleanupSettings(settings *types.CleanupSettings) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/server/cleanup", c.version)
+	var settingsReturn *types.CleanupSettings
+
+	err := c.doRetryRequest("PUT", path, settings, &settingsReturn)
+	if err != nil {
+		return err
+	}
+
+	if settingsReturn == nil {
+		return errors.New("cleanup settings not updated")
+	}
+	*settings = *settingsReturn
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package teamcity
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetCleanupSettingsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"enabled":true,"maxCleanupDuration":180,"daily":{"hour":3,"minute":0}}`), nil)
+
+	settings, err := client.GetCleanupSettings()
+	require.NoError(t, err, "Expected no error")
+
+	assert.Equal(t, "/httpAuth/app/rest/latest/server/cleanup", lastRequest(client).URL.Path)
+	assert.Equal(t, &types.CleanupSettings{
+		Enabled:            true,
+		MaxCleanupDuration: 180,
+		Daily:              &types.CleanupDaily{Hour: 3},
+	}, settings)
+}
+
+func TestClientReplaceCleanupSettingsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"enabled":true,"maxCleanupDuration":0,"cron":{"minute":"0","hour":"2","day":"?","month":"*","dayWeek":"SUN"}}`), nil)
+
+	settings := &types.CleanupSettings{
+		Enabled: true,
+		Cron:    &types.CleanupCron{Minute: "0", Hour: "2", Day: "?", Month: "*", DayWeek: "SUN"},
+	}
+	err := client.ReplaceCleanupSettings(settings)
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "PUT", req.Method)
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err, "Expected no error")
+	assert.JSONEq(t, `{"enabled":true,"maxCleanupDuration":0,"cron":{"minute":"0","hour":"2","day":"?","month":"*","dayWeek":"SUN"}}`, string(body))
+	assert.Equal(t, "SUN", settings.Cron.DayWeek)
+}
@@ -0,0 +1,21 @@
+package teamcity
+
+import (
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetLicensingData returns the license usage and the license keys of the
+// server
+func (c *Client) GetLicensingData() (*types.LicensingData, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/server/licensingData", c.version)
+	var licensing *types.LicensingData
+
+	err := c.doRetryRequest("GET", path, nil, &licensing)
+	if err != nil {
+		return nil, err
+	}
+
+	return licensing, nil
+}
@@ -0,0 +1,33 @@
+package teamcity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetLicensingDataMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{
+		"licenseUseExceeded":false,"maxAgents":13,"unlimitedAgents":false,"agentsLeft":4,
+		"maxBuildTypes":-1,"unlimitedBuildTypes":true,"buildTypesLeft":-1,
+		"serverLicenseType":"enterprise","serverEffectiveReleaseDate":"20191129T000000+0000",
+		"licenseKeys":{"count":2,"licenseKey":[
+			{"valid":true,"active":true,"expired":false,"obsolete":false,"expirationDate":"20200601T000000+0000","type":"enterprise","servers":1,"unlimitedBuildTypes":true,"key":"AAAA-BBBB"},
+			{"valid":true,"active":true,"expired":false,"obsolete":false,"expirationDate":"20200301T000000+0000","type":"agent","agents":10,"key":"CCCC-DDDD"}
+		]}
+	}`), nil)
+
+	licensing, err := client.GetLicensingData()
+	require.NoError(t, err, "Expected no error")
+	require.NotNil(t, licensing)
+
+	assert.Equal(t, "/httpAuth/app/rest/latest/server/licensingData", lastRequest(client).URL.Path)
+	assert.Equal(t, 13, licensing.MaxAgents)
+	assert.Equal(t, 4, licensing.AgentsLeft)
+	assert.True(t, licensing.UnlimitedBuildTypes)
+	require.Equal(t, 2, len(licensing.LicenseKeys))
+	assert.Equal(t, 10, licensing.LicenseKeys[1].Agents)
+	assert.Equal(t, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), licensing.Expiry().UTC())
+}
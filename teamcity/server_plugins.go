@@ -0,0 +1,23 @@
+package teamcity
+
+import (
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetPlugins returns the plugins installed on the server
+func (c *Client) GetPlugins() ([]types.Plugin, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/server/plugins", c.version)
+	var plugins struct {
+		Count  int
+		Plugin []types.Plugin
+	}
+
+	err := c.doRetryRequest("GET", path, nil, &plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	return plugins.Plugin, nil
+}
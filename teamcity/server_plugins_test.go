@@ -0,0 +1,29 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetPluginsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":2,"plugin":[
+		{"name":"ant","displayName":"Ant","version":"71499","loadPath":"/opt/teamcity/webapps/ROOT/WEB-INF/plugins/ant"},
+		{"name":"slack","displayName":"Slack Notifier","version":"1.0","parameters":{"property":[{"name":"bundled","value":"false"}]}}
+	]}`), nil)
+
+	plugins, err := client.GetPlugins()
+	require.NoError(t, err, "Expected no error")
+
+	assert.Equal(t, "/httpAuth/app/rest/latest/server/plugins", lastRequest(client).URL.Path)
+	require.Equal(t, 2, len(plugins))
+	assert.Equal(t, "Ant", plugins[0].DisplayName)
+	assert.Equal(t, types.Plugin{
+		Name:        "slack",
+		DisplayName: "Slack Notifier",
+		Version:     "1.0",
+		Parameters:  types.Properties{"bundled": "false"},
+	}, plugins[1])
+}
@@ -0,0 +1,112 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// ServerVersion is the major and minor version of a TeamCity server, e.g.
+// 2017.2 or 10.0
+type ServerVersion struct {
+	Major uint
+	Minor uint
+}
+
+func (v ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// AtLeast tells whether v is the same as or newer than other
+func (v ServerVersion) AtLeast(other ServerVersion) bool {
+	return v.Major > other.Major || (v.Major == other.Major && v.Minor >= other.Minor)
+}
+
+// Feature is a part of the REST API only available from some server version
+type Feature string
+
+const (
+	// FeatureSecureParameterReferences means the values of password
+	// parameters are reported as %secure:teamcity.password.NAME% references
+	// instead of empty strings
+	FeatureSecureParameterReferences Feature = "secure parameter references"
+	// FeatureSecureTokens means secrets can be stored as project secure tokens
+	FeatureSecureTokens Feature = "secure tokens"
+	// FeatureMultipleTemplates means a build configuration can be based on
+	// more than one template
+	FeatureMultipleTemplates Feature = "multiple templates"
+)
+
+var featureVersions = map[Feature]ServerVersion{
+	FeatureSecureParameterReferences: {Major: 10, Minor: 0},
+	FeatureSecureTokens:              {Major: 2017, Minor: 1},
+	FeatureMultipleTemplates:         {Major: 2017, Minor: 2},
+}
+
+// UnsupportedError is returned when a call needs a newer server
+type UnsupportedError struct {
+	Feature  Feature
+	Required ServerVersion
+	Server   ServerVersion
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s requires TeamCity %s or newer, server is %s", e.Feature, e.Required, e.Server)
+}
+
+// ServerVersion returns the version of the server. It is requested from the
+// server on first use and remembered afterwards.
+func (c *Client) ServerVersion() (ServerVersion, error) {
+	c.serverVersionMu.Lock()
+	defer c.serverVersionMu.Unlock()
+	if c.serverVersion != nil {
+		return *c.serverVersion, nil
+	}
+
+	server, err := c.Server()
+	if err != nil {
+		return ServerVersion{}, err
+	}
+	if server == nil {
+		return ServerVersion{}, errors.New("server version not found")
+	}
+	c.serverVersion = &ServerVersion{Major: server.VersionMajor, Minor: server.VersionMinor}
+	return *c.serverVersion, nil
+}
+
+// Supports tells whether the server is new enough for feature
+func (c *Client) Supports(feature Feature) (bool, error) {
+	err := c.requireFeature(feature)
+	if _, ok := err.(*UnsupportedError); ok {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// requireFeature returns an UnsupportedError when the server is too old for
+// feature
+func (c *Client) requireFeature(feature Feature) error {
+	required, ok := featureVersions[feature]
+	if !ok {
+		return fmt.Errorf("unknown feature %q", feature)
+	}
+	version, err := c.ServerVersion()
+	if err != nil {
+		return err
+	}
+	if !version.AtLeast(required) {
+		return &UnsupportedError{Feature: feature, Required: required, Server: version}
+	}
+	return nil
+}
+
+// PasswordParameterValue returns the value the server reports for the named
+// password parameter in place of its secret
+func (c *Client) PasswordParameterValue(name string) (string, error) {
+	supported, err := c.Supports(FeatureSecureParameterReferences)
+	if err != nil || !supported {
+		return "", err
+	}
+	return types.SecurePasswordReference(name), nil
+}
@@ -0,0 +1,150 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/httpfixture"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVersionTestClient(version *ServerVersion, interactions ...httpfixture.Interaction) (*Client, *httpfixture.Replayer) {
+	replayer := httpfixture.NewReplayer(&httpfixture.Fixture{Interactions: interactions})
+	client := NewTestClient(nil, nil)
+	client.serverVersion = version
+	client.retries = 1
+	client.HTTPClient.Transport = replayer
+	return client, replayer
+}
+
+func interaction(method, path, requestBody string, status int, responseBody string) httpfixture.Interaction {
+	return httpfixture.Interaction{
+		Request: httpfixture.Request{Method: method, Path: "/httpAuth/app/rest/latest" + path, Body: httpfixture.Body(requestBody)},
+		Response: httpfixture.Response{
+			StatusCode:  status,
+			ContentType: "application/json",
+			Body:        httpfixture.Body(responseBody),
+		},
+	}
+}
+
+func TestClientServerVersionDetectedOnce(t *testing.T) {
+	client, replayer := newVersionTestClient(nil,
+		interaction("GET", "/server", "", 200, `{"version":"2017.1.5 (build 47175)","versionMajor":2017,"versionMinor":1}`))
+
+	supported, err := client.Supports(FeatureSecureTokens)
+	require.NoError(t, err)
+	assert.True(t, supported)
+	supported, err = client.Supports(FeatureMultipleTemplates)
+	require.NoError(t, err)
+	assert.False(t, supported)
+
+	version, err := client.ServerVersion()
+	require.NoError(t, err)
+	assert.Equal(t, ServerVersion{Major: 2017, Minor: 1}, version)
+	assert.Equal(t, "2017.1", version.String())
+	assert.Empty(t, replayer.Unused())
+}
+
+func TestClientServerVersionError(t *testing.T) {
+	client, _ := newVersionTestClient(nil)
+
+	_, err := client.Supports(FeatureSecureTokens)
+	assert.Error(t, err)
+	_, err = client.Supports(Feature("time travel"))
+	assert.EqualError(t, err, `unknown feature "time travel"`)
+}
+
+func TestClientSupports(t *testing.T) {
+	for _, test := range []struct {
+		version  ServerVersion
+		features map[Feature]bool
+	}{
+		{ServerVersion{9, 1}, map[Feature]bool{FeatureSecureParameterReferences: false, FeatureSecureTokens: false, FeatureMultipleTemplates: false}},
+		{ServerVersion{10, 0}, map[Feature]bool{FeatureSecureParameterReferences: true, FeatureSecureTokens: false, FeatureMultipleTemplates: false}},
+		{ServerVersion{2017, 1}, map[Feature]bool{FeatureSecureParameterReferences: true, FeatureSecureTokens: true, FeatureMultipleTemplates: false}},
+		{ServerVersion{2017, 2}, map[Feature]bool{FeatureSecureParameterReferences: true, FeatureSecureTokens: true, FeatureMultipleTemplates: true}},
+	} {
+		version := test.version
+		client, _ := newVersionTestClient(&version)
+		for feature, expected := range test.features {
+			supported, err := client.Supports(feature)
+			require.NoError(t, err)
+			assert.Equal(t, expected, supported, "%s on %s", feature, test.version)
+		}
+	}
+}
+
+func TestClientPasswordParameterValue(t *testing.T) {
+	client, _ := newVersionTestClient(&ServerVersion{9, 1})
+	value, err := client.PasswordParameterValue("env.MUH")
+	require.NoError(t, err)
+	assert.Equal(t, "", value)
+
+	client, _ = newVersionTestClient(&ServerVersion{10, 0})
+	value, err = client.PasswordParameterValue("env.MUH")
+	require.NoError(t, err)
+	assert.Equal(t, "%secure:teamcity.password.env.MUH%", value)
+}
+
+func TestClientCreateSecureTokenUnsupported(t *testing.T) {
+	client, _ := newVersionTestClient(&ServerVersion{10, 0})
+
+	_, err := client.CreateSecureToken("_Root", "hunter2")
+	require.Error(t, err)
+	assert.IsType(t, &UnsupportedError{}, err)
+	assert.Equal(t, "secure tokens requires TeamCity 2017.1 or newer, server is 10.0", err.Error())
+}
+
+func TestClientTemplatesBeforeMultipleTemplates(t *testing.T) {
+	client, replayer := newVersionTestClient(&ServerVersion{10, 0},
+		interaction("GET", "/buildTypes/id:Empty_Build/template", "", 200, `{"id":"Empty_First"}`),
+		interaction("GET", "/buildTypes/id:Empty_Build/template", "", 200, `{"id":"Empty_First"}`),
+		interaction("PUT", "/buildTypes/id:Empty_Build/template", "id:Empty_Second", 200, `{"id":"Empty_Second"}`),
+		interaction("GET", "/buildTypes/id:Empty_Build/template", "", 200, `{"id":"Empty_Second"}`),
+		interaction("GET", "/buildTypes/id:Empty_Build/template", "", 200, `{"id":"Empty_Second"}`),
+		interaction("DELETE", "/buildTypes/id:Empty_Build/template", "", 204, ""),
+	)
+
+	templates, err := client.GetBuildConfigurationTemplates("Empty_Build")
+	require.NoError(t, err)
+	assert.Equal(t, types.TemplateIds{"Empty_First"}, templates)
+
+	err = client.AttachBuildConfigurationTemplate("Empty_Build", "Empty_Second")
+	require.Error(t, err)
+	assert.Equal(t, "multiple templates requires TeamCity 2017.2 or newer, server is 10.0", err.Error())
+
+	templates = types.TemplateIds{"Empty_First", "Empty_Second"}
+	err = client.ReplaceAllBuildConfigurationTemplates("Empty_Build", &templates)
+	assert.IsType(t, &UnsupportedError{}, err)
+
+	templates = types.TemplateIds{"Empty_Second"}
+	require.NoError(t, client.ReplaceAllBuildConfigurationTemplates("Empty_Build", &templates))
+	assert.Equal(t, types.TemplateIds{"Empty_Second"}, templates)
+
+	require.NoError(t, client.DetachBuildConfigurationTemplate("Empty_Build", "Empty_Second"))
+	assert.Empty(t, replayer.Unused())
+}
+
+func TestClientCreateBuildConfigurationBeforeMultipleTemplates(t *testing.T) {
+	client, replayer := newVersionTestClient(&ServerVersion{10, 0},
+		interaction("POST", "/buildTypes", `{"projectId":"Empty","templateFlag":false,"template":{"id":"Empty_First"},"name":"Build"}`,
+			200, `{"id":"Empty_Build","projectId":"Empty","name":"Build","template":{"id":"Empty_First"}}`))
+
+	config := &types.BuildConfiguration{
+		ProjectID: "Empty",
+		Name:      "Build",
+		Templates: types.TemplateIds{"Empty_First"},
+	}
+	require.NoError(t, client.CreateBuildConfiguration(config))
+	assert.Equal(t, types.TemplateId("Empty_First"), config.TemplateID)
+	assert.Empty(t, replayer.Unused())
+
+	config = &types.BuildConfiguration{
+		ProjectID: "Empty",
+		Name:      "Build",
+		Templates: types.TemplateIds{"Empty_First", "Empty_Second"},
+	}
+	assert.IsType(t, &UnsupportedError{}, client.CreateBuildConfiguration(config))
+}
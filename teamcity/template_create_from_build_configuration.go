@@ -0,0 +1,33 @@
+package teamcity
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// CreateTemplateFromBuildConfiguration creates a new template in projectID as a
+// copy of an existing build configuration. The source configuration is left
+// untouched; attach the template to it separately if required.
+func (c *Client) CreateTemplateFromBuildConfiguration(buildConfID, projectID, name, templateID string) (*types.BuildConfiguration, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/projects/id:%s/templates", c.version, projectID)
+	var templateReturn *types.BuildConfiguration
+	description := types.NewBuildTypeDescription{
+		Name:                      name,
+		ID:                        templateID,
+		SourceBuildTypeLocator:    fmt.Sprintf("id:%s", buildConfID),
+		CopyAllAssociatedSettings: true,
+	}
+
+	err := c.doRetryRequest("POST", path, description, &templateReturn)
+	if err != nil {
+		return nil, err
+	}
+
+	if templateReturn == nil {
+		return nil, errors.New("template not created")
+	}
+
+	return templateReturn, nil
+}
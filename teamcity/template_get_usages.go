@@ -0,0 +1,26 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetTemplateUsages returns the build configurations attached to a template
+func (c *Client) GetTemplateUsages(templateID string) ([]types.BuildType, error) {
+	locator := url.QueryEscape(fmt.Sprintf("template:(id:%s)", templateID))
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes?locator=%s", c.version, locator)
+	var buildTypes struct {
+		Count     int64
+		HREF      string
+		BuildType []types.BuildType
+	}
+
+	err := c.doRetryRequest("GET", path, nil, &buildTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTypes.BuildType, nil
+}
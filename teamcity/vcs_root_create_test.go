@@ -86,3 +86,27 @@ func TestClientCreateVcsRootUsedID(t *testing.T) {
 	err = client.CreateVcsRoot(vcs)
 	assert.Error(t, err, "Expected error")
 }
+
+func TestClientCreateTypedVcsRootMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"id":"Empty_Sdk","name":"Sdk","vcsName":"jetbrains.git","project":{"id":"Empty"},"properties":{"property":[{"name":"url","value":"https://github.com/icelander/teamcity-sdk-go"},{"name":"branch","value":"refs/heads/master"}]}}`), nil)
+
+	vcs, err := client.CreateTypedVcsRoot(&types.GitVcsRoot{
+		Name:      "Sdk",
+		ProjectID: "Empty",
+		URL:       "https://github.com/icelander/teamcity-sdk-go",
+		Branch:    "refs/heads/master",
+	})
+	require.NoError(t, err, "Expected no error")
+	assert.Equal(t, "Empty_Sdk", vcs.ID, "Expected create to return ID")
+}
+
+func TestClientCreateTypedVcsRootInvalid(t *testing.T) {
+	client := NewTestClient(newResponse(``), nil)
+
+	_, err := client.CreateTypedVcsRoot(&types.GitVcsRoot{
+		Name:      "Sdk",
+		ProjectID: "Empty",
+	})
+	assert.EqualError(t, err, "invalid jetbrains.git VCS root: url is required; branch is required")
+	assert.Nil(t, lastRequest(client), "Expected no request to be sent")
+}
@@ -0,0 +1,22 @@
+package teamcity
+
+import (
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// CreateTypedVcsRoot validates a typed VCS root model such as
+// types.GitVcsRoot and creates it. Nothing is sent to the server if
+// validation fails.
+func (c *Client) CreateTypedVcsRoot(root types.TypedVcsRoot) (*types.VcsRoot, error) {
+	if err := root.Validate(); err != nil {
+		return nil, err
+	}
+
+	vcs := root.VcsRoot()
+	err := c.CreateVcsRoot(vcs)
+	if err != nil {
+		return nil, err
+	}
+
+	return vcs, nil
+}
@@ -0,0 +1,34 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetVcsRootInstances returns the instances of a VCS root together with the
+// last revision TeamCity has seen for each.
+func (c *Client) GetVcsRootInstances(vcsRootID string) ([]types.VcsRootInstance, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/vcs-root-instances?locator=%s&fields=count,vcs-root-instance(id,name,vcs-root-id,href,lastVersion,lastVersionInternal)",
+		c.version, url.QueryEscape(fmt.Sprintf("vcsRoot:(id:%s)", vcsRootID)))
+	var instances struct {
+		Count           int64
+		VcsRootInstance []types.VcsRootInstance `json:"vcs-root-instance"`
+	}
+
+	err := c.doRetryRequest("GET", path, nil, &instances)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances.VcsRootInstance, nil
+}
+
+// CheckVcsRootInstanceForChanges asks TeamCity to check a VCS root instance for
+// new changes now instead of waiting for the next polling interval.
+func (c *Client) CheckVcsRootInstanceForChanges(instanceID string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/vcs-root-instances/checkingForChangesQueue?locator=%s",
+		c.version, url.QueryEscape(fmt.Sprintf("id:%s", instanceID)))
+	return c.doRetryRequest("POST", path, nil, nil)
+}
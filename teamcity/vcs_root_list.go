@@ -0,0 +1,49 @@
+package teamcity
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// GetVcsRoots returns the VCS roots matching a locator such as
+// "affectedProject:(id:Single)". Properties are not loaded.
+func (c *Client) GetVcsRoots(locator string) ([]types.VcsRoot, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/vcs-roots?fields=count,vcs-root(id,name,vcsName,href,project(id))", c.version)
+	if locator != "" {
+		path += "&locator=" + url.QueryEscape(locator)
+	}
+	var vcsRoots struct {
+		Count   int64
+		VcsRoot []types.VcsRoot `json:"vcs-root"`
+	}
+
+	err := c.doRetryRequest("GET", path, nil, &vcsRoots)
+	if err != nil {
+		return nil, err
+	}
+
+	return vcsRoots.VcsRoot, nil
+}
+
+// GetProjectVcsRoots returns the VCS roots defined directly in a project
+func (c *Client) GetProjectVcsRoots(projectID string) ([]types.VcsRoot, error) {
+	return c.GetVcsRoots(fmt.Sprintf("project:(id:%s)", projectID))
+}
+
+// GetVcsRootUsages returns the build configurations attached to a VCS root
+func (c *Client) GetVcsRootUsages(vcsRootID string) ([]types.BuildType, error) {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/buildTypes?locator=%s", c.version, url.QueryEscape(fmt.Sprintf("vcsRoot:(id:%s)", vcsRootID)))
+	var buildTypes struct {
+		Count     int64
+		BuildType []types.BuildType
+	}
+
+	err := c.doRetryRequest("GET", path, nil, &buildTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTypes.BuildType, nil
+}
@@ -0,0 +1,61 @@
+package teamcity
+
+import (
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGetProjectVcsRootsMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":1,"vcs-root":[{"id":"Single_HttpsGithubComUmweltdkDockerNodeGit","name":"https://github.com/umweltdk/docker-node.git","vcsName":"jetbrains.git","project":{"id":"Single"}}]}`), nil)
+
+	vcsRoots, err := client.GetProjectVcsRoots("Single")
+	require.NoError(t, err, "Expected no error")
+
+	assert.Equal(t, "project:(id:Single)", lastRequest(client).URL.Query().Get("locator"))
+	assert.Equal(t, []types.VcsRoot{{
+		ID:        "Single_HttpsGithubComUmweltdkDockerNodeGit",
+		Name:      "https://github.com/umweltdk/docker-node.git",
+		VcsName:   "jetbrains.git",
+		ProjectID: "Single",
+	}}, vcsRoots)
+}
+
+func TestClientGetVcsRootInstancesMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":1,"vcs-root-instance":[{"id":"3","name":"https://github.com/umweltdk/docker-node.git","vcs-root-id":"Single_HttpsGithubComUmweltdkDockerNodeGit","lastVersion":"2691bc37fefa5216ace02434b8a24d042013bea9"}]}`), nil)
+
+	instances, err := client.GetVcsRootInstances("Single_HttpsGithubComUmweltdkDockerNodeGit")
+	require.NoError(t, err, "Expected no error")
+
+	assert.Equal(t, "vcsRoot:(id:Single_HttpsGithubComUmweltdkDockerNodeGit)", lastRequest(client).URL.Query().Get("locator"))
+	require.Equal(t, 1, len(instances))
+	assert.Equal(t, "3", instances[0].ID)
+	assert.Equal(t, "2691bc37fefa5216ace02434b8a24d042013bea9", instances[0].LastVersion)
+}
+
+func TestClientCheckVcsRootInstanceForChangesMock(t *testing.T) {
+	client := NewTestClient(newResponse(``), nil)
+
+	err := client.CheckVcsRootInstanceForChanges("3")
+	require.NoError(t, err, "Expected no error")
+
+	req := lastRequest(client)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "/httpAuth/app/rest/latest/vcs-root-instances/checkingForChangesQueue", req.URL.Path)
+	assert.Equal(t, "id:3", req.URL.Query().Get("locator"))
+}
+
+func TestClientGetVcsRootUsages(t *testing.T) {
+	client, err := NewRealTestClient(t)
+	require.NoError(t, err, "Expected no error")
+
+	usages, err := client.GetVcsRootUsages("Single_HttpsGithubComUmweltdkDockerNodeGit")
+	require.NoError(t, err, "Expected no error")
+	ids := make([]string, 0)
+	for _, usage := range usages {
+		ids = append(ids, usage.ID)
+	}
+	assert.Contains(t, ids, "Single_Normal")
+}
@@ -0,0 +1,26 @@
+package teamcity
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func (c *Client) SetVcsRootField(VcsRootId, field string, value string) error {
+	path := fmt.Sprintf("/httpAuth/app/rest/%s/vcs-roots/id:%s/%s", c.version, VcsRootId, field)
+
+	body := bytes.NewBuffer([]byte(value))
+	_, err := c.doNotJSONRequest("PUT", path, "text/plain", "text/plain", body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) SetVcsRootName(VcsRootId, name string) error {
+	return c.SetVcsRootField(VcsRootId, "name", name)
+}
+
+// SetVcsRootProject moves a VCS root to another project
+func (c *Client) SetVcsRootProject(VcsRootId, projectID string) error {
+	return c.SetVcsRootField(VcsRootId, "projectId", projectID)
+}
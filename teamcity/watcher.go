@@ -0,0 +1,380 @@
+package teamcity
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// WatchEventType is the kind of change a Watcher reports
+type WatchEventType string
+
+const (
+	BuildQueued       WatchEventType = "BuildQueued"
+	BuildStarted      WatchEventType = "BuildStarted"
+	BuildFinished     WatchEventType = "BuildFinished"
+	BuildFailed       WatchEventType = "BuildFailed"
+	AgentConnected    WatchEventType = "AgentConnected"
+	AgentDisconnected WatchEventType = "AgentDisconnected"
+)
+
+// WatchEvent is a change seen by a Watcher. Build is set for build events and
+// Agent for agent events. A build finishing with any status other than
+// SUCCESS, including a cancelled build, is reported as BuildFailed.
+type WatchEvent struct {
+	Type  WatchEventType
+	Build *types.Build
+	Agent *types.Agent
+}
+
+// WatchSource is what a Watcher polls. Client implements it.
+type WatchSource interface {
+	GetBuildQueue() ([]*types.Build, error)
+	GetRunningBuilds() ([]*types.Build, error)
+	GetFinishedBuildsSince(buildID int64, since time.Time) ([]*types.Build, error)
+	GetBuild(buildID string) (*types.Build, error)
+	GetAgentStats() ([]*types.Agent, error)
+}
+
+// WatchCheckpoint is the state a Watcher resumes from: the newest build seen
+// and the queued and running builds and connected agents it is waiting to
+// change. The zero value means nothing has been seen yet.
+type WatchCheckpoint struct {
+	LastBuildID int64          `json:"lastBuildId"`
+	SinceDate   types.JSONTime `json:"sinceDate,omitempty"`
+	Queued      []int64        `json:"queued"`
+	Running     []int64        `json:"running"`
+	Agents      []WatchedAgent `json:"agents"`
+}
+
+// WatchedAgent is a connected agent in a checkpoint
+type WatchedAgent struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (c WatchCheckpoint) started() bool {
+	return c.LastBuildID > 0 || c.SinceDate != ""
+}
+
+// LoadWatchCheckpoint reads a checkpoint written by a Watcher. A missing
+// file gives the zero checkpoint.
+func LoadWatchCheckpoint(path string) (WatchCheckpoint, error) {
+	var checkpoint WatchCheckpoint
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return checkpoint, err
+	}
+	err = json.Unmarshal(data, &checkpoint)
+	return checkpoint, err
+}
+
+// Save writes the checkpoint to path
+func (c WatchCheckpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Watcher polls the build queue, the builds and the agents and reports what
+// changed between polls.
+//
+// The first poll from the zero checkpoint only records what is queued,
+// running and connected; builds finished after Since are reported, which is
+// nothing when Since is zero. Later polls ask for finished builds newer than
+// the newest build seen, and look up the queued and running builds they were
+// waiting for when those disappear.
+//
+// Checkpoint and Resume may be called while Watch is running.
+type Watcher struct {
+	Source   WatchSource
+	Interval time.Duration
+	// Since is where the first poll from the zero checkpoint starts reporting
+	// finished builds, the current time when zero
+	Since time.Time
+	// CheckpointFile, when set, is written after the events of every poll
+	// have been delivered by Watch
+	CheckpointFile string
+
+	mu         sync.Mutex
+	checkpoint WatchCheckpoint
+}
+
+// NewWatcher returns a Watcher polling source every interval
+func NewWatcher(source WatchSource, interval time.Duration) *Watcher {
+	return &Watcher{
+		Source:   source,
+		Interval: interval,
+	}
+}
+
+// Resume continues from a checkpoint saved earlier
+func (w *Watcher) Resume(checkpoint WatchCheckpoint) {
+	w.setCheckpoint(checkpoint)
+}
+
+// Checkpoint returns the state after the last poll
+func (w *Watcher) Checkpoint() WatchCheckpoint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.checkpoint
+}
+
+func (w *Watcher) setCheckpoint(checkpoint WatchCheckpoint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checkpoint = checkpoint
+}
+
+// Poll polls once and returns the changes since the previous poll
+func (w *Watcher) Poll() ([]WatchEvent, error) {
+	events, checkpoint, err := w.poll()
+	if err != nil {
+		return nil, err
+	}
+	w.setCheckpoint(checkpoint)
+	return events, nil
+}
+
+// Watch polls until ctx is done and sends the changes on the returned event
+// channel. Poll errors are sent on the error channel, which holds one error;
+// later errors are dropped until it is read. Both channels are closed when
+// ctx is done.
+//
+// The checkpoint only moves on once all events of a poll are delivered, so a
+// watcher resumed from CheckpointFile neither misses events nor repeats them,
+// except for the events of a poll interrupted by ctx.
+func (w *Watcher) Watch(ctx context.Context) (<-chan WatchEvent, <-chan error) {
+	events := make(chan WatchEvent)
+	errs := make(chan error, 1)
+	report := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+		for {
+			polled, checkpoint, err := w.poll()
+			if err != nil {
+				report(err)
+			} else {
+				for _, event := range polled {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				w.setCheckpoint(checkpoint)
+				if w.CheckpointFile != "" {
+					if err := checkpoint.Save(w.CheckpointFile); err != nil {
+						report(err)
+					}
+				}
+			}
+
+			select {
+			case <-time.After(w.Interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, errs
+}
+
+func (w *Watcher) poll() ([]WatchEvent, WatchCheckpoint, error) {
+	previous := w.Checkpoint()
+	next := WatchCheckpoint{LastBuildID: previous.LastBuildID, SinceDate: previous.SinceDate}
+	if !previous.started() {
+		since := w.Since
+		if since.IsZero() {
+			since = time.Now()
+		}
+		next.SinceDate = types.JSONTime(since.Format("20060102T150405-0700"))
+	}
+
+	queue, err := w.Source.GetBuildQueue()
+	if err != nil {
+		return nil, previous, err
+	}
+	running, err := w.Source.GetRunningBuilds()
+	if err != nil {
+		return nil, previous, err
+	}
+	finished, err := w.Source.GetFinishedBuildsSince(next.LastBuildID, next.SinceDate.Time())
+	if err != nil {
+		return nil, previous, err
+	}
+	agents, err := w.Source.GetAgentStats()
+	if err != nil {
+		return nil, previous, err
+	}
+
+	var events []WatchEvent
+	emit := func(eventType WatchEventType, build *types.Build) {
+		if previous.started() {
+			events = append(events, WatchEvent{Type: eventType, Build: build})
+		}
+	}
+	seen := func(build *types.Build) {
+		if build.ID > next.LastBuildID {
+			next.LastBuildID = build.ID
+		}
+	}
+
+	wasQueued := idSet(previous.Queued)
+	wasRunning := idSet(previous.Running)
+	present := map[int64]bool{}
+
+	sortBuilds(queue)
+	for _, build := range queue {
+		seen(build)
+		present[build.ID] = true
+		next.Queued = append(next.Queued, build.ID)
+		if !wasQueued[build.ID] {
+			emit(BuildQueued, build)
+		}
+	}
+	sortBuilds(running)
+	for _, build := range running {
+		seen(build)
+		present[build.ID] = true
+		next.Running = append(next.Running, build.ID)
+		if !wasRunning[build.ID] {
+			emit(BuildStarted, build)
+		}
+	}
+
+	// Builds that went through queue and agent between polls are reported
+	// as started as well, builds seen before only as finished. Builds
+	// cancelled before they got an agent have no start date and are not
+	// reported as started.
+	for _, build := range finished {
+		seen(build)
+		if present[build.ID] {
+			continue
+		}
+		present[build.ID] = true
+		if previous.started() && !wasQueued[build.ID] && !wasRunning[build.ID] {
+			events = append(events, WatchEvent{Type: BuildQueued, Build: build})
+		}
+		if previous.started() && !wasRunning[build.ID] && build.StartDate != "" {
+			events = append(events, WatchEvent{Type: BuildStarted, Build: build})
+		}
+		events = append(events, finishedEvent(build))
+	}
+
+	// Builds waited for that are neither queued nor running any more and were
+	// too old to be listed as finished
+	var gone []int64
+	for _, ids := range [][]int64{previous.Queued, previous.Running} {
+		for _, id := range ids {
+			if !present[id] {
+				gone = append(gone, id)
+				present[id] = true
+			}
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool { return gone[i] < gone[j] })
+	for _, id := range gone {
+		build, err := w.Source.GetBuild(strconv.FormatInt(id, 10))
+		if err == ErrBuildNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, previous, err
+		}
+		switch buildState(build) {
+		case types.Queued:
+			next.Queued = append(next.Queued, id)
+		case types.Started:
+			next.Running = append(next.Running, id)
+			if !wasRunning[id] {
+				events = append(events, WatchEvent{Type: BuildStarted, Build: build})
+			}
+		default:
+			if !wasRunning[id] && build.StartDate != "" {
+				events = append(events, WatchEvent{Type: BuildStarted, Build: build})
+			}
+			events = append(events, finishedEvent(build))
+		}
+	}
+
+	wasConnected := map[int]bool{}
+	for _, agent := range previous.Agents {
+		wasConnected[agent.ID] = true
+	}
+	connected := map[int]bool{}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].ID < agents[j].ID })
+	for _, agent := range agents {
+		if !agent.Connected {
+			continue
+		}
+		connected[agent.ID] = true
+		next.Agents = append(next.Agents, WatchedAgent{ID: agent.ID, Name: agent.Name})
+		if previous.started() && !wasConnected[agent.ID] {
+			events = append(events, WatchEvent{Type: AgentConnected, Agent: agent})
+		}
+	}
+	for _, agent := range previous.Agents {
+		if !connected[agent.ID] {
+			events = append(events, WatchEvent{Type: AgentDisconnected, Agent: &types.Agent{ID: agent.ID, Name: agent.Name}})
+		}
+	}
+
+	return events, next, nil
+}
+
+// buildState prefers the state reported by the server over the one computed
+// from the build dates
+func buildState(build *types.Build) types.State {
+	switch build.State {
+	case "queued":
+		return types.Queued
+	case "running":
+		return types.Started
+	case "finished":
+		return types.Finished
+	}
+	return build.ComputedState()
+}
+
+func finishedEvent(build *types.Build) WatchEvent {
+	if build.Status == "SUCCESS" {
+		return WatchEvent{Type: BuildFinished, Build: build}
+	}
+	return WatchEvent{Type: BuildFailed, Build: build}
+}
+
+func sortBuilds(builds []*types.Build) {
+	sort.Slice(builds, func(i, j int) bool { return builds[i].ID < builds[j].ID })
+}
+
+func idSet(ids []int64) map[int64]bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,338 @@
+package teamcity
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/icelander/teamcity-sdk-go/httpfixture"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ WatchSource = &Client{}
+
+var watchStart = time.Date(2019, 12, 12, 10, 0, 0, 0, time.UTC)
+
+type fakeWatchSource struct {
+	now    time.Time
+	builds map[int64]*types.Build
+	agents []*types.Agent
+	since  []string
+}
+
+func newFakeWatchSource() *fakeWatchSource {
+	return &fakeWatchSource{now: watchStart, builds: map[int64]*types.Build{}}
+}
+
+func (f *fakeWatchSource) date() types.JSONTime {
+	f.now = f.now.Add(time.Minute)
+	return types.JSONTime(f.now.Format("20060102T150405-0700"))
+}
+
+func (f *fakeWatchSource) queue(id int64) {
+	f.builds[id] = &types.Build{ID: id, State: "queued", QueuedDate: f.date()}
+}
+
+func (f *fakeWatchSource) start(id int64) {
+	f.builds[id].State = "running"
+	f.builds[id].StartDate = f.date()
+}
+
+func (f *fakeWatchSource) finish(id int64, status string) {
+	f.builds[id].State = "finished"
+	f.builds[id].Status = status
+	f.builds[id].FinishDate = f.date()
+}
+
+func (f *fakeWatchSource) withState(state string) []*types.Build {
+	var builds []*types.Build
+	for _, build := range f.builds {
+		if build.State == state {
+			builds = append(builds, build)
+		}
+	}
+	return builds
+}
+
+func (f *fakeWatchSource) GetBuildQueue() ([]*types.Build, error) {
+	return f.withState("queued"), nil
+}
+
+func (f *fakeWatchSource) GetRunningBuilds() ([]*types.Build, error) {
+	return f.withState("running"), nil
+}
+
+func (f *fakeWatchSource) GetFinishedBuildsSince(buildID int64, since time.Time) ([]*types.Build, error) {
+	if buildID > 0 {
+		f.since = append(f.since, fmt.Sprintf("build %d", buildID))
+	} else {
+		f.since = append(f.since, "date "+since.UTC().Format("15:04"))
+	}
+	var builds []*types.Build
+	for _, build := range f.withState("finished") {
+		if (buildID > 0 && build.ID > buildID) || (buildID == 0 && build.FinishDate.Time().After(since)) {
+			builds = append(builds, build)
+		}
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].ID < builds[j].ID })
+	return builds, nil
+}
+
+func (f *fakeWatchSource) GetBuild(buildID string) (*types.Build, error) {
+	id, _ := strconv.ParseInt(buildID, 10, 64)
+	build, ok := f.builds[id]
+	if !ok {
+		return nil, ErrBuildNotFound
+	}
+	return build, nil
+}
+
+func (f *fakeWatchSource) GetAgentStats() ([]*types.Agent, error) {
+	return f.agents, nil
+}
+
+func eventNames(events []WatchEvent) []string {
+	names := []string{}
+	for _, event := range events {
+		if event.Build != nil {
+			names = append(names, fmt.Sprintf("%s %d", event.Type, event.Build.ID))
+		} else {
+			names = append(names, fmt.Sprintf("%s %s", event.Type, event.Agent.Name))
+		}
+	}
+	return names
+}
+
+func poll(t *testing.T, watcher *Watcher) []string {
+	events, err := watcher.Poll()
+	require.NoError(t, err)
+	return eventNames(events)
+}
+
+func TestWatcherFirstPollRecordsState(t *testing.T) {
+	source := newFakeWatchSource()
+	source.queue(1)
+	source.finish(1, "SUCCESS")
+	source.queue(2)
+	source.start(2)
+	source.queue(3)
+	source.agents = []*types.Agent{{ID: 1, Name: "agent-1", Connected: true}, {ID: 2, Name: "agent-2"}}
+	watcher := NewWatcher(source, time.Minute)
+	watcher.Since = watchStart.Add(time.Hour)
+
+	assert.Equal(t, []string{}, poll(t, watcher))
+	checkpoint := watcher.Checkpoint()
+	assert.Equal(t, int64(3), checkpoint.LastBuildID)
+	assert.Equal(t, []int64{3}, checkpoint.Queued)
+	assert.Equal(t, []int64{2}, checkpoint.Running)
+	assert.Equal(t, []WatchedAgent{{ID: 1, Name: "agent-1"}}, checkpoint.Agents)
+
+	assert.Equal(t, []string{}, poll(t, watcher))
+	assert.Equal(t, []string{"date 11:00", "build 3"}, source.since)
+}
+
+func TestWatcherSince(t *testing.T) {
+	source := newFakeWatchSource()
+	source.queue(1)
+	source.finish(1, "SUCCESS")
+	source.queue(2)
+	source.finish(2, "FAILURE")
+	watcher := NewWatcher(source, time.Minute)
+	watcher.Since = watchStart.Add(2 * time.Minute)
+
+	assert.Equal(t, []string{"BuildFailed 2"}, poll(t, watcher))
+}
+
+func TestWatcherBuilds(t *testing.T) {
+	source := newFakeWatchSource()
+	source.queue(1)
+	source.start(1)
+	watcher := NewWatcher(source, time.Minute)
+	assert.Equal(t, []string{}, poll(t, watcher))
+
+	source.queue(2)
+	source.queue(3)
+	assert.Equal(t, []string{"BuildQueued 2", "BuildQueued 3"}, poll(t, watcher))
+
+	source.start(2)
+	assert.Equal(t, []string{"BuildStarted 2"}, poll(t, watcher))
+
+	// 1 and 2 are older than the newest build seen and are looked up
+	source.finish(2, "SUCCESS")
+	source.finish(1, "FAILURE")
+	assert.Equal(t, []string{"BuildFailed 1", "BuildFinished 2"}, poll(t, watcher))
+
+	// 4 went through queue and agent between polls, 3 was cancelled
+	source.queue(4)
+	source.start(4)
+	source.finish(4, "SUCCESS")
+	source.finish(3, "UNKNOWN")
+	assert.Equal(t, []string{"BuildQueued 4", "BuildStarted 4", "BuildFinished 4", "BuildFailed 3"},
+		poll(t, watcher))
+
+	// removed from the queue and gone
+	source.queue(5)
+	assert.Equal(t, []string{"BuildQueued 5"}, poll(t, watcher))
+	delete(source.builds, 5)
+	assert.Equal(t, []string{}, poll(t, watcher))
+	assert.Equal(t, []string{}, poll(t, watcher))
+
+	// 6 was queued and cancelled, 7 was running, both older than the newest
+	// build and looked up
+	source.queue(6)
+	source.queue(7)
+	source.start(7)
+	source.queue(8)
+	assert.Equal(t, []string{"BuildQueued 6", "BuildQueued 8", "BuildStarted 7"}, poll(t, watcher))
+	source.finish(6, "UNKNOWN")
+	source.finish(7, "FAILURE")
+	delete(source.builds, 8)
+	assert.Equal(t, []string{"BuildFailed 6", "BuildFailed 7"}, poll(t, watcher))
+
+	checkpoint := watcher.Checkpoint()
+	assert.Equal(t, int64(8), checkpoint.LastBuildID)
+	assert.Empty(t, checkpoint.Queued)
+	assert.Empty(t, checkpoint.Running)
+}
+
+func TestWatcherAgents(t *testing.T) {
+	source := newFakeWatchSource()
+	source.agents = []*types.Agent{{ID: 1, Name: "agent-1", Connected: true}}
+	watcher := NewWatcher(source, time.Minute)
+	assert.Equal(t, []string{}, poll(t, watcher))
+
+	source.agents = []*types.Agent{{ID: 1, Name: "agent-1", Connected: true}, {ID: 2, Name: "agent-2", Connected: true}}
+	assert.Equal(t, []string{"AgentConnected agent-2"}, poll(t, watcher))
+
+	source.agents = []*types.Agent{{ID: 2, Name: "agent-2"}}
+	assert.Equal(t, []string{"AgentDisconnected agent-1", "AgentDisconnected agent-2"}, poll(t, watcher))
+
+	source.agents = []*types.Agent{{ID: 2, Name: "agent-2", Connected: true}}
+	assert.Equal(t, []string{"AgentConnected agent-2"}, poll(t, watcher))
+}
+
+func TestWatcherResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watcher")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	checkpoint, err := LoadWatchCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, WatchCheckpoint{}, checkpoint)
+
+	source := newFakeWatchSource()
+	source.queue(1)
+	watcher := NewWatcher(source, time.Minute)
+	assert.Equal(t, []string{}, poll(t, watcher))
+	source.queue(2)
+	assert.Equal(t, []string{"BuildQueued 2"}, poll(t, watcher))
+	require.NoError(t, watcher.Checkpoint().Save(path))
+
+	// changes while the watcher is down are reported once on restart
+	source.start(1)
+	source.finish(1, "SUCCESS")
+	source.queue(3)
+
+	checkpoint, err = LoadWatchCheckpoint(path)
+	require.NoError(t, err)
+	assert.Equal(t, watcher.Checkpoint(), checkpoint)
+	restarted := NewWatcher(source, time.Minute)
+	restarted.Resume(checkpoint)
+	assert.Equal(t, []string{"BuildQueued 3", "BuildStarted 1", "BuildFinished 1"}, poll(t, restarted))
+	assert.Equal(t, []string{}, poll(t, restarted))
+}
+
+func TestWatcherWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watcher")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	source := newFakeWatchSource()
+	source.queue(1)
+	watcher := NewWatcher(source, time.Millisecond)
+	watcher.CheckpointFile = filepath.Join(dir, "checkpoint.json")
+	watcher.Resume(WatchCheckpoint{LastBuildID: 0, SinceDate: types.JSONTime(watchStart.Format("20060102T150405-0700"))})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := watcher.Watch(ctx)
+	event := <-events
+	assert.Equal(t, BuildQueued, event.Type)
+	assert.Equal(t, int64(1), event.Build.ID)
+	cancel()
+
+	for range events {
+	}
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	checkpoint, err := LoadWatchCheckpoint(watcher.CheckpointFile)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, checkpoint.Queued)
+}
+
+func TestWatcherCheckpointWhileWatching(t *testing.T) {
+	source := newFakeWatchSource()
+	source.queue(1)
+	watcher := NewWatcher(source, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := watcher.Watch(ctx)
+	for i := 0; i < 10; i++ {
+		watcher.Checkpoint()
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	for range events {
+	}
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []int64{1}, watcher.Checkpoint().Queued)
+}
+
+func TestClientGetFinishedBuildsSinceMock(t *testing.T) {
+	client := NewTestClient(newResponse(`{"count":2,"build":[{"id":12,"state":"finished"},{"id":11,"state":"finished"}]}`), nil)
+
+	builds, err := client.GetFinishedBuildsSince(10, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "state:finished,defaultFilter:false,sinceBuild:(id:10),count:100", lastRequest(client).URL.Query().Get("locator"))
+	require.Equal(t, 2, len(builds))
+	assert.Equal(t, int64(11), builds[0].ID)
+
+	client = NewTestClient(newResponse(`{"count":0}`), nil)
+	_, err = client.GetFinishedBuildsSince(0, watchStart)
+	require.NoError(t, err)
+	assert.Equal(t, "state:finished,defaultFilter:false,sinceDate:20191212T100000+0000,count:100", lastRequest(client).URL.Query().Get("locator"))
+}
+
+func TestClientGetFinishedBuildsSincePages(t *testing.T) {
+	locator := "state:finished,defaultFilter:false,sinceBuild:(id:10),count:100"
+	firstPage := interaction("GET", "/builds?locator="+url.QueryEscape(locator)+"&fields="+buildListFields, "", 200,
+		`{"count":2,"nextHref":"/app/rest/builds?locator=state:finished,defaultFilter:false,sinceBuild:(id:10),count:100,start:100","build":[{"id":14},{"id":13}]}`)
+	secondPage := httpfixture.Interaction{
+		Request: httpfixture.Request{Method: "GET", Path: "/httpAuth/app/rest/builds?locator=state:finished,defaultFilter:false,sinceBuild:(id:10),count:100,start:100"},
+		Response: httpfixture.Response{StatusCode: 200, ContentType: "application/json",
+			Body: httpfixture.Body(`{"count":2,"build":[{"id":12},{"id":11}]}`)},
+	}
+	client, replayer := newVersionTestClient(nil, firstPage, secondPage)
+
+	builds, err := client.GetFinishedBuildsSince(10, time.Time{})
+	require.NoError(t, err)
+	ids := []int64{}
+	for _, build := range builds {
+		ids = append(ids, build.ID)
+	}
+	assert.Equal(t, []int64{11, 12, 13, 14}, ids)
+	assert.Empty(t, replayer.Unused())
+}
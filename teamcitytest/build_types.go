@@ -0,0 +1,772 @@
+package teamcitytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// buildType is a stored build configuration or template. Steps, triggers,
+// features, dependencies and agent requirements are kept as items per
+// collection, only the other settings live in config.
+type buildType struct {
+	config   types.BuildConfiguration
+	paused   bool
+	items    map[string][]item
+	counters map[string]int
+}
+
+func (bt *buildType) kind() string {
+	if bt.config.TemplateFlag {
+		return "Template"
+	}
+	return "Build configuration"
+}
+
+func (bt *buildType) clone() *buildType {
+	clone := &buildType{
+		config:   bt.config,
+		paused:   bt.paused,
+		items:    make(map[string][]item),
+		counters: make(map[string]int),
+	}
+	clone.config.Parameters = copyParameters(bt.config.Parameters)
+	clone.config.Settings = append(types.BuildSettings(nil), bt.config.Settings...)
+	clone.config.VcsRootEntries = append(types.VcsRootEntries(nil), bt.config.VcsRootEntries...)
+	clone.config.Templates = append(types.TemplateIds(nil), bt.config.Templates...)
+	for path, items := range bt.items {
+		for _, it := range items {
+			clone.items[path] = append(clone.items[path], it.clone())
+		}
+	}
+	for path, counter := range bt.counters {
+		clone.counters[path] = counter
+	}
+	return clone
+}
+
+// remap updates references to copied build configurations and templates
+func (bt *buildType) remap(mapping map[string]string) {
+	for i, id := range bt.config.Templates {
+		if newID, ok := mapping[string(id)]; ok {
+			bt.config.Templates[i] = types.TemplateId(newID)
+		}
+	}
+	for _, c := range collections {
+		for i, it := range bt.items[c.path] {
+			if it.SourceBuildType == nil {
+				continue
+			}
+			if newID, ok := mapping[it.SourceBuildType.ID]; ok {
+				if it.ID == it.SourceBuildType.ID {
+					it.ID = newID
+				}
+				source := *it.SourceBuildType
+				source.ID = newID
+				it.SourceBuildType = &source
+				bt.items[c.path][i] = it
+			}
+		}
+	}
+}
+
+type buildTypeShort struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ProjectName  string `json:"projectName"`
+	ProjectID    string `json:"projectId"`
+	TemplateFlag bool   `json:"templateFlag,omitempty"`
+	Href         string `json:"href"`
+	WebURL       string `json:"webUrl"`
+}
+
+func (s *Server) buildTypeShort(bt *buildType) buildTypeShort {
+	projectName := ""
+	if project, ok := s.projects[bt.config.ProjectID]; ok {
+		projectName = project.Name
+	}
+	return buildTypeShort{
+		ID:           bt.config.ID,
+		Name:         bt.config.Name,
+		ProjectName:  projectName,
+		ProjectID:    bt.config.ProjectID,
+		TemplateFlag: bt.config.TemplateFlag,
+		Href:         "/app/rest/buildTypes/id:" + bt.config.ID,
+		WebURL:       s.URL + "/viewType.html?buildTypeId=" + bt.config.ID,
+	}
+}
+
+func (s *Server) buildTypeIDTaken(id string) bool {
+	_, ok := s.buildTypes[id]
+	return ok
+}
+
+func (s *Server) findBuildType(locator string) (*buildType, error) {
+	id := locatorID(locator)
+	bt, ok := s.buildTypes[id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "No build type nor template is found by id '%s'.", id)
+	}
+	return bt, nil
+}
+
+func (s *Server) findTemplate(id string) (*buildType, error) {
+	template, err := s.findBuildType("id:" + id)
+	if err != nil {
+		return nil, err
+	}
+	if !template.config.TemplateFlag {
+		return nil, badRequest("Build configuration '%s' is not a template.", id)
+	}
+	return template, nil
+}
+
+// projectBuildTypes returns the build configurations and templates of a
+// project, templates first
+func (s *Server) projectBuildTypes(projectID string) []*buildType {
+	bts := make([]*buildType, 0)
+	for _, bt := range s.buildTypes {
+		if bt.config.ProjectID == projectID {
+			bts = append(bts, bt)
+		}
+	}
+	sort.Slice(bts, func(i, j int) bool {
+		if bts[i].config.TemplateFlag != bts[j].config.TemplateFlag {
+			return bts[i].config.TemplateFlag
+		}
+		return bts[i].config.ID < bts[j].config.ID
+	})
+	return bts
+}
+
+func (s *Server) templates(bt *buildType) []*buildType {
+	templates := make([]*buildType, 0, len(bt.config.Templates))
+	for _, id := range bt.config.Templates {
+		if template, ok := s.buildTypes[string(id)]; ok {
+			templates = append(templates, template)
+		}
+	}
+	return templates
+}
+
+// templateParameters returns the parameters the build configuration gets
+// from its templates. The first template wins when several define one.
+func (s *Server) templateParameters(bt *buildType) types.Parameters {
+	inherited := make(types.Parameters)
+	templates := s.templates(bt)
+	for i := len(templates) - 1; i >= 0; i-- {
+		for name, parameter := range templates[i].config.Parameters {
+			inherited[name] = parameter
+		}
+	}
+	return inherited
+}
+
+func (s *Server) buildTypeJSON(bt *buildType) (interface{}, error) {
+	short := s.buildTypeShort(bt)
+	fields := map[string]interface{}{
+		"parameters":  parameterList(mergeParameters(s.templateParameters(bt), bt.config.Parameters)),
+		"projectName": short.ProjectName,
+		"href":        short.Href,
+		"webUrl":      short.WebURL,
+		"paused":      bt.paused,
+	}
+	for _, c := range collections {
+		fields[c.path] = c.list(s.effectiveItems(bt, c))
+	}
+	return withFields(bt.config, fields)
+}
+
+func (s *Server) routeBuildTypes(r *request, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listBuildTypes(r.locator())
+		case http.MethodPost:
+			var config types.BuildConfiguration
+			if err := r.decode(&config); err != nil {
+				return nil, err
+			}
+			bt, err := s.createBuildType(config)
+			if err != nil {
+				return nil, err
+			}
+			return s.buildTypeJSON(bt)
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	bt, err := s.findBuildType(path[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.buildTypeJSON(bt)
+		case http.MethodDelete:
+			return nil, s.deleteBuildType(bt)
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	if c, ok := findCollection(path[1]); ok {
+		return s.routeItems(r, bt, c, path[2:])
+	}
+	switch path[1] {
+	case "parameters":
+		return routeParameters(r, path[2:], &bt.config.Parameters, s.templateParameters(bt))
+	case "settings":
+		return routeSettings(r, bt, path[2:])
+	case "vcs-root-entries":
+		return s.routeVcsRootEntries(r, bt, path[2:])
+	case "templates":
+		return s.routeTemplates(r, bt, path[2:])
+	case "template":
+		if len(path) == 2 {
+			return s.routeTemplate(r, bt)
+		}
+	case "project":
+		if len(path) == 2 {
+			return s.routeBuildTypeProject(r, bt)
+		}
+	case "builds":
+		if len(path) == 2 && r.Method == http.MethodGet {
+			locator := r.locator()
+			locator["buildType"] = "id:" + bt.config.ID
+			return s.listBuilds(locator), nil
+		}
+	case "id", "name", "description", "paused":
+		if len(path) == 2 {
+			return s.routeBuildTypeField(r, bt, path[1])
+		}
+	}
+	return nil, notFound(r)
+}
+
+func (s *Server) routeProjectBuildTypes(r *request, project *types.Project, template bool) (interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		list := make([]buildTypeShort, 0)
+		for _, bt := range s.projectBuildTypes(project.ID) {
+			if bt.config.TemplateFlag == template {
+				list = append(list, s.buildTypeShort(bt))
+			}
+		}
+		return buildTypeList(list), nil
+	case http.MethodPost:
+		var description types.NewBuildTypeDescription
+		if err := r.decode(&description); err != nil {
+			return nil, err
+		}
+		if description.SourceBuildTypeLocator != "" {
+			bt, err := s.copyBuildType(description, project.ID, template)
+			if err != nil {
+				return nil, err
+			}
+			return s.buildTypeJSON(bt)
+		}
+		var config types.BuildConfiguration
+		if err := r.decode(&config); err != nil {
+			return nil, err
+		}
+		config.ProjectID = project.ID
+		config.TemplateFlag = template
+		bt, err := s.createBuildType(config)
+		if err != nil {
+			return nil, err
+		}
+		return s.buildTypeJSON(bt)
+	}
+	return nil, methodNotAllowed(r)
+}
+
+func buildTypeList(list []buildTypeShort) interface{} {
+	return struct {
+		Count     int              `json:"count"`
+		BuildType []buildTypeShort `json:"buildType"`
+	}{len(list), list}
+}
+
+// listBuildTypes supports the project, affectedProject, template, vcsRoot,
+// snapshotDependency and templateFlag locator dimensions
+func (s *Server) listBuildTypes(locator map[string]string) (interface{}, error) {
+	matches := func(bt *buildType) bool { return !bt.config.TemplateFlag }
+	filters := []func(*buildType) bool{}
+
+	if value, ok := locator["templateFlag"]; ok {
+		flag := value == "true"
+		matches = func(bt *buildType) bool { return bt.config.TemplateFlag == flag }
+	}
+	if value, ok := locator["project"]; ok {
+		id := locatorID(value)
+		filters = append(filters, func(bt *buildType) bool { return bt.config.ProjectID == id })
+	}
+	if value, ok := locator["affectedProject"]; ok {
+		projects := make(map[string]bool)
+		for _, id := range s.descendants(locatorID(value)) {
+			projects[id] = true
+		}
+		filters = append(filters, func(bt *buildType) bool { return projects[bt.config.ProjectID] })
+	}
+	if value, ok := locator["template"]; ok {
+		id := locatorID(value)
+		filters = append(filters, func(bt *buildType) bool {
+			for _, template := range bt.config.Templates {
+				if string(template) == id {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if value, ok := locator["vcsRoot"]; ok {
+		id := locatorID(value)
+		filters = append(filters, func(bt *buildType) bool {
+			for _, entry := range bt.config.VcsRootEntries {
+				if string(entry.VcsRootID) == id {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if value, ok := locator["snapshotDependency"]; ok {
+		dependency := parseLocator(value)
+		recursive := dependency["recursive"] != "false"
+		related := make(map[string]bool)
+		// to:(X) lists what X depends on, from:(X) what depends on X
+		if to, ok := dependency["to"]; ok {
+			s.collectDependencies(locatorID(to), recursive, related)
+		} else if from, ok := dependency["from"]; ok {
+			s.collectDependents(locatorID(from), recursive, related)
+		}
+		filters = append(filters, func(bt *buildType) bool { return related[bt.config.ID] })
+	}
+
+	ids := make([]string, 0)
+	for id := range s.buildTypes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	list := make([]buildTypeShort, 0)
+	for _, id := range ids {
+		bt := s.buildTypes[id]
+		ok := matches(bt)
+		for _, filter := range filters {
+			ok = ok && filter(bt)
+		}
+		if ok {
+			list = append(list, s.buildTypeShort(bt))
+		}
+	}
+	return buildTypeList(list), nil
+}
+
+func (s *Server) snapshotSources(bt *buildType) []string {
+	sources := make([]string, 0)
+	for _, it := range s.effectiveItems(bt, snapshotDependencies) {
+		sources = append(sources, it.SourceBuildType.ID)
+	}
+	return sources
+}
+
+// collectDependents adds the build configurations with a snapshot dependency
+// on id
+func (s *Server) collectDependents(id string, recursive bool, found map[string]bool) {
+	for _, bt := range s.buildTypes {
+		for _, source := range s.snapshotSources(bt) {
+			if source == id && !found[bt.config.ID] {
+				found[bt.config.ID] = true
+				if recursive {
+					s.collectDependents(bt.config.ID, recursive, found)
+				}
+			}
+		}
+	}
+}
+
+// collectDependencies adds the build configurations id has a snapshot
+// dependency on
+func (s *Server) collectDependencies(id string, recursive bool, found map[string]bool) {
+	bt, ok := s.buildTypes[id]
+	if !ok {
+		return
+	}
+	for _, source := range s.snapshotSources(bt) {
+		if !found[source] {
+			found[source] = true
+			if recursive {
+				s.collectDependencies(source, recursive, found)
+			}
+		}
+	}
+}
+
+func (s *Server) checkBuildTypeName(bt *buildType, name string) error {
+	if name == "" {
+		return badRequest("%s name cannot be empty.", bt.kind())
+	}
+	for _, other := range s.projectBuildTypes(bt.config.ProjectID) {
+		if other != bt && other.config.TemplateFlag == bt.config.TemplateFlag && other.config.Name == name {
+			return badRequest("%s with name \"%s\" already exists in project \"%s\".", bt.kind(), name, bt.config.ProjectID)
+		}
+	}
+	return nil
+}
+
+// assignBuildTypeID validates the requested ID of a new build configuration
+// or generates one
+func (s *Server) assignBuildTypeID(bt *buildType) error {
+	if bt.config.ID == "" {
+		bt.config.ID = generateID(bt.config.ProjectID, bt.config.Name, s.buildTypeIDTaken)
+		return nil
+	}
+	if err := checkID(bt.kind(), bt.config.ID); err != nil {
+		return err
+	}
+	if s.buildTypeIDTaken(bt.config.ID) {
+		return badRequest("The build configuration / template ID \"%s\" is already used by another configuration or template.", bt.config.ID)
+	}
+	return nil
+}
+
+func (s *Server) checkTemplates(bt *buildType, templates types.TemplateIds) error {
+	if len(templates) > 0 && bt.config.TemplateFlag {
+		return badRequest("Templates cannot be attached to template '%s'.", bt.config.ID)
+	}
+	for _, id := range templates {
+		if _, err := s.findTemplate(string(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) createBuildType(config types.BuildConfiguration) (*buildType, error) {
+	project, err := s.findProject("id:" + config.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := config.Templates
+	if len(templates) == 0 && config.TemplateID != "" {
+		templates = types.TemplateIds{config.TemplateID}
+	}
+	bt := &buildType{
+		config: types.BuildConfiguration{
+			ID:           config.ID,
+			ProjectID:    project.ID,
+			TemplateFlag: config.TemplateFlag,
+			Templates:    templates,
+			Name:         config.Name,
+			Description:  config.Description,
+			Settings:     config.Settings,
+			Parameters:   config.Parameters.Own(),
+		},
+		items:    make(map[string][]item),
+		counters: make(map[string]int),
+	}
+	if err := s.checkBuildTypeName(bt, bt.config.Name); err != nil {
+		return nil, err
+	}
+	if err := s.assignBuildTypeID(bt); err != nil {
+		return nil, err
+	}
+	if err := s.checkTemplates(bt, templates); err != nil {
+		return nil, err
+	}
+	for _, entry := range config.VcsRootEntries {
+		if _, err := s.attachVcsRoot(bt, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for _, c := range collections {
+		items, err := c.decodeList(fields[c.path])
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			if it.Inherited {
+				continue
+			}
+			if _, err := s.addItem(bt, c, it); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.buildTypes[bt.config.ID] = bt
+	return bt, nil
+}
+
+func (s *Server) copyBuildType(description types.NewBuildTypeDescription, projectID string, template bool) (*buildType, error) {
+	source, err := s.findBuildType(description.SourceBuildTypeLocator)
+	if err != nil {
+		return nil, err
+	}
+	bt := source.clone()
+	bt.config.ID = description.ID
+	bt.config.Name = description.Name
+	bt.config.ProjectID = projectID
+	bt.config.TemplateFlag = template
+	if template {
+		bt.config.Templates = nil
+	}
+	if err := s.checkBuildTypeName(bt, bt.config.Name); err != nil {
+		return nil, err
+	}
+	if err := s.assignBuildTypeID(bt); err != nil {
+		return nil, err
+	}
+	s.buildTypes[bt.config.ID] = bt
+	return bt, nil
+}
+
+func (s *Server) deleteBuildType(bt *buildType) error {
+	if bt.config.TemplateFlag {
+		usages := make([]string, 0)
+		for _, other := range s.buildTypes {
+			for _, id := range other.config.Templates {
+				if string(id) == bt.config.ID {
+					usages = append(usages, other.config.ID)
+				}
+			}
+		}
+		if len(usages) > 0 {
+			sort.Strings(usages)
+			return badRequest("Template '%s' cannot be deleted because it is used by build configurations: %v.", bt.config.ID, usages)
+		}
+	}
+	delete(s.buildTypes, bt.config.ID)
+	for _, other := range s.buildTypes {
+		for _, c := range []collection{snapshotDependencies, artifactDependencies} {
+			kept := make([]item, 0)
+			for _, it := range other.items[c.path] {
+				if it.SourceBuildType.ID != bt.config.ID {
+					kept = append(kept, it)
+				}
+			}
+			other.items[c.path] = kept
+		}
+	}
+	return nil
+}
+
+func (s *Server) routeTemplates(r *request, bt *buildType, path []string) (interface{}, error) {
+	list := func() interface{} {
+		list := make([]buildTypeShort, 0)
+		for _, template := range s.templates(bt) {
+			list = append(list, s.buildTypeShort(template))
+		}
+		return buildTypeList(list)
+	}
+
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return list(), nil
+		case http.MethodPost:
+			var short types.BuildConfigurationShort
+			if err := r.decode(&short); err != nil {
+				return nil, err
+			}
+			id := types.TemplateId(short.ID)
+			if err := s.checkTemplates(bt, types.TemplateIds{id}); err != nil {
+				return nil, err
+			}
+			for _, attached := range bt.config.Templates {
+				if attached == id {
+					return s.buildTypeShort(s.buildTypes[short.ID]), nil
+				}
+			}
+			bt.config.Templates = append(bt.config.Templates, id)
+			return s.buildTypeShort(s.buildTypes[short.ID]), nil
+		case http.MethodPut:
+			var templates types.TemplateIds
+			if err := r.decode(&templates); err != nil {
+				return nil, err
+			}
+			if err := s.checkTemplates(bt, templates); err != nil {
+				return nil, err
+			}
+			bt.config.Templates = templates
+			return list(), nil
+		case http.MethodDelete:
+			bt.config.Templates = nil
+			return nil, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	if len(path) == 1 && r.Method == http.MethodDelete {
+		id := types.TemplateId(locatorID(path[0]))
+		for i, attached := range bt.config.Templates {
+			if attached == id {
+				bt.config.Templates = append(bt.config.Templates[:i:i], bt.config.Templates[i+1:]...)
+				return nil, nil
+			}
+		}
+		return nil, errorf(http.StatusNotFound, "Template '%s' is not attached to build configuration '%s'.", id, bt.config.ID)
+	}
+	return nil, notFound(r)
+}
+
+// routeTemplate serves the single template attachment of servers before
+// 2017.2, which replaces all attached templates
+func (s *Server) routeTemplate(r *request, bt *buildType) (interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		templates := s.templates(bt)
+		if len(templates) == 0 {
+			return nil, errorf(http.StatusNotFound, "Build configuration '%s' is not attached to a template.", bt.config.ID)
+		}
+		return s.buildTypeShort(templates[0]), nil
+	case http.MethodPut:
+		id := types.TemplateId(locatorID(string(r.body)))
+		if err := s.checkTemplates(bt, types.TemplateIds{id}); err != nil {
+			return nil, err
+		}
+		bt.config.Templates = types.TemplateIds{id}
+		return s.buildTypeShort(s.buildTypes[string(id)]), nil
+	case http.MethodDelete:
+		bt.config.Templates = nil
+		return nil, nil
+	}
+	return nil, methodNotAllowed(r)
+}
+
+func (s *Server) routeBuildTypeProject(r *request, bt *buildType) (interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		return s.projectShort(s.projects[bt.config.ProjectID]), nil
+	case http.MethodPut:
+		var reference projectReference
+		if err := r.decode(&reference); err != nil {
+			return nil, err
+		}
+		project, err := s.findProject("id:" + reference.id())
+		if err != nil {
+			return nil, err
+		}
+		moved := *bt
+		moved.config.ProjectID = project.ID
+		if err := s.checkBuildTypeName(&moved, bt.config.Name); err != nil {
+			return nil, err
+		}
+		bt.config.ProjectID = project.ID
+		return s.projectShort(project), nil
+	}
+	return nil, methodNotAllowed(r)
+}
+
+func (s *Server) routeBuildTypeField(r *request, bt *buildType, field string) (interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		switch field {
+		case "id":
+			return text(bt.config.ID), nil
+		case "name":
+			return text(bt.config.Name), nil
+		case "description":
+			return text(bt.config.Description), nil
+		}
+		return text(strconv.FormatBool(bt.paused)), nil
+	case http.MethodPut:
+		value := string(r.body)
+		switch field {
+		case "id":
+			return nil, badRequest("Changing the build configuration ID is not supported.")
+		case "name":
+			if err := s.checkBuildTypeName(bt, value); err != nil {
+				return nil, err
+			}
+			bt.config.Name = value
+		case "description":
+			bt.config.Description = value
+		case "paused":
+			paused, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, badRequest("Invalid boolean value \"%s\".", value)
+			}
+			bt.paused = paused
+		}
+		return text(value), nil
+	}
+	return nil, methodNotAllowed(r)
+}
+
+func routeSettings(r *request, bt *buildType, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return bt.config.Settings, nil
+		case http.MethodPut:
+			var settings types.BuildSettings
+			if err := r.decode(&settings); err != nil {
+				return nil, err
+			}
+			bt.config.Settings = settings
+			return settings, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+	if len(path) != 1 {
+		return nil, notFound(r)
+	}
+
+	name := path[0]
+	index := -1
+	for i, setting := range bt.config.Settings {
+		if setting.Name == name {
+			index = i
+		}
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if index < 0 {
+			return nil, errorf(http.StatusNotFound, "No setting with name '%s' is found.", name)
+		}
+		return text(bt.config.Settings[index].Value), nil
+	case http.MethodPut:
+		setting := types.BuildSetting{Name: name, Value: string(r.body)}
+		if !r.isText() {
+			if err := r.decode(&setting); err != nil {
+				return nil, err
+			}
+			setting.Name = name
+		}
+		if index < 0 {
+			bt.config.Settings = append(bt.config.Settings, setting)
+		} else {
+			bt.config.Settings[index] = setting
+		}
+		if r.isText() {
+			return text(setting.Value), nil
+		}
+		return setting, nil
+	case http.MethodDelete:
+		if index >= 0 {
+			bt.config.Settings = append(bt.config.Settings[:index:index], bt.config.Settings[index+1:]...)
+		}
+		return nil, nil
+	}
+	return nil, methodNotAllowed(r)
+}
+
+func (s *Server) describe(bt *buildType) string {
+	return fmt.Sprintf("%s '%s'", bt.kind(), bt.config.ID)
+}
@@ -0,0 +1,419 @@
+package teamcitytest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// Build states
+const (
+	StateQueued   = "queued"
+	StateRunning  = "running"
+	StateFinished = "finished"
+)
+
+// Build statuses
+const (
+	StatusSuccess = "SUCCESS"
+	StatusFailure = "FAILURE"
+	StatusUnknown = "UNKNOWN"
+)
+
+type buildTrigger struct {
+	Type string         `json:"type"`
+	Date types.JSONTime `json:"date"`
+	User *struct {
+		Username string `json:"username"`
+	} `json:"user,omitempty"`
+}
+
+type buildAgent struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type canceledInfo struct {
+	Text      string         `json:"text"`
+	Timestamp types.JSONTime `json:"timestamp"`
+}
+
+// build is a queued, running or finished build in its JSON form
+type build struct {
+	ID            int64            `json:"id"`
+	BuildTypeID   string           `json:"buildTypeId"`
+	Number        string           `json:"number,omitempty"`
+	Status        string           `json:"status,omitempty"`
+	StatusText    string           `json:"statusText,omitempty"`
+	State         string           `json:"state"`
+	BranchName    string           `json:"branchName,omitempty"`
+	DefaultBranch bool             `json:"defaultBranch,omitempty"`
+	Running       bool             `json:"running,omitempty"`
+	QueuePosition int              `json:"queuePosition,omitempty"`
+	Href          string           `json:"href"`
+	WebURL        string           `json:"webUrl"`
+	BuildType     buildTypeShort   `json:"buildType"`
+	QueuedDate    types.JSONTime   `json:"queuedDate,omitempty"`
+	StartDate     types.JSONTime   `json:"startDate,omitempty"`
+	FinishDate    types.JSONTime   `json:"finishDate,omitempty"`
+	Triggered     buildTrigger     `json:"triggered"`
+	Agent         *buildAgent      `json:"agent,omitempty"`
+	Properties    types.Properties `json:"properties"`
+	CanceledInfo  *canceledInfo    `json:"canceledInfo,omitempty"`
+}
+
+func (s *Server) buildJSON(b *build) build {
+	out := *b
+	if b.State == StateQueued {
+		out.Href = fmt.Sprintf("/app/rest/buildQueue/id:%d", b.ID)
+		for _, queued := range s.builds {
+			if queued.State == StateQueued && queued.ID <= b.ID {
+				out.QueuePosition++
+			}
+		}
+	} else {
+		out.Href = fmt.Sprintf("/app/rest/builds/id:%d", b.ID)
+	}
+	out.WebURL = fmt.Sprintf("%s/viewLog.html?buildId=%d&buildTypeId=%s", s.URL, b.ID, b.BuildTypeID)
+	if bt, ok := s.buildTypes[b.BuildTypeID]; ok {
+		out.BuildType = s.buildTypeShort(bt)
+	}
+	return out
+}
+
+func (s *Server) findBuild(locator string) (*build, error) {
+	id, err := strconv.ParseInt(locatorID(locator), 10, 64)
+	if err == nil {
+		for _, b := range s.builds {
+			if b.ID == id {
+				return b, nil
+			}
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "No build found by locator '%s'.", locator)
+}
+
+func (s *Server) routeBuildQueue(r *request, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			locator := r.locator()
+			locator["state"] = StateQueued
+			return s.listBuilds(locator), nil
+		case http.MethodPost:
+			return s.queueBuild(r)
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	b, err := s.findBuild(path[0])
+	if err == nil && b.State != StateQueued {
+		err = errorf(http.StatusNotFound, "No queued build found by locator '%s'.", path[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.buildJSON(b), nil
+		case http.MethodPost:
+			return s.cancelBuild(r, b)
+		case http.MethodDelete:
+			s.cancel(b, "")
+			return nil, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+	return nil, notFound(r)
+}
+
+func (s *Server) routeBuilds(r *request, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		if r.Method == http.MethodGet {
+			return s.listBuilds(r.locator()), nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	b, err := s.findBuild(path[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.buildJSON(b), nil
+		case http.MethodPost:
+			return s.cancelBuild(r, b)
+		case http.MethodDelete:
+			if b.State != StateFinished {
+				return nil, badRequest("Build %d is not finished and cannot be deleted.", b.ID)
+			}
+			for i, other := range s.builds {
+				if other == b {
+					s.builds = append(s.builds[:i:i], s.builds[i+1:]...)
+				}
+			}
+			return nil, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+	if len(path) == 2 && path[1] == "resulting-properties" && r.Method == http.MethodGet {
+		return s.resultingProperties(b), nil
+	}
+	return nil, notFound(r)
+}
+
+func (s *Server) queueBuild(r *request) (interface{}, error) {
+	var input struct {
+		BuildTypeID string `json:"buildTypeId"`
+		BuildType   *struct {
+			ID string `json:"id"`
+		} `json:"buildType"`
+		BranchName string           `json:"branchName"`
+		Properties types.Properties `json:"properties"`
+	}
+	if err := r.decode(&input); err != nil {
+		return nil, err
+	}
+	id := input.BuildTypeID
+	if id == "" && input.BuildType != nil {
+		id = input.BuildType.ID
+	}
+	if id == "" {
+		return nil, badRequest("No build type specified.")
+	}
+	bt, err := s.findBuildType("id:" + id)
+	if err != nil {
+		return nil, err
+	}
+	if bt.config.TemplateFlag {
+		return nil, badRequest("Cannot trigger a build of template '%s'.", bt.config.ID)
+	}
+
+	b := &build{
+		ID:            s.nextBuildID,
+		BuildTypeID:   bt.config.ID,
+		State:         StateQueued,
+		BranchName:    input.BranchName,
+		DefaultBranch: input.BranchName == "",
+		QueuedDate:    timestamp(),
+		Properties:    input.Properties,
+	}
+	if b.Properties == nil {
+		b.Properties = make(types.Properties)
+	}
+	b.Triggered = buildTrigger{Type: "user", Date: b.QueuedDate}
+	if username, _, ok := r.BasicAuth(); ok {
+		b.Triggered.User = &struct {
+			Username string `json:"username"`
+		}{username}
+	}
+	s.nextBuildID++
+	s.builds = append(s.builds, b)
+	return s.buildJSON(b), nil
+}
+
+func (s *Server) cancelBuild(r *request, b *build) (interface{}, error) {
+	var input struct {
+		Comment       string `json:"comment"`
+		ReadIntoQueue bool   `json:"readIntoQueue"`
+	}
+	if len(r.body) > 0 {
+		if err := r.decode(&input); err != nil {
+			return nil, err
+		}
+	}
+	if b.State == StateRunning && input.ReadIntoQueue {
+		b.State = StateQueued
+		b.Running = false
+		b.Number = ""
+		b.Status = ""
+		b.StartDate = ""
+		b.Agent = nil
+		return s.buildJSON(b), nil
+	}
+	s.cancel(b, input.Comment)
+	return s.buildJSON(b), nil
+}
+
+// cancel finishes a queued or running build as canceled
+func (s *Server) cancel(b *build, comment string) {
+	if b.State == StateFinished {
+		return
+	}
+	now := timestamp()
+	b.State = StateFinished
+	b.Running = false
+	b.Status = StatusUnknown
+	b.StatusText = "Canceled"
+	b.FinishDate = now
+	b.CanceledInfo = &canceledInfo{Text: comment, Timestamp: now}
+}
+
+func (s *Server) resultingProperties(b *build) types.Properties {
+	properties := make(types.Properties)
+	if bt, ok := s.buildTypes[b.BuildTypeID]; ok {
+		if project, ok := s.projects[bt.config.ProjectID]; ok {
+			for name, parameter := range mergeParameters(s.inheritedProjectParameters(project), project.Parameters) {
+				properties[name] = visibleParameter(parameter).Value
+			}
+		}
+		for name, parameter := range mergeParameters(s.templateParameters(bt), bt.config.Parameters) {
+			properties[name] = visibleParameter(parameter).Value
+		}
+	}
+	for name, value := range b.Properties {
+		properties[name] = value
+	}
+	properties["teamcity.build.id"] = strconv.FormatInt(b.ID, 10)
+	properties["system.teamcity.buildType.id"] = b.BuildTypeID
+	if b.Number != "" {
+		properties["build.number"] = b.Number
+	}
+	return properties
+}
+
+// listBuilds supports the id, buildType, affectedProject, project, branch,
+// number, state, running, status and count locator dimensions. Queued builds
+// are only listed when asked for with state, newest builds come first.
+func (s *Server) listBuilds(locator map[string]string) interface{} {
+	filters := make([]func(*build) bool, 0)
+	add := func(f func(*build) bool) { filters = append(filters, f) }
+
+	state, hasState := locator["state"]
+	switch {
+	case !hasState:
+		add(func(b *build) bool { return b.State != StateQueued })
+	case state != "any":
+		add(func(b *build) bool { return b.State == state })
+	}
+	if value, ok := locator["id"]; ok {
+		add(func(b *build) bool { return strconv.FormatInt(b.ID, 10) == value })
+	}
+	if value, ok := locator["buildType"]; ok {
+		id := locatorID(value)
+		add(func(b *build) bool { return b.BuildTypeID == id })
+	}
+	for _, dimension := range []string{"project", "affectedProject"} {
+		value, ok := locator[dimension]
+		if !ok {
+			continue
+		}
+		projects := map[string]bool{locatorID(value): true}
+		if dimension == "affectedProject" {
+			for _, id := range s.descendants(locatorID(value)) {
+				projects[id] = true
+			}
+		}
+		add(func(b *build) bool {
+			bt, ok := s.buildTypes[b.BuildTypeID]
+			return ok && projects[bt.config.ProjectID]
+		})
+	}
+	if value, ok := locator["branch"]; ok {
+		branch := parseLocator(value)
+		add(func(b *build) bool {
+			switch {
+			case branch["default"] == "any":
+				return true
+			case branch["default"] == "true" || branch[""] == "<default>":
+				return b.DefaultBranch
+			}
+			name := branch["name"]
+			if name == "" {
+				name = branch[""]
+			}
+			return strings.TrimPrefix(b.BranchName, "refs/heads/") == strings.TrimPrefix(name, "refs/heads/")
+		})
+	}
+	if value, ok := locator["number"]; ok {
+		add(func(b *build) bool { return b.Number == value })
+	}
+	if value, ok := locator["running"]; ok && value != "any" {
+		add(func(b *build) bool { return b.Running == (value == "true") })
+	}
+	if value, ok := locator["status"]; ok {
+		add(func(b *build) bool { return b.Status == strings.ToUpper(value) })
+	}
+
+	list := make([]build, 0)
+	for _, b := range s.builds {
+		matches := true
+		for _, filter := range filters {
+			matches = matches && filter(b)
+		}
+		if matches {
+			list = append(list, s.buildJSON(b))
+		}
+	}
+	if !hasState || state != StateQueued {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].ID > list[j].ID })
+	}
+	if value, ok := locator["count"]; ok {
+		if count, err := strconv.Atoi(value); err == nil && count < len(list) {
+			list = list[:count]
+		}
+	}
+	return struct {
+		Count int     `json:"count"`
+		Href  string  `json:"href"`
+		Build []build `json:"build"`
+	}{len(list), "/app/rest/builds", list}
+}
+
+// StartBuild takes the queued build with the given ID from the queue and
+// runs it on the named agent. The build gets the next number of its build
+// configuration.
+func (s *Server) StartBuild(id int64, agent string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.findBuild(fmt.Sprintf("id:%d", id))
+	if err != nil {
+		return err
+	}
+	if b.State != StateQueued {
+		return fmt.Errorf("build %d is %s, not queued", id, b.State)
+	}
+	number := 1
+	for _, other := range s.builds {
+		if other.BuildTypeID == b.BuildTypeID && other.Number != "" {
+			if n, err := strconv.Atoi(other.Number); err == nil && n >= number {
+				number = n + 1
+			}
+		}
+	}
+	b.State = StateRunning
+	b.Running = true
+	b.Status = StatusSuccess
+	b.StatusText = "Running"
+	b.Number = strconv.Itoa(number)
+	b.StartDate = timestamp()
+	b.Agent = &buildAgent{ID: 1, Name: agent}
+	return nil
+}
+
+// FinishBuild finishes the running build with the given ID with a status
+// such as StatusSuccess or StatusFailure
+func (s *Server) FinishBuild(id int64, status string, statusText string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, err := s.findBuild(fmt.Sprintf("id:%d", id))
+	if err != nil {
+		return err
+	}
+	if b.State != StateRunning {
+		return fmt.Errorf("build %d is %s, not running", id, b.State)
+	}
+	b.State = StateFinished
+	b.Running = false
+	b.Status = status
+	b.StatusText = statusText
+	b.FinishDate = timestamp()
+	return nil
+}
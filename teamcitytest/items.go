@@ -0,0 +1,284 @@
+package teamcitytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// item is a step, trigger, feature, dependency or agent requirement. All of
+// them share this JSON form, dependencies add their source.
+type item struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name,omitempty"`
+	Type            string           `json:"type"`
+	Disabled        bool             `json:"disabled,omitempty"`
+	Inherited       bool             `json:"inherited,omitempty"`
+	Properties      types.Properties `json:"properties"`
+	SourceBuildType *buildTypeShort  `json:"source-buildType,omitempty"`
+}
+
+func (it item) clone() item {
+	properties := make(types.Properties)
+	for name, value := range it.Properties {
+		properties[name] = value
+	}
+	it.Properties = properties
+	if it.SourceBuildType != nil {
+		source := *it.SourceBuildType
+		it.SourceBuildType = &source
+	}
+	return it
+}
+
+// collection describes one kind of item and where it is served
+type collection struct {
+	// path is the URL segment and the key in build configuration JSON
+	path string
+	// key holds the items in collection JSON
+	key string
+	// name is used in messages
+	name string
+	// prefix of generated IDs. Snapshot dependencies have no prefix, their
+	// ID is that of the source build configuration.
+	prefix string
+	// defaultType is used when a new item has no type
+	defaultType string
+}
+
+var (
+	steps                = collection{"steps", "step", "step", "RUNNER_", ""}
+	features             = collection{"features", "feature", "feature", "BUILD_EXT_", ""}
+	triggers             = collection{"triggers", "trigger", "trigger", "TRIGGER_", ""}
+	snapshotDependencies = collection{"snapshot-dependencies", "snapshot-dependency", "snapshot dependency", "", "snapshot_dependency"}
+	artifactDependencies = collection{"artifact-dependencies", "artifact-dependency", "artifact dependency", "ARTIFACT_DEPENDENCY_", "artifact_dependency"}
+	agentRequirements    = collection{"agent-requirements", "agent-requirement", "agent requirement", "RQ_", ""}
+
+	collections = []collection{steps, features, triggers, snapshotDependencies, artifactDependencies, agentRequirements}
+)
+
+func findCollection(path string) (collection, bool) {
+	for _, c := range collections {
+		if c.path == path {
+			return c, true
+		}
+	}
+	return collection{}, false
+}
+
+func (c collection) hasSource() bool {
+	return c.defaultType != ""
+}
+
+// list returns the collection JSON holding items
+func (c collection) list(items []item) interface{} {
+	if items == nil {
+		items = make([]item, 0)
+	}
+	return map[string]interface{}{
+		"count": len(items),
+		c.key:   items,
+	}
+}
+
+func (c collection) decodeList(raw json.RawMessage) ([]item, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var lists map[string][]item
+	if err := json.Unmarshal(raw, &lists); err != nil {
+		return nil, badRequest("Cannot parse %s list: %s", c.name, err)
+	}
+	return lists[c.key], nil
+}
+
+// effectiveItems returns the items the build configuration inherits from its
+// templates followed by its own. Own items replace inherited ones with the
+// same ID.
+func (s *Server) effectiveItems(bt *buildType, c collection) []item {
+	own := make(map[string]bool)
+	for _, it := range bt.items[c.path] {
+		own[it.ID] = true
+	}
+	items := make([]item, 0)
+	seen := make(map[string]bool)
+	for _, template := range s.templates(bt) {
+		for _, it := range template.items[c.path] {
+			if own[it.ID] || seen[it.ID] {
+				continue
+			}
+			seen[it.ID] = true
+			it.Inherited = true
+			items = append(items, it)
+		}
+	}
+	return append(items, bt.items[c.path]...)
+}
+
+func (s *Server) findItem(bt *buildType, c collection, id string) (int, error) {
+	for i, it := range bt.items[c.path] {
+		if it.ID == id {
+			return i, nil
+		}
+	}
+	for _, it := range s.effectiveItems(bt, c) {
+		if it.ID == id {
+			return -1, badRequest("The %s with id '%s' is inherited from a template and cannot be changed in %s.", c.name, id, s.describe(bt))
+		}
+	}
+	return -1, errorf(http.StatusNotFound, "No %s with id '%s' is found in %s.", c.name, id, s.describe(bt))
+}
+
+// prepareItem validates an item and fills in its source and ID. index is
+// the position of the item being replaced, or -1 for a new one.
+func (s *Server) prepareItem(bt *buildType, c collection, it item, index int) (item, error) {
+	it.Inherited = false
+	if it.Properties == nil {
+		it.Properties = make(types.Properties)
+	}
+	if it.Type == "" {
+		it.Type = c.defaultType
+	}
+	if it.Type == "" {
+		return it, badRequest("The %s type is not specified.", c.name)
+	}
+
+	if c.hasSource() {
+		if it.SourceBuildType == nil || it.SourceBuildType.ID == "" {
+			return it, badRequest("The %s source build configuration is not specified.", c.name)
+		}
+		source, err := s.findBuildType("id:" + it.SourceBuildType.ID)
+		if err != nil {
+			return it, err
+		}
+		if source.config.ID == bt.config.ID {
+			return it, badRequest("%s cannot depend on itself.", s.describe(bt))
+		}
+		short := s.buildTypeShort(source)
+		it.SourceBuildType = &short
+		if c.prefix == "" {
+			it.ID = source.config.ID
+		}
+	}
+
+	if it.ID == "" {
+		taken := func(id string) bool {
+			for _, other := range s.effectiveItems(bt, c) {
+				if other.ID == id {
+					return true
+				}
+			}
+			return false
+		}
+		for it.ID == "" || taken(it.ID) {
+			bt.counters[c.path]++
+			it.ID = c.prefix + strconv.Itoa(bt.counters[c.path])
+		}
+	}
+	for i, other := range bt.items[c.path] {
+		if other.ID == it.ID && i != index {
+			return it, badRequest("%s already has a %s with id '%s'.", s.describe(bt), c.name, it.ID)
+		}
+	}
+	return it, nil
+}
+
+func (s *Server) addItem(bt *buildType, c collection, it item) (item, error) {
+	it, err := s.prepareItem(bt, c, it, -1)
+	if err != nil {
+		return it, err
+	}
+	bt.items[c.path] = append(bt.items[c.path], it)
+	return it, nil
+}
+
+func (s *Server) routeItems(r *request, bt *buildType, c collection, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return c.list(s.effectiveItems(bt, c)), nil
+		case http.MethodPost:
+			var it item
+			if err := r.decode(&it); err != nil {
+				return nil, err
+			}
+			return s.addItem(bt, c, it)
+		case http.MethodPut:
+			items, err := c.decodeList(r.body)
+			if err != nil {
+				return nil, err
+			}
+			previous := bt.items[c.path]
+			bt.items[c.path] = nil
+			for _, it := range items {
+				if it.Inherited {
+					continue
+				}
+				if _, err := s.addItem(bt, c, it); err != nil {
+					bt.items[c.path] = previous
+					return nil, err
+				}
+			}
+			return c.list(s.effectiveItems(bt, c)), nil
+		case http.MethodDelete:
+			bt.items[c.path] = nil
+			return nil, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	id := path[0]
+	if len(path) == 1 && r.Method == http.MethodGet {
+		for _, it := range s.effectiveItems(bt, c) {
+			if it.ID == id {
+				return it, nil
+			}
+		}
+	}
+	index, err := s.findItem(bt, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(path) == 2 && path[1] == "disabled" {
+		switch r.Method {
+		case http.MethodGet:
+			return text(strconv.FormatBool(bt.items[c.path][index].Disabled)), nil
+		case http.MethodPut:
+			disabled, err := strconv.ParseBool(string(r.body))
+			if err != nil {
+				return nil, badRequest("Invalid boolean value \"%s\".", string(r.body))
+			}
+			bt.items[c.path][index].Disabled = disabled
+			return text(strconv.FormatBool(disabled)), nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+	if len(path) != 1 {
+		return nil, notFound(r)
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var it item
+		if err := r.decode(&it); err != nil {
+			return nil, err
+		}
+		if it.ID == "" {
+			it.ID = id
+		}
+		it, err := s.prepareItem(bt, c, it, index)
+		if err != nil {
+			return nil, err
+		}
+		bt.items[c.path][index] = it
+		return it, nil
+	case http.MethodDelete:
+		items := bt.items[c.path]
+		bt.items[c.path] = append(items[:index:index], items[index+1:]...)
+		return nil, nil
+	}
+	return nil, methodNotAllowed(r)
+}
@@ -0,0 +1,166 @@
+package teamcitytest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+type parameterType struct {
+	RawValue string `json:"rawValue"`
+}
+
+// parameterJSON is a parameter as the server sends it. Unlike the form
+// written by the client it carries the inherited flag.
+type parameterJSON struct {
+	Name      string         `json:"name"`
+	Value     string         `json:"value"`
+	Inherited bool           `json:"inherited,omitempty"`
+	Type      *parameterType `json:"type,omitempty"`
+}
+
+func parameterEntry(name string, parameter types.Parameter) parameterJSON {
+	parameter = visibleParameter(parameter)
+	entry := parameterJSON{Name: name, Value: parameter.Value, Inherited: parameter.Inherited}
+	if parameter.Spec != nil {
+		entry.Type = &parameterType{RawValue: parameter.Spec.String()}
+	}
+	return entry
+}
+
+func parameterList(parameters types.Parameters) interface{} {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]parameterJSON, 0, len(names))
+	for _, name := range names {
+		entries = append(entries, parameterEntry(name, parameters[name]))
+	}
+	return struct {
+		Count    int             `json:"count"`
+		Property []parameterJSON `json:"property"`
+	}{len(entries), entries}
+}
+
+// mergeParameters returns the parameters an entity shows: those it inherits,
+// marked as such, overridden by its own
+func mergeParameters(inherited types.Parameters, own types.Parameters) types.Parameters {
+	merged := make(types.Parameters)
+	for name, parameter := range inherited {
+		parameter.Inherited = true
+		merged[name] = parameter
+	}
+	for name, parameter := range own {
+		parameter.Inherited = false
+		merged[name] = parameter
+	}
+	return merged
+}
+
+// visibleParameter blanks the value of a password parameter, which TeamCity
+// never returns
+func visibleParameter(parameter types.Parameter) types.Parameter {
+	if parameter.Spec != nil {
+		if _, ok := parameter.Spec.Type.(types.PasswordType); ok {
+			parameter.Value = ""
+		}
+	}
+	return parameter
+}
+
+// decodeParameter reads a single parameter in the same form the entries of
+// a parameters collection have
+func decodeParameter(r *request) (types.NamedParameter, error) {
+	body := append([]byte(`{"property":[`), r.body...)
+	body = append(body, []byte(`]}`)...)
+	var parameters types.Parameters
+	if err := (&request{body: body}).decode(&parameters); err != nil {
+		return types.NamedParameter{}, err
+	}
+	for name, parameter := range parameters {
+		return types.NamedParameter{Name: name, Parameter: parameter}, nil
+	}
+	return types.NamedParameter{}, badRequest("No parameter given.")
+}
+
+// routeParameters serves the parameters of a project or build configuration.
+// own holds the parameters defined on the entity itself and inherited those
+// it gets from parent projects or templates.
+func routeParameters(r *request, path []string, own *types.Parameters, inherited types.Parameters) (interface{}, error) {
+	if *own == nil {
+		*own = make(types.Parameters)
+	}
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return parameterList(mergeParameters(inherited, *own)), nil
+		case http.MethodPut:
+			var parameters types.Parameters
+			if err := r.decode(&parameters); err != nil {
+				return nil, err
+			}
+			*own = parameters.Own()
+			return parameterList(mergeParameters(inherited, *own)), nil
+		case http.MethodPost:
+			parameter, err := decodeParameter(r)
+			if err != nil {
+				return nil, err
+			}
+			if parameter.Name == "" {
+				return nil, badRequest("Parameter name cannot be empty.")
+			}
+			(*own)[parameter.Name] = parameter.Parameter
+			return parameterEntry(parameter.Name, parameter.Parameter), nil
+		case http.MethodDelete:
+			*own = make(types.Parameters)
+			return nil, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	name := path[0]
+	merged := mergeParameters(inherited, *own)
+	if len(path) == 2 && path[1] == "value" {
+		path = path[:1]
+		if r.Method == http.MethodGet {
+			if parameter, ok := merged[name]; ok {
+				return text(visibleParameter(parameter).Value), nil
+			}
+		}
+	}
+	if len(path) != 1 {
+		return nil, notFound(r)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		parameter, ok := merged[name]
+		if !ok {
+			return nil, errorf(http.StatusNotFound, "No parameter with name '%s' is found.", name)
+		}
+		return parameterEntry(name, parameter), nil
+	case http.MethodPut:
+		if r.isText() {
+			parameter := merged[name]
+			parameter.Value = string(r.body)
+			parameter.Inherited = false
+			(*own)[name] = parameter
+			return text(visibleParameter(parameter).Value), nil
+		}
+		parameter, err := decodeParameter(r)
+		if err != nil {
+			return nil, err
+		}
+		(*own)[name] = parameter.Parameter
+		return parameterEntry(name, parameter.Parameter), nil
+	case http.MethodDelete:
+		if _, ok := (*own)[name]; !ok {
+			return nil, errorf(http.StatusNotFound, "No own parameter with name '%s' is found.", name)
+		}
+		delete(*own, name)
+		return nil, nil
+	}
+	return nil, methodNotAllowed(r)
+}
@@ -0,0 +1,435 @@
+package teamcitytest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// projectReference is the form a project is referred to in request bodies,
+// either by ID or by locator
+type projectReference struct {
+	ID      string `json:"id"`
+	Locator string `json:"locator"`
+}
+
+func (p *projectReference) id() string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return locatorID(p.Locator)
+}
+
+func (s *Server) findProject(locator string) (*types.Project, error) {
+	id := locatorID(locator)
+	project, ok := s.projects[id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "No project found by locator '%s'. Project cannot be found by external id '%s'.", locator, id)
+	}
+	return project, nil
+}
+
+// ancestors returns the projects from the root project down to the parent of
+// the given one
+func (s *Server) ancestors(project *types.Project) []*types.Project {
+	chain := make([]*types.Project, 0)
+	for parentID := string(project.ParentProjectID); parentID != ""; {
+		parent := s.projects[parentID]
+		chain = append([]*types.Project{parent}, chain...)
+		parentID = string(parent.ParentProjectID)
+	}
+	return chain
+}
+
+// descendants returns the IDs of the project and all its subprojects
+func (s *Server) descendants(projectID string) []string {
+	ids := []string{projectID}
+	for _, child := range s.children(projectID) {
+		ids = append(ids, s.descendants(child.ID)...)
+	}
+	return ids
+}
+
+func (s *Server) children(projectID string) []*types.Project {
+	children := make([]*types.Project, 0)
+	for _, project := range s.projects {
+		if string(project.ParentProjectID) == projectID {
+			children = append(children, project)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].ID < children[j].ID })
+	return children
+}
+
+func (s *Server) inheritedProjectParameters(project *types.Project) types.Parameters {
+	inherited := make(types.Parameters)
+	for _, ancestor := range s.ancestors(project) {
+		for name, parameter := range ancestor.Parameters {
+			inherited[name] = parameter
+		}
+	}
+	return inherited
+}
+
+func (s *Server) projectHref(id string) string {
+	return "/app/rest/projects/id:" + id
+}
+
+func (s *Server) projectShort(project *types.Project) types.ProjectShort {
+	return types.ProjectShort{
+		ID:              project.ID,
+		Name:            project.Name,
+		Description:     project.Description,
+		ParentProjectID: string(project.ParentProjectID),
+		Href:            s.projectHref(project.ID),
+		WebURL:          s.URL + "/project.html?projectId=" + project.ID,
+	}
+}
+
+func (s *Server) projectJSON(project *types.Project) (interface{}, error) {
+	out := types.Project{
+		ID:                  project.ID,
+		Name:                project.Name,
+		Description:         project.Description,
+		Href:                s.projectHref(project.ID),
+		WebUrl:              s.URL + "/project.html?projectId=" + project.ID,
+		ParentProjectID:     project.ParentProjectID,
+		Archived:            project.Archived,
+		BuildConfigurations: make(types.BuildConfigurations),
+		Templates:           make(types.BuildConfigurations),
+		Projects:            make(types.Projects),
+	}
+	for _, bt := range s.buildTypes {
+		if bt.config.ProjectID != project.ID {
+			continue
+		}
+		short := types.BuildConfiguration{
+			ID:           bt.config.ID,
+			ProjectID:    bt.config.ProjectID,
+			TemplateFlag: bt.config.TemplateFlag,
+			Name:         bt.config.Name,
+		}
+		if bt.config.TemplateFlag {
+			out.Templates[short.ID] = short
+		} else {
+			out.BuildConfigurations[short.ID] = short
+		}
+	}
+	for _, child := range s.children(project.ID) {
+		out.Projects[child.ID] = types.Project{
+			ID:              child.ID,
+			Name:            child.Name,
+			Description:     child.Description,
+			Href:            s.projectHref(child.ID),
+			ParentProjectID: child.ParentProjectID,
+		}
+	}
+	parameters := mergeParameters(s.inheritedProjectParameters(project), project.Parameters)
+	return withFields(out, map[string]interface{}{"parameters": parameterList(parameters)})
+}
+
+func (s *Server) routeProjects(r *request, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listProjects(), nil
+		case http.MethodPost:
+			return s.createProject(r)
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	project, err := s.findProject(path[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.projectJSON(project)
+		case http.MethodDelete:
+			return nil, s.deleteProject(project)
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	switch path[1] {
+	case "parameters":
+		return routeParameters(r, path[2:], &project.Parameters, s.inheritedProjectParameters(project))
+	case "buildTypes", "templates":
+		if len(path) == 2 {
+			return s.routeProjectBuildTypes(r, project, path[1] == "templates")
+		}
+	case "parentProject":
+		if len(path) == 2 {
+			return s.routeParentProject(r, project)
+		}
+	case "secure":
+		if len(path) == 3 && path[2] == "tokens" && r.Method == http.MethodPost {
+			return s.createSecureToken(r), nil
+		}
+	case "id", "name", "description", "archived":
+		if len(path) == 2 {
+			return s.routeProjectField(r, project, path[1])
+		}
+	}
+	return nil, notFound(r)
+}
+
+func (s *Server) listProjects() interface{} {
+	ids := make([]string, 0, len(s.projects))
+	for id := range s.projects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	projects := make([]types.ProjectShort, 0, len(ids))
+	for _, id := range ids {
+		projects = append(projects, s.projectShort(s.projects[id]))
+	}
+	return struct {
+		Count   int                  `json:"count"`
+		Href    string               `json:"href"`
+		Project []types.ProjectShort `json:"project"`
+	}{len(projects), "/app/rest/projects", projects}
+}
+
+func (s *Server) projectIDTaken(id string) bool {
+	_, ok := s.projects[id]
+	return ok
+}
+
+func (s *Server) checkProjectName(parentID string, name string, except string) error {
+	if name == "" {
+		return badRequest("Project name cannot be empty.")
+	}
+	for _, sibling := range s.children(parentID) {
+		if sibling.Name == name && sibling.ID != except {
+			return badRequest("Project with name \"%s\" already exists in the parent project \"%s\".", name, parentID)
+		}
+	}
+	return nil
+}
+
+// newProjectID validates the requested project ID or generates one
+func (s *Server) newProjectID(id string, parentID string, name string) (string, error) {
+	if id == "" {
+		return generateID(parentID, name, s.projectIDTaken), nil
+	}
+	if err := checkID("Project", id); err != nil {
+		return "", err
+	}
+	if s.projectIDTaken(id) {
+		return "", badRequest("Project ID \"%s\" is already used by another project.", id)
+	}
+	return id, nil
+}
+
+func (s *Server) createProject(r *request) (interface{}, error) {
+	var input struct {
+		ID            string            `json:"id"`
+		Name          string            `json:"name"`
+		Description   string            `json:"description"`
+		ParentProject *projectReference `json:"parentProject"`
+		SourceProject *projectReference `json:"sourceProject"`
+		Parameters    types.Parameters  `json:"parameters"`
+	}
+	if err := r.decode(&input); err != nil {
+		return nil, err
+	}
+
+	parentID := RootProjectID
+	if input.ParentProject != nil && input.ParentProject.id() != "" {
+		parentID = input.ParentProject.id()
+	}
+	parent, err := s.findProject("id:" + parentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProjectName(parent.ID, input.Name, ""); err != nil {
+		return nil, err
+	}
+	id, err := s.newProjectID(input.ID, parent.ID, input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.SourceProject != nil {
+		source, err := s.findProject(input.SourceProject.Locator)
+		if err != nil {
+			return nil, err
+		}
+		if source.ID == RootProjectID {
+			return nil, badRequest("Cannot copy the root project.")
+		}
+		mapping := make(map[string]string)
+		project := s.copyProject(source, parent.ID, id, input.Name, mapping)
+		for _, newID := range mapping {
+			if bt, ok := s.buildTypes[newID]; ok {
+				bt.remap(mapping)
+			}
+		}
+		return s.projectJSON(project)
+	}
+
+	project := &types.Project{
+		ID:              id,
+		Name:            input.Name,
+		Description:     input.Description,
+		ParentProjectID: types.ProjectId(parent.ID),
+		Parameters:      input.Parameters.Own(),
+	}
+	s.projects[id] = project
+	return s.projectJSON(project)
+}
+
+// copyIDFor gives a copied entity the ID of the original with the source
+// project prefix replaced, or a generated one
+func copyIDFor(id string, sourceID string, targetID string, name string, taken func(string) bool) string {
+	if strings.HasPrefix(id, sourceID) {
+		candidate := targetID + strings.TrimPrefix(id, sourceID)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+	return generateID(targetID, name, taken)
+}
+
+// copyProject copies the project with its parameters, build configurations,
+// templates and subprojects. mapping collects the IDs given to copied build
+// configurations and templates so references between them can be updated.
+func (s *Server) copyProject(source *types.Project, parentID string, id string, name string, mapping map[string]string) *types.Project {
+	project := &types.Project{
+		ID:              id,
+		Name:            name,
+		Description:     source.Description,
+		ParentProjectID: types.ProjectId(parentID),
+		Parameters:      copyParameters(source.Parameters),
+	}
+	s.projects[id] = project
+
+	for _, bt := range s.projectBuildTypes(source.ID) {
+		clone := bt.clone()
+		clone.config.ID = copyIDFor(bt.config.ID, source.ID, id, bt.config.Name, s.buildTypeIDTaken)
+		clone.config.ProjectID = id
+		s.buildTypes[clone.config.ID] = clone
+		mapping[bt.config.ID] = clone.config.ID
+	}
+	for _, child := range s.children(source.ID) {
+		if child.ID == id {
+			continue
+		}
+		childID := copyIDFor(child.ID, source.ID, id, child.Name, s.projectIDTaken)
+		s.copyProject(child, id, childID, child.Name, mapping)
+	}
+	return project
+}
+
+func copyParameters(parameters types.Parameters) types.Parameters {
+	copied := make(types.Parameters)
+	for name, parameter := range parameters {
+		copied[name] = parameter
+	}
+	return copied
+}
+
+func (s *Server) deleteProject(project *types.Project) error {
+	if project.ID == RootProjectID {
+		return badRequest("Cannot delete the root project.")
+	}
+	for _, id := range s.descendants(project.ID) {
+		for btID, bt := range s.buildTypes {
+			if bt.config.ProjectID == id {
+				delete(s.buildTypes, btID)
+			}
+		}
+		for rootID, root := range s.vcsRoots {
+			if string(root.ProjectID) == id {
+				s.deleteVcsRoot(rootID)
+			}
+		}
+		delete(s.projects, id)
+	}
+	return nil
+}
+
+func (s *Server) routeParentProject(r *request, project *types.Project) (interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		if project.ParentProjectID == "" {
+			return nil, errorf(http.StatusNotFound, "The root project has no parent.")
+		}
+		return s.projectJSON(s.projects[string(project.ParentProjectID)])
+	case http.MethodPut:
+		var reference projectReference
+		if err := r.decode(&reference); err != nil {
+			return nil, err
+		}
+		parent, err := s.findProject("id:" + reference.id())
+		if err != nil {
+			return nil, err
+		}
+		if project.ID == RootProjectID {
+			return nil, badRequest("Cannot move the root project.")
+		}
+		for _, id := range s.descendants(project.ID) {
+			if id == parent.ID {
+				return nil, badRequest("Cannot move project \"%s\" into itself or its subproject \"%s\".", project.ID, parent.ID)
+			}
+		}
+		if err := s.checkProjectName(parent.ID, project.Name, project.ID); err != nil {
+			return nil, err
+		}
+		project.ParentProjectID = types.ProjectId(parent.ID)
+		return s.projectJSON(project)
+	}
+	return nil, methodNotAllowed(r)
+}
+
+func (s *Server) routeProjectField(r *request, project *types.Project, field string) (interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		switch field {
+		case "id":
+			return text(project.ID), nil
+		case "name":
+			return text(project.Name), nil
+		case "description":
+			return text(project.Description), nil
+		}
+		return text(strconv.FormatBool(project.Archived)), nil
+	case http.MethodPut:
+		value := string(r.body)
+		switch field {
+		case "id":
+			return nil, badRequest("Changing the project ID is not supported.")
+		case "name":
+			if err := s.checkProjectName(string(project.ParentProjectID), value, project.ID); err != nil {
+				return nil, err
+			}
+			project.Name = value
+		case "description":
+			project.Description = value
+		case "archived":
+			archived, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, badRequest("Invalid boolean value \"%s\".", value)
+			}
+			project.Archived = archived
+		}
+		return text(value), nil
+	}
+	return nil, methodNotAllowed(r)
+}
+
+func (s *Server) createSecureToken(r *request) interface{} {
+	b := make([]byte, 16)
+	rand.Read(b)
+	token := fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	s.tokens[token] = string(r.body)
+	return text(token)
+}
@@ -0,0 +1,226 @@
+package teamcitytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// text is a result written as a plain text body
+type text string
+
+// httpError is a failure reported with a status code and a plain text
+// message, as TeamCity does
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, args ...interface{}) error {
+	return &httpError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return errorf(http.StatusBadRequest, format, args...)
+}
+
+func notFound(r *request) error {
+	return errorf(http.StatusNotFound, "Not found: %s %s", r.Method, r.URL.Path)
+}
+
+func methodNotAllowed(r *request) error {
+	return errorf(http.StatusMethodNotAllowed, "Request method '%s' not supported for %s", r.Method, r.URL.Path)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if e, ok := err.(*httpError); ok {
+		status = e.status
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(status)
+	w.Write([]byte(err.Error()))
+}
+
+func writeResult(w http.ResponseWriter, result interface{}) {
+	switch v := result.(type) {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case text:
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(v))
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+	}
+}
+
+// withFields returns the JSON object v marshals to with some fields replaced
+func withFields(v interface{}, fields map[string]interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{}, len(raw)+len(fields))
+	for name, value := range raw {
+		out[name] = value
+	}
+	for name, value := range fields {
+		out[name] = value
+	}
+	return out, nil
+}
+
+// request is an incoming call with the REST prefix stripped from its path
+type request struct {
+	*http.Request
+	path []string
+	body []byte
+}
+
+// isText reports whether the body is plain text rather than JSON
+func (r *request) isText() bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "text/plain")
+}
+
+func (r *request) decode(v interface{}) error {
+	if err := json.Unmarshal(r.body, v); err != nil {
+		return badRequest("Cannot parse request body: %s", err)
+	}
+	return nil
+}
+
+func (r *request) locator() map[string]string {
+	return parseLocator(r.URL.Query().Get("locator"))
+}
+
+var versionSegment = regexp.MustCompile(`^(latest|\d+\.\d+)$`)
+
+// restPath splits the path below /app/rest, accepting the /httpAuth and
+// /guestAuth prefixes and an optional API version
+func restPath(path string) ([]string, bool) {
+	path = strings.TrimPrefix(path, "/httpAuth")
+	path = strings.TrimPrefix(path, "/guestAuth")
+	if !strings.HasPrefix(path, "/app/rest/") {
+		return nil, false
+	}
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/app/rest/"), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) > 0 && versionSegment.MatchString(segments[0]) {
+		segments = segments[1:]
+	}
+	return segments, true
+}
+
+// parseLocator splits a locator such as "buildType:(id:X),count:1" into its
+// dimensions. Nested locators are returned without the parentheses. A bare
+// value is stored under the empty name.
+func parseLocator(locator string) map[string]string {
+	dimensions := make(map[string]string)
+	if locator == "" {
+		return dimensions
+	}
+	parts := make([]string, 0)
+	depth, start := 0, 0
+	for i, c := range locator {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, locator[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, locator[start:])
+
+	for _, part := range parts {
+		name, value := "", part
+		if i := strings.Index(part, ":"); i >= 0 && !strings.HasPrefix(part, "(") {
+			name, value = part[:i], part[i+1:]
+		}
+		if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+			value = value[1 : len(value)-1]
+		}
+		dimensions[name] = value
+	}
+	return dimensions
+}
+
+// locatorID returns the ID a locator such as "id:X" or a bare "X" selects
+func locatorID(locator string) string {
+	dimensions := parseLocator(locator)
+	if id, ok := dimensions["id"]; ok {
+		return id
+	}
+	return dimensions[""]
+}
+
+var validID = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+func checkID(kind string, id string) error {
+	if !validID.MatchString(id) || len(id) > 225 {
+		return badRequest("%s ID \"%s\" is invalid: it should start with a latin letter and contain only latin letters, digits and underscores (at most 225 characters).", kind, id)
+	}
+	return nil
+}
+
+// generateID derives an ID from the parent ID and a name the way TeamCity
+// does, e.g. "Parent_MyApp" for "My app", adding a number while taken
+func generateID(parentID string, name string, taken func(string) bool) string {
+	var b strings.Builder
+	upper := true
+	for _, c := range name {
+		if c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c)) {
+			if upper {
+				c = unicode.ToUpper(c)
+			}
+			b.WriteRune(c)
+			upper = false
+		} else {
+			upper = true
+		}
+	}
+	base := b.String()
+	if parentID != "" && parentID != RootProjectID {
+		base = parentID + "_" + base
+	}
+	if base == "" || !unicode.IsLetter(rune(base[0])) {
+		base = "Id" + base
+	}
+	id := base
+	for n := 2; taken(id); n++ {
+		id = fmt.Sprintf("%s%d", base, n)
+	}
+	return id
+}
+
+func timestamp() types.JSONTime {
+	return types.JSONTime(time.Now().Format("20060102T150405-0700"))
+}
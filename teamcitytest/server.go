@@ -0,0 +1,146 @@
+// Package teamcitytest provides an in-memory fake TeamCity server for tests.
+//
+// The fake speaks the parts of the REST API used by the teamcity package:
+// projects, build configurations and templates with their parameters,
+// settings, steps, triggers, features, dependencies and agent requirements,
+// VCS roots, the build queue and builds. Entities get IDs the way TeamCity
+// generates them and failures are reported with the status codes and plain
+// text messages of a real server, so tools built on the client can be tested
+// hermetically:
+//
+//	server := teamcitytest.NewServer()
+//	defer server.Close()
+//	client := server.Client()
+//
+// Builds never run on their own; tests move them through their life cycle
+// with StartBuild and FinishBuild.
+package teamcitytest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/icelander/teamcity-sdk-go/teamcity"
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+// RootProjectID is the ID of the project every other project descends from
+const RootProjectID = "_Root"
+
+// Server is a fake TeamCity server listening on a local address
+type Server struct {
+	*httptest.Server
+
+	// Username and Password are required from clients when Username is set
+	Username string
+	Password string
+	// Info is returned by the server endpoint
+	Info types.Server
+
+	mu          sync.Mutex
+	projects    map[string]*types.Project
+	buildTypes  map[string]*buildType
+	vcsRoots    map[string]*types.VcsRoot
+	builds      []*build
+	nextBuildID int64
+	tokens      map[string]string
+}
+
+// NewServer starts a fake server holding only the root project. Callers
+// should Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Info: types.Server{
+			Version:      "2019.2 (build 71499)",
+			VersionMajor: 2019,
+			VersionMinor: 2,
+			BuildNumber:  "71499",
+		},
+		projects: map[string]*types.Project{
+			RootProjectID: {
+				ID:          RootProjectID,
+				Name:        "<Root project>",
+				Description: "Contains all other projects",
+			},
+		},
+		buildTypes:  make(map[string]*buildType),
+		vcsRoots:    make(map[string]*types.VcsRoot),
+		nextBuildID: 1,
+		tokens:      make(map[string]string),
+	}
+	s.Server = httptest.NewServer(s)
+	s.Info.StartTime = timestamp()
+	return s
+}
+
+// Client returns a TeamCity client talking to the server
+func (s *Server) Client() *teamcity.Client {
+	return teamcity.New(s.URL, s.Username, s.Password, "latest")
+}
+
+// SecureValue returns the value stored for a token created through the
+// secure tokens endpoint
+func (s *Server) SecureValue(token string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.tokens[token]
+	return value, ok
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Username != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.Username || password != s.Password {
+			writeError(w, errorf(http.StatusUnauthorized, "Authentication required"))
+			return
+		}
+	}
+
+	path, ok := restPath(r.URL.Path)
+	if !ok {
+		writeError(w, errorf(http.StatusNotFound, "Not found: %s", r.URL.Path))
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, errorf(http.StatusBadRequest, "Cannot read request body: %s", err))
+		return
+	}
+	req := &request{Request: r, path: path, body: body}
+
+	s.mu.Lock()
+	result, err := s.route(req)
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeResult(w, result)
+}
+
+func (s *Server) route(r *request) (interface{}, error) {
+	if len(r.path) == 0 {
+		return nil, notFound(r)
+	}
+	switch r.path[0] {
+	case "server":
+		if len(r.path) == 1 && r.Method == http.MethodGet {
+			info := s.Info
+			info.CurrentTime = timestamp()
+			return info, nil
+		}
+	case "projects":
+		return s.routeProjects(r, r.path[1:])
+	case "buildTypes":
+		return s.routeBuildTypes(r, r.path[1:])
+	case "vcs-roots":
+		return s.routeVcsRoots(r, r.path[1:])
+	case "buildQueue":
+		return s.routeBuildQueue(r, r.path[1:])
+	case "builds":
+		return s.routeBuilds(r, r.path[1:])
+	}
+	return nil, notFound(r)
+}
@@ -0,0 +1,415 @@
+package teamcitytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/icelander/teamcity-sdk-go/teamcity"
+	"github.com/icelander/teamcity-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createProject(t *testing.T, client *teamcity.Client, parentID string, name string) *types.Project {
+	project := &types.Project{Name: name, ParentProjectID: types.ProjectId(parentID)}
+	require.NoError(t, client.CreateProject(project))
+	return project
+}
+
+func TestRestPath(t *testing.T) {
+	for path, expected := range map[string][]string{
+		"/httpAuth/app/rest/latest/projects/id:A": {"projects", "id:A"},
+		"/app/rest/buildTypes/id:A":               {"buildTypes", "id:A"},
+		"/guestAuth/app/rest/2018.1/server":       {"server"},
+		"/httpAuth/app/rest/latest/builds/":       {"builds"},
+	} {
+		segments, ok := restPath(path)
+		assert.True(t, ok, path)
+		assert.Equal(t, expected, segments, path)
+	}
+	_, ok := restPath("/httpAuth/downloadBuildLog.html")
+	assert.False(t, ok)
+}
+
+func TestParseLocator(t *testing.T) {
+	locator := parseLocator("snapshotDependency:(to:(id:App_Build),recursive:false),count:1,branch:refs/heads/x")
+	assert.Equal(t, map[string]string{
+		"snapshotDependency": "to:(id:App_Build),recursive:false",
+		"count":              "1",
+		"branch":             "refs/heads/x",
+	}, locator)
+	assert.Equal(t, "App_Build", locatorID(parseLocator(locator["snapshotDependency"])["to"]))
+	assert.Equal(t, "App", locatorID("App"))
+}
+
+func TestProjects(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	app := createProject(t, client, "", "My App")
+	assert.Equal(t, "MyApp", app.ID)
+	assert.Equal(t, types.ProjectId(RootProjectID), app.ParentProjectID)
+	child := createProject(t, client, app.ID, "Child")
+	assert.Equal(t, "MyApp_Child", child.ID)
+
+	err := client.CreateProject(&types.Project{ID: "MyApp", Name: "Other"})
+	assert.EqualError(t, err, `Project ID "MyApp" is already used by another project.`)
+	err = client.CreateProject(&types.Project{Name: "Child", ParentProjectID: "MyApp"})
+	assert.Error(t, err)
+
+	parameter := types.Parameter{Value: "eu"}
+	require.NoError(t, client.ReplaceProjectParameter(app.ID, "region", &parameter))
+	require.NoError(t, client.SetProjectDescription(child.ID, "The child"))
+
+	project, err := client.GetProject(child.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "The child", project.Description)
+	assert.Equal(t, types.Parameter{Value: "eu", Inherited: true}, project.Parameters["region"])
+	assert.Empty(t, project.Parameters.Own())
+
+	project, err = client.GetProject(app.ID)
+	require.NoError(t, err)
+	assert.Contains(t, project.Projects, child.ID)
+
+	require.NoError(t, client.MoveProject(child.ID, RootProjectID))
+	assert.Error(t, client.MoveProject(app.ID, app.ID))
+
+	require.NoError(t, client.DeleteProject(app.ID))
+	project, err = client.GetProject(app.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, project)
+}
+
+func TestBuildConfigurations(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	app := createProject(t, client, "", "App")
+
+	template := &types.BuildConfiguration{
+		ProjectID:    app.ID,
+		TemplateFlag: true,
+		Name:         "Base",
+		Parameters:   types.Parameters{"env.GOFLAGS": {Value: "-mod=vendor"}},
+		Steps:        types.BuildSteps{{Type: "simpleRunner", Name: "Lint"}},
+	}
+	require.NoError(t, client.CreateBuildConfiguration(template))
+	assert.Equal(t, "App_Base", template.ID)
+	assert.Equal(t, "RUNNER_1", template.Steps[0].ID)
+
+	lib := &types.BuildConfiguration{ProjectID: app.ID, Name: "Lib"}
+	require.NoError(t, client.CreateBuildConfiguration(lib))
+
+	config := &types.BuildConfiguration{
+		ProjectID: app.ID,
+		Name:      "Service",
+		Templates: types.TemplateIds{"App_Base"},
+	}
+	require.NoError(t, client.CreateBuildConfiguration(config))
+
+	step := &types.BuildStep{Type: "simpleRunner", Name: "Build", Properties: types.Properties{"script.content": "make"}}
+	require.NoError(t, client.AddBuildConfigurationStep(config.ID, step))
+	assert.Equal(t, "RUNNER_2", step.ID)
+	dependency := &types.BuildSnapshotDependency{SourceBuildType: types.BuildType{ID: lib.ID}}
+	require.NoError(t, client.AddBuildConfigurationSnapshotDependency(config.ID, dependency))
+	assert.Equal(t, lib.ID, dependency.ID)
+	assert.Equal(t, "snapshot_dependency", dependency.Type)
+	require.NoError(t, client.ReplaceBuildConfigurationParameterValue(config.ID, "env.GOFLAGS", "-mod=mod"))
+	require.NoError(t, client.ReplaceBuildConfigurationSetting(config.ID, "artifactRules", "bin"))
+	require.NoError(t, client.SetBuildConfigurationPaused(config.ID, true))
+
+	got, err := client.GetBuildConfiguration(config.ID)
+	require.NoError(t, err)
+	assert.Equal(t, types.TemplateIds{"App_Base"}, got.Templates)
+	assert.Equal(t, []string{"RUNNER_1", "RUNNER_2"}, []string{got.Steps[0].ID, got.Steps[1].ID})
+	assert.True(t, got.Steps[0].Inherited)
+	assert.Equal(t, "-mod=mod", got.Parameters["env.GOFLAGS"].Value)
+	assert.Equal(t, types.BuildSettings{{Name: "artifactRules", Value: "bin"}}, got.Settings)
+	own := got.OwnSettings()
+	assert.Len(t, own.Steps, 1)
+
+	usages, err := client.GetTemplateUsages(template.ID)
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, config.ID, usages[0].ID)
+	assert.EqualError(t, client.DeleteBuildConfiguration(template.ID),
+		"Template 'App_Base' cannot be deleted because it is used by build configurations: [App_Service].")
+
+	report, err := client.PreviewProjectDeletion(app.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, report)
+
+	buildType, err := client.GetBuildType(config.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Service", buildType.Name)
+	assert.Equal(t, "App", buildType.ProjectName)
+
+	missing, err := client.GetBuildConfiguration("App_Missing")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestPreviewProjectDeletionTemplateUsages(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	app := createProject(t, client, "", "App")
+	other := createProject(t, client, "", "Other")
+	template := &types.BuildConfiguration{ProjectID: app.ID, TemplateFlag: true, Name: "Base"}
+	require.NoError(t, client.CreateBuildConfiguration(template))
+	inside := &types.BuildConfiguration{ProjectID: app.ID, Name: "Build", Templates: types.TemplateIds{"App_Base"}}
+	require.NoError(t, client.CreateBuildConfiguration(inside))
+	outside := &types.BuildConfiguration{ProjectID: other.ID, Name: "Build", Templates: types.TemplateIds{"App_Base"}}
+	require.NoError(t, client.CreateBuildConfiguration(outside))
+
+	report, err := client.DeleteProjectGuarded(app.ID)
+	require.Error(t, err)
+	assert.Equal(t, []string{"App_Base"}, report.Templates)
+	assert.Equal(t, []teamcity.ExternalTemplateUsage{{
+		TemplateID:           "App_Base",
+		BuildConfigurationID: "Other_Build",
+		ProjectID:            "Other",
+	}}, report.ExternalTemplateUsages)
+	assert.Contains(t, report.String(), "Other_Build (project Other) uses template App_Base")
+
+	require.NoError(t, client.DeleteBuildConfiguration(outside.ID))
+	_, err = client.DeleteProjectGuarded(app.ID)
+	require.NoError(t, err)
+}
+
+func TestSnapshotDependencyLocator(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	app := createProject(t, client, "", "App")
+	for _, name := range []string{"Lib", "Service", "Deploy"} {
+		require.NoError(t, client.CreateBuildConfiguration(&types.BuildConfiguration{ProjectID: app.ID, Name: name}))
+	}
+	require.NoError(t, client.AddBuildConfigurationSnapshotDependency("App_Service",
+		&types.BuildSnapshotDependency{SourceBuildType: types.BuildType{ID: "App_Lib"}}))
+	require.NoError(t, client.AddBuildConfigurationSnapshotDependency("App_Deploy",
+		&types.BuildSnapshotDependency{SourceBuildType: types.BuildType{ID: "App_Service"}}))
+
+	list := func(locator string) []string {
+		resp, err := http.Get(server.URL + "/app/rest/buildTypes?locator=" + url.QueryEscape(locator))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var buildTypes struct {
+			BuildType []struct {
+				ID string `json:"id"`
+			} `json:"buildType"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&buildTypes))
+		ids := []string{}
+		for _, buildType := range buildTypes.BuildType {
+			ids = append(ids, buildType.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"App_Lib"}, list("snapshotDependency:(to:(id:App_Service),recursive:false)"))
+	assert.Equal(t, []string{"App_Deploy"}, list("snapshotDependency:(from:(id:App_Service),recursive:false)"))
+	assert.Equal(t, []string{"App_Lib", "App_Service"}, list("snapshotDependency:(to:(id:App_Deploy))"))
+	assert.Equal(t, []string{"App_Deploy", "App_Service"}, list("snapshotDependency:(from:(id:App_Lib))"))
+}
+
+func TestDeleteProjectGuardedDependencyDirection(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	app := createProject(t, client, "", "App")
+	other := createProject(t, client, "", "Other")
+	require.NoError(t, client.CreateBuildConfiguration(&types.BuildConfiguration{ProjectID: app.ID, Name: "Build"}))
+	require.NoError(t, client.CreateBuildConfiguration(&types.BuildConfiguration{ProjectID: other.ID, Name: "Build"}))
+
+	// App_Build depending on a configuration outside does not block deleting App
+	require.NoError(t, client.AddBuildConfigurationSnapshotDependency("App_Build",
+		&types.BuildSnapshotDependency{SourceBuildType: types.BuildType{ID: "Other_Build"}}))
+	report, err := client.PreviewProjectDeletion(app.ID)
+	require.NoError(t, err)
+	assert.Empty(t, report.ExternalDependents)
+
+	// Other_Build depending on a configuration inside does
+	report, err = client.PreviewProjectDeletion(other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []teamcity.ExternalSnapshotDependency{{
+		BuildConfigurationID: "Other_Build",
+		DependentID:          "App_Build",
+		DependentProjectID:   "App",
+	}}, report.ExternalDependents)
+	_, err = client.DeleteProjectGuarded(other.ID)
+	assert.EqualError(t, err, "project Other not deleted: 1 build configurations outside the project depend on it")
+	project, err := client.GetProject(other.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, project)
+
+	_, err = client.DeleteProjectGuarded(app.ID)
+	require.NoError(t, err)
+}
+
+func TestCopyProject(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	app := createProject(t, client, "", "App")
+	template := &types.BuildConfiguration{ProjectID: app.ID, TemplateFlag: true, Name: "Base"}
+	require.NoError(t, client.CreateBuildConfiguration(template))
+	config := &types.BuildConfiguration{ProjectID: app.ID, Name: "Build", Templates: types.TemplateIds{"App_Base"}}
+	require.NoError(t, client.CreateBuildConfiguration(config))
+
+	copied, err := client.CopyProject(app.ID, RootProjectID, "Copy", "Copy", true)
+	require.NoError(t, err)
+	assert.Contains(t, copied.BuildConfigurations, "Copy_Build")
+	assert.Contains(t, copied.Templates, "Copy_Base")
+	templates, err := client.GetBuildConfigurationTemplates("Copy_Build")
+	require.NoError(t, err)
+	assert.Equal(t, types.TemplateIds{"Copy_Base"}, templates)
+
+	created, err := client.CreateTemplateFromBuildConfiguration(config.ID, app.ID, "Extracted", "")
+	require.NoError(t, err)
+	assert.Equal(t, "App_Extracted", created.ID)
+	assert.True(t, created.TemplateFlag)
+	assert.Empty(t, created.Templates)
+
+	token, err := client.CreateSecureToken(app.ID, "s3cret")
+	require.NoError(t, err)
+	value, ok := server.SecureValue(string(token))
+	assert.True(t, ok)
+	assert.Equal(t, "s3cret", value)
+}
+
+func TestVcsRoots(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	app := createProject(t, client, "", "App")
+	other := createProject(t, client, "", "Other")
+
+	root := &types.VcsRoot{
+		Name:       "app",
+		VcsName:    "jetbrains.git",
+		ProjectID:  types.ProjectId(app.ID),
+		Properties: types.Properties{"url": "https://example.com/app.git", "secure:password": "s3cret"},
+	}
+	require.NoError(t, client.CreateVcsRoot(root))
+	assert.Equal(t, "App_App", root.ID)
+	assert.Equal(t, "", root.Properties["secure:password"])
+
+	roots, err := client.GetProjectVcsRoots(app.ID)
+	require.NoError(t, err)
+	require.Len(t, roots, 1)
+	assert.Equal(t, root.ID, roots[0].ID)
+
+	config := &types.BuildConfiguration{ProjectID: app.ID, Name: "Build"}
+	require.NoError(t, client.CreateBuildConfiguration(config))
+	entry := &types.VcsRootEntry{VcsRootID: types.VcsRootId(root.ID), CheckoutRules: "+:src"}
+	require.NoError(t, client.AttachBuildConfigurationVcsRoot(config.ID, entry))
+	assert.Equal(t, root.ID, entry.ID)
+
+	foreign := &types.BuildConfiguration{ProjectID: other.ID, Name: "Build"}
+	require.NoError(t, client.CreateBuildConfiguration(foreign))
+	assert.Error(t, client.AttachBuildConfigurationVcsRoot(foreign.ID, &types.VcsRootEntry{VcsRootID: types.VcsRootId(root.ID)}))
+
+	usages, err := client.GetVcsRootUsages(root.ID)
+	require.NoError(t, err)
+	require.Len(t, usages, 1)
+	assert.Equal(t, config.ID, usages[0].ID)
+
+	require.NoError(t, client.DeleteVcsRoot(root.ID))
+	got, err := client.GetBuildConfiguration(config.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.VcsRootEntries)
+}
+
+func TestBuilds(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	app := createProject(t, client, "", "App")
+	config := &types.BuildConfiguration{
+		ProjectID:  app.ID,
+		Name:       "Build",
+		Parameters: types.Parameters{"env.MODE": {Value: "fast"}},
+	}
+	require.NoError(t, client.CreateBuildConfiguration(config))
+
+	first, err := client.QueueBuild(config.ID, "", types.Properties{"extra": "1"})
+	require.NoError(t, err)
+	second, err := client.QueueBuild(config.ID, "feature", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first.ID)
+	assert.Equal(t, "queued", first.State)
+	assert.Equal(t, "refs/heads/feature", second.BranchName)
+
+	queue, err := client.GetBuildQueue()
+	require.NoError(t, err)
+	require.Len(t, queue, 2)
+	assert.Equal(t, int64(2), queue[1].QueuePosition)
+
+	require.NoError(t, server.StartBuild(first.ID, "agent-1"))
+	assert.Error(t, server.StartBuild(first.ID, "agent-1"))
+	require.NoError(t, server.FinishBuild(first.ID, StatusSuccess, "Tests passed: 3"))
+
+	build, err := client.GetBuild(strconv.FormatInt(first.ID, 10))
+	require.NoError(t, err)
+	assert.Equal(t, types.Finished, build.ComputedState())
+	assert.Equal(t, "1", build.Number)
+	assert.Equal(t, "SUCCESS", build.Status)
+	assert.Equal(t, "agent-1", build.Agent.Name)
+	assert.Equal(t, config.ID, build.BuildType.ID)
+
+	id, err := client.GetBuildID(config.ID, "default:any", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "1", id)
+
+	properties, err := client.GetBuildProperties("1")
+	require.NoError(t, err)
+	assert.Equal(t, "fast", properties["env.MODE"])
+	assert.Equal(t, "1", properties["extra"])
+
+	canceled, err := client.CancelBuild(second.ID, "not needed")
+	require.NoError(t, err)
+	assert.Equal(t, "UNKNOWN", canceled.Status)
+
+	builds, err := client.SearchBuild("buildType:(id:App_Build),status:SUCCESS")
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	assert.Equal(t, first.ID, builds[0].ID)
+
+	all, err := client.GetBuilds()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, []int64{all[0].ID, all[1].ID})
+
+	missing, err := client.QueueBuild("App_Missing", "", nil)
+	require.NoError(t, err)
+	assert.Zero(t, missing.ID)
+}
+
+func TestAuthentication(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Username = "admin"
+	server.Password = "secret"
+
+	_, err := teamcity.New(server.URL, "admin", "wrong", "").Server()
+	assert.EqualError(t, err, "Authentication required")
+
+	info, err := server.Client().Server()
+	require.NoError(t, err)
+	assert.Equal(t, uint(2019), info.VersionMajor)
+}
+
+func TestErrorsArePlainText(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/app/rest/projects", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+}
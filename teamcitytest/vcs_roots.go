@@ -0,0 +1,340 @@
+package teamcitytest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/icelander/teamcity-sdk-go/types"
+)
+
+func (s *Server) findVcsRoot(locator string) (*types.VcsRoot, error) {
+	id := locatorID(locator)
+	root, ok := s.vcsRoots[id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, "No VCS root found by locator '%s'.", locator)
+	}
+	return root, nil
+}
+
+// vcsRootJSON returns the root with secure property values removed, as
+// TeamCity never returns them
+func (s *Server) vcsRootJSON(root *types.VcsRoot) types.VcsRoot {
+	out := *root
+	out.Href = "/app/rest/vcs-roots/id:" + root.ID
+	out.Properties = make(types.Properties)
+	for name, value := range root.Properties {
+		if strings.HasPrefix(name, "secure:") {
+			value = ""
+		}
+		out.Properties[name] = value
+	}
+	return out
+}
+
+func (s *Server) vcsRootIDTaken(id string) bool {
+	_, ok := s.vcsRoots[id]
+	return ok
+}
+
+func (s *Server) checkVcsRootName(projectID string, name string, except string) error {
+	if name == "" {
+		return badRequest("VCS root name cannot be empty.")
+	}
+	for _, root := range s.vcsRoots {
+		if string(root.ProjectID) == projectID && root.Name == name && root.ID != except {
+			return badRequest("VCS root with name \"%s\" already exists in project \"%s\".", name, projectID)
+		}
+	}
+	return nil
+}
+
+func (s *Server) routeVcsRoots(r *request, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listVcsRoots(r.locator()), nil
+		case http.MethodPost:
+			var root types.VcsRoot
+			if err := r.decode(&root); err != nil {
+				return nil, err
+			}
+			created, err := s.createVcsRoot(root)
+			if err != nil {
+				return nil, err
+			}
+			return s.vcsRootJSON(created), nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	root, err := s.findVcsRoot(path[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.vcsRootJSON(root), nil
+		case http.MethodDelete:
+			s.deleteVcsRoot(root.ID)
+			return nil, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	switch path[1] {
+	case "properties":
+		return routeVcsRootProperties(r, root, path[2:])
+	case "name", "projectId":
+		if len(path) == 2 {
+			return s.routeVcsRootField(r, root, path[1])
+		}
+	}
+	return nil, notFound(r)
+}
+
+func (s *Server) listVcsRoots(locator map[string]string) interface{} {
+	projectID := ""
+	if value, ok := locator["project"]; ok {
+		projectID = locatorID(value)
+	}
+	ids := make([]string, 0)
+	for id, root := range s.vcsRoots {
+		if projectID == "" || string(root.ProjectID) == projectID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	roots := make([]types.VcsRoot, 0, len(ids))
+	for _, id := range ids {
+		root := s.vcsRootJSON(s.vcsRoots[id])
+		root.Properties = nil
+		roots = append(roots, root)
+	}
+	return struct {
+		Count   int             `json:"count"`
+		Href    string          `json:"href"`
+		VcsRoot []types.VcsRoot `json:"vcs-root"`
+	}{len(roots), "/app/rest/vcs-roots", roots}
+}
+
+func (s *Server) createVcsRoot(root types.VcsRoot) (*types.VcsRoot, error) {
+	if root.ProjectID == "" {
+		return nil, badRequest("The project of the VCS root is not specified.")
+	}
+	project, err := s.findProject("id:" + string(root.ProjectID))
+	if err != nil {
+		return nil, err
+	}
+	if root.VcsName == "" {
+		return nil, badRequest("The VCS root type (vcsName) is not specified.")
+	}
+	if err := s.checkVcsRootName(project.ID, root.Name, ""); err != nil {
+		return nil, err
+	}
+	if root.ID == "" {
+		root.ID = generateID(project.ID, root.Name, s.vcsRootIDTaken)
+	} else {
+		if err := checkID("VCS root", root.ID); err != nil {
+			return nil, err
+		}
+		if s.vcsRootIDTaken(root.ID) {
+			return nil, badRequest("VCS root ID \"%s\" is already used by another VCS root.", root.ID)
+		}
+	}
+	if root.Properties == nil {
+		root.Properties = make(types.Properties)
+	}
+	root.Href = ""
+	s.vcsRoots[root.ID] = &root
+	return &root, nil
+}
+
+// deleteVcsRoot removes the root and detaches it from all build
+// configurations and templates
+func (s *Server) deleteVcsRoot(id string) {
+	delete(s.vcsRoots, id)
+	for _, bt := range s.buildTypes {
+		kept := make(types.VcsRootEntries, 0)
+		for _, entry := range bt.config.VcsRootEntries {
+			if string(entry.VcsRootID) != id {
+				kept = append(kept, entry)
+			}
+		}
+		bt.config.VcsRootEntries = kept
+	}
+}
+
+func routeVcsRootProperties(r *request, root *types.VcsRoot, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return root.Properties, nil
+		case http.MethodPut:
+			var properties types.Properties
+			if err := r.decode(&properties); err != nil {
+				return nil, err
+			}
+			root.Properties = properties
+			return properties, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+	if len(path) != 1 {
+		return nil, notFound(r)
+	}
+
+	name := path[0]
+	switch r.Method {
+	case http.MethodGet:
+		value, ok := root.Properties[name]
+		if !ok {
+			return nil, errorf(http.StatusNotFound, "No property with name '%s' is found.", name)
+		}
+		return text(value), nil
+	case http.MethodPut:
+		root.Properties[name] = string(r.body)
+		return text(r.body), nil
+	case http.MethodDelete:
+		delete(root.Properties, name)
+		return nil, nil
+	}
+	return nil, methodNotAllowed(r)
+}
+
+func (s *Server) routeVcsRootField(r *request, root *types.VcsRoot, field string) (interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		if field == "name" {
+			return text(root.Name), nil
+		}
+		return text(root.ProjectID), nil
+	case http.MethodPut:
+		value := string(r.body)
+		if field == "name" {
+			if err := s.checkVcsRootName(string(root.ProjectID), value, root.ID); err != nil {
+				return nil, err
+			}
+			root.Name = value
+			return text(value), nil
+		}
+		project, err := s.findProject("id:" + value)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkVcsRootName(project.ID, root.Name, root.ID); err != nil {
+			return nil, err
+		}
+		root.ProjectID = types.ProjectId(project.ID)
+		return text(value), nil
+	}
+	return nil, methodNotAllowed(r)
+}
+
+// attachVcsRoot adds a VCS root entry to the build configuration. The root
+// has to belong to its project or one of the parent projects.
+func (s *Server) attachVcsRoot(bt *buildType, entry types.VcsRootEntry) (types.VcsRootEntry, error) {
+	id := string(entry.VcsRootID)
+	if id == "" {
+		id = entry.ID
+	}
+	root, err := s.findVcsRoot("id:" + id)
+	if err != nil {
+		return entry, err
+	}
+	accessible := false
+	if project, ok := s.projects[bt.config.ProjectID]; ok {
+		for _, p := range append(s.ancestors(project), project) {
+			accessible = accessible || p.ID == string(root.ProjectID)
+		}
+	}
+	if !accessible {
+		return entry, badRequest("VCS root '%s' of project '%s' is not accessible from project '%s'.", root.ID, root.ProjectID, bt.config.ProjectID)
+	}
+	for _, existing := range bt.config.VcsRootEntries {
+		if string(existing.VcsRootID) == root.ID {
+			return entry, badRequest("VCS root '%s' is already attached to %s.", root.ID, s.describe(bt))
+		}
+	}
+	entry.ID = root.ID
+	entry.VcsRootID = types.VcsRootId(root.ID)
+	bt.config.VcsRootEntries = append(bt.config.VcsRootEntries, entry)
+	return entry, nil
+}
+
+func (s *Server) routeVcsRootEntries(r *request, bt *buildType, path []string) (interface{}, error) {
+	list := func() interface{} {
+		entries := bt.config.VcsRootEntries
+		if entries == nil {
+			entries = make(types.VcsRootEntries, 0)
+		}
+		return struct {
+			Count        int                  `json:"count"`
+			VcsRootEntry []types.VcsRootEntry `json:"vcs-root-entry"`
+		}{len(entries), entries}
+	}
+
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return list(), nil
+		case http.MethodPost:
+			var entry types.VcsRootEntry
+			if err := r.decode(&entry); err != nil {
+				return nil, err
+			}
+			return s.attachVcsRoot(bt, entry)
+		case http.MethodPut:
+			var entries types.VcsRootEntries
+			if err := r.decode(&entries); err != nil {
+				return nil, err
+			}
+			previous := bt.config.VcsRootEntries
+			bt.config.VcsRootEntries = nil
+			for _, entry := range entries {
+				if _, err := s.attachVcsRoot(bt, entry); err != nil {
+					bt.config.VcsRootEntries = previous
+					return nil, err
+				}
+			}
+			return list(), nil
+		case http.MethodDelete:
+			bt.config.VcsRootEntries = nil
+			return nil, nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+
+	index := -1
+	for i, entry := range bt.config.VcsRootEntries {
+		if entry.ID == path[0] {
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, errorf(http.StatusNotFound, "No VCS root entry with id '%s' is found in %s.", path[0], s.describe(bt))
+	}
+	if len(path) == 2 && path[1] == "checkout-rules" {
+		switch r.Method {
+		case http.MethodGet:
+			return text(bt.config.VcsRootEntries[index].CheckoutRules), nil
+		case http.MethodPut:
+			bt.config.VcsRootEntries[index].CheckoutRules = string(r.body)
+			return text(r.body), nil
+		}
+		return nil, methodNotAllowed(r)
+	}
+	if len(path) != 1 {
+		return nil, notFound(r)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		return bt.config.VcsRootEntries[index], nil
+	case http.MethodDelete:
+		entries := bt.config.VcsRootEntries
+		bt.config.VcsRootEntries = append(entries[:index:index], entries[index+1:]...)
+		return nil, nil
+	}
+	return nil, methodNotAllowed(r)
+}
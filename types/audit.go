@@ -0,0 +1,152 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuditEvent is an entry of the audit log: an action a user or the server
+// performed and the entities it affected
+type AuditEvent struct {
+	ID        string        `json:"id"`
+	Timestamp JSONTime      `json:"timestamp"`
+	Action    AuditAction   `json:"action"`
+	Comment   AuditComment  `json:"comment,omitempty"`
+	User      *AuditUser    `json:"user,omitempty"`
+	Entities  AuditEntities `json:"relatedEntities,omitempty"`
+}
+
+// AuditAction identifies what was done, e.g. build_type_edit_settings
+type AuditAction struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// AuditUser is the user who performed an action. It is nil for actions of
+// the server itself.
+type AuditUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name,omitempty"`
+}
+
+// AuditComment is the description TeamCity records with an action
+type AuditComment string
+
+func (c AuditComment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Text string `json:"text"`
+	}{string(c)})
+}
+
+func (c *AuditComment) UnmarshalJSON(b []byte) error {
+	var comment struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(b, &comment); err != nil {
+		return err
+	}
+	*c = AuditComment(comment.Text)
+	return nil
+}
+
+// AuditEntity is an entity affected by an audited action. Type tells which of
+// the references is set.
+type AuditEntity struct {
+	Type      string                   `json:"type"`
+	Text      string                   `json:"text,omitempty"`
+	Project   *ProjectShort            `json:"project,omitempty"`
+	BuildType *BuildConfigurationShort `json:"buildType,omitempty"`
+	VcsRoot   *VcsRootShort            `json:"vcsRoot,omitempty"`
+	Agent     *Agent                   `json:"agent,omitempty"`
+}
+
+type AuditEntities []AuditEntity
+
+type auditEntitiesJSON struct {
+	Count  int           `json:"count"`
+	Entity []AuditEntity `json:"entity"`
+}
+
+func (e AuditEntities) MarshalJSON() ([]byte, error) {
+	entities := auditEntitiesJSON{Count: len(e), Entity: make([]AuditEntity, 0, len(e))}
+	entities.Entity = append(entities.Entity, e...)
+	return json.Marshal(entities)
+}
+
+func (e *AuditEntities) UnmarshalJSON(b []byte) error {
+	var entities auditEntitiesJSON
+	if err := json.Unmarshal(b, &entities); err != nil {
+		return err
+	}
+	*e = AuditEntities(entities.Entity)
+	return nil
+}
+
+// BuildConfigurationIDs returns the IDs of the affected build configurations
+// and templates
+func (e AuditEvent) BuildConfigurationIDs() []string {
+	var ids []string
+	for _, entity := range e.Entities {
+		if entity.BuildType != nil {
+			ids = append(ids, entity.BuildType.ID)
+		}
+	}
+	return ids
+}
+
+// ProjectIDs returns the IDs of the affected projects
+func (e AuditEvent) ProjectIDs() []string {
+	var ids []string
+	for _, entity := range e.Entities {
+		if entity.Project != nil {
+			ids = append(ids, entity.Project.ID)
+		}
+	}
+	return ids
+}
+
+// AuditFilter selects audit events. Empty fields do not filter.
+type AuditFilter struct {
+	// ProjectID selects the events affecting the project or anything in it
+	ProjectID            string
+	BuildConfigurationID string
+	Username             string
+	// Action is an action name such as build_type_edit_settings
+	Action string
+	// Since and Until bound the event timestamps, both inclusive
+	Since time.Time
+	Until time.Time
+	// Count limits the number of events returned, 0 means no limit
+	Count int
+}
+
+// Locator returns the audit locator for the filter. The time range is not
+// part of it and has to be checked with InRange.
+func (f AuditFilter) Locator() string {
+	var dimensions []string
+	if f.ProjectID != "" {
+		dimensions = append(dimensions, fmt.Sprintf("affectedProject:(id:%s)", f.ProjectID))
+	}
+	if f.BuildConfigurationID != "" {
+		dimensions = append(dimensions, fmt.Sprintf("buildType:(id:%s)", f.BuildConfigurationID))
+	}
+	if f.Username != "" {
+		dimensions = append(dimensions, fmt.Sprintf("user:(username:%s)", f.Username))
+	}
+	if f.Action != "" {
+		dimensions = append(dimensions, fmt.Sprintf("action:%s", f.Action))
+	}
+	return strings.Join(dimensions, ",")
+}
+
+// InRange tells whether the event happened between Since and Until
+func (f AuditFilter) InRange(event AuditEvent) bool {
+	timestamp := event.Timestamp.Time()
+	if !f.Since.IsZero() && timestamp.Before(f.Since) {
+		return false
+	}
+	return f.Until.IsZero() || !timestamp.After(f.Until)
+}
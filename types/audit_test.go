@@ -0,0 +1,46 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditFilterLocator(t *testing.T) {
+	assert.Equal(t, "", AuditFilter{}.Locator())
+	assert.Equal(t, "affectedProject:(id:Empty),buildType:(id:Empty_Build),user:(username:admin),action:build_type_edit_settings",
+		AuditFilter{
+			ProjectID:            "Empty",
+			BuildConfigurationID: "Empty_Build",
+			Username:             "admin",
+			Action:               "build_type_edit_settings",
+			Since:                time.Now(),
+		}.Locator())
+}
+
+func TestAuditFilterInRange(t *testing.T) {
+	event := AuditEvent{Timestamp: "20191212T101500+0000"}
+	at := event.Timestamp.Time()
+
+	assert.True(t, AuditFilter{}.InRange(event))
+	assert.True(t, AuditFilter{Since: at, Until: at}.InRange(event))
+	assert.False(t, AuditFilter{Since: at.Add(time.Second)}.InRange(event))
+	assert.False(t, AuditFilter{Until: at.Add(-time.Second)}.InRange(event))
+}
+
+func TestAuditEventEntities(t *testing.T) {
+	var event AuditEvent
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"7","relatedEntities":{"count":3,"entity":[
+		{"type":"project","project":{"id":"Empty","name":"Empty"}},
+		{"type":"buildType","buildType":{"id":"Empty_Build"}},
+		{"type":"buildType","buildType":{"id":"Empty_Tempy","templateFlag":true}}
+	]}}`), &event))
+
+	assert.Equal(t, []string{"Empty"}, event.ProjectIDs())
+	assert.Equal(t, []string{"Empty_Build", "Empty_Tempy"}, event.BuildConfigurationIDs())
+	assert.True(t, HealthError.AtLeast(HealthWarning))
+	assert.False(t, HealthInfo.AtLeast(HealthWarning))
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BackupOptions selects what a server backup includes
+type BackupOptions struct {
+	// FileName is the name of the backup file in the backup directory
+	FileName string
+	// AddTimestamp appends the start time to FileName
+	AddTimestamp           bool
+	IncludeConfigs         bool
+	IncludeDatabase        bool
+	IncludeBuildLogs       bool
+	IncludePersonalChanges bool
+	IncludeRunningBuilds   bool
+	// IncludeSupplementaryData includes the data of plugins
+	IncludeSupplementaryData bool
+}
+
+// Query returns the query parameters starting a backup with the options
+func (o BackupOptions) Query() url.Values {
+	query := url.Values{}
+	query.Set("fileName", o.FileName)
+	query.Set("addTimestamp", strconv.FormatBool(o.AddTimestamp))
+	query.Set("includeConfigs", strconv.FormatBool(o.IncludeConfigs))
+	query.Set("includeDatabase", strconv.FormatBool(o.IncludeDatabase))
+	query.Set("includeBuildLogs", strconv.FormatBool(o.IncludeBuildLogs))
+	query.Set("includePersonalChanges", strconv.FormatBool(o.IncludePersonalChanges))
+	query.Set("includeRunningBuilds", strconv.FormatBool(o.IncludeRunningBuilds))
+	// sic, the parameter is misspelled by TeamCity
+	query.Set("includeSupplimentaryData", strconv.FormatBool(o.IncludeSupplementaryData))
+	return query
+}
+
+// BackupStatus is the status of the current or last server backup as
+// reported by TeamCity, e.g. Idle or Running
+type BackupStatus string
+
+const BackupIdle BackupStatus = "Idle"
+
+// InProgress tells whether a backup is starting or running
+func (s BackupStatus) InProgress() bool {
+	status := strings.ToLower(string(s))
+	return status == "starting" || status == "running"
+}
@@ -0,0 +1,159 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultBranchPattern matches the default branch of the VCS root
+const DefaultBranchPattern = "<default>"
+
+// BranchSpecRule is one line of a branch specification such as
+// "+:refs/heads/*" or "-:<default>". A pattern may contain a single "*"
+// wildcard and a single parenthesised group marking the logical branch name,
+// e.g. "+:refs/heads/(feature-*)".
+type BranchSpecRule struct {
+	Exclude bool
+	Pattern string
+}
+
+// BranchSpec as found in the teamcity:branchSpec VCS root property
+type BranchSpec []BranchSpecRule
+
+// ParseBranchSpec parses a newline separated branch specification. Lines
+// without a "+:" or "-:" prefix are include rules.
+func ParseBranchSpec(s string) (BranchSpec, error) {
+	spec := make(BranchSpec, 0)
+	for n, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		rule := BranchSpecRule{}
+		if strings.HasPrefix(line, "-:") {
+			rule.Exclude = true
+			line = line[2:]
+		} else if strings.HasPrefix(line, "+:") {
+			line = line[2:]
+		}
+		rule.Pattern = strings.TrimSpace(line)
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("branch specification line %d: %s", n+1, err)
+		}
+		spec = append(spec, rule)
+	}
+	return spec, nil
+}
+
+func (r BranchSpecRule) validate() error {
+	if r.Pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	if strings.Count(r.Pattern, "*") > 1 {
+		return fmt.Errorf("pattern %q has more than one *", r.Pattern)
+	}
+	opens, closes := strings.Count(r.Pattern, "("), strings.Count(r.Pattern, ")")
+	if opens > 1 || closes > 1 || opens != closes {
+		return fmt.Errorf("pattern %q must have at most one () group", r.Pattern)
+	}
+	if opens == 1 && strings.Index(r.Pattern, "(") > strings.Index(r.Pattern, ")") {
+		return fmt.Errorf("pattern %q has an unbalanced () group", r.Pattern)
+	}
+	return nil
+}
+
+func (r BranchSpecRule) String() string {
+	if r.Exclude {
+		return "-:" + r.Pattern
+	}
+	return "+:" + r.Pattern
+}
+
+func (b BranchSpec) String() string {
+	lines := make([]string, len(b))
+	for idx, rule := range b {
+		lines[idx] = rule.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// match returns the logical branch name for ref, if the pattern matches it
+func (r BranchSpecRule) match(ref, defaultBranch string) (string, bool) {
+	if r.Pattern == DefaultBranchPattern {
+		if ref != defaultBranch {
+			return "", false
+		}
+		return strings.TrimPrefix(ref, "refs/heads/"), true
+	}
+
+	pattern := strings.Replace(strings.Replace(r.Pattern, "(", "", 1), ")", "", 1)
+	groupStart := strings.Index(r.Pattern, "(")
+	groupEnd := strings.Index(r.Pattern, ")") - 1
+	star := strings.Index(pattern, "*")
+
+	if star < 0 {
+		if ref != pattern {
+			return "", false
+		}
+		if groupStart >= 0 {
+			return ref[groupStart:groupEnd], true
+		}
+		return ref, true
+	}
+
+	prefix, suffix := pattern[:star], pattern[star+1:]
+	if len(ref) < len(prefix)+len(suffix) || !strings.HasPrefix(ref, prefix) || !strings.HasSuffix(ref, suffix) {
+		return "", false
+	}
+	if groupStart < 0 {
+		return ref[star : len(ref)-len(suffix)], true
+	}
+
+	// Translate group bounds in the pattern into bounds in the ref, which is
+	// longer than the pattern by the text the * stands for.
+	stretch := len(ref) - len(pattern) + 1
+	start, end := groupStart, groupEnd
+	if start > star {
+		start += stretch - 1
+	}
+	if end > star {
+		end += stretch - 1
+	}
+	return ref[start:end], true
+}
+
+// specificity ranks matching rules. <default> names one branch explicitly, so
+// it beats any pattern.
+func (r BranchSpecRule) specificity() int {
+	if r.Pattern == DefaultBranchPattern {
+		return int(^uint(0) >> 1)
+	}
+	return len(strings.Replace(r.Pattern, "*", "", -1))
+}
+
+// Match tells whether the ref of a branch is monitored by the specification
+// and returns its logical name, as shown in the TeamCity UI. defaultBranch is
+// the full ref of the VCS root's default branch, which is monitored unless a
+// "-:<default>" line excludes it.
+// When several lines match, the most specific pattern wins, with later lines
+// winning ties.
+func (b BranchSpec) Match(ref, defaultBranch string) (string, bool) {
+	var best *BranchSpecRule
+	bestName := ""
+	for idx := range b {
+		rule := &b[idx]
+		name, ok := rule.match(ref, defaultBranch)
+		if ok && (best == nil || rule.specificity() >= best.specificity()) {
+			best = rule
+			bestName = name
+		}
+	}
+
+	if best == nil || best.Exclude {
+		if ref == defaultBranch && (best == nil || best.Pattern != DefaultBranchPattern) {
+			return strings.TrimPrefix(ref, "refs/heads/"), true
+		}
+		return "", false
+	}
+	return bestName, true
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBranchSpec(t *testing.T) {
+	spec, err := ParseBranchSpec("+:refs/heads/*\n-:<default>\nrefs/tags/(v*)")
+	require.NoError(t, err)
+	assert.Equal(t, BranchSpec{
+		{Pattern: "refs/heads/*"},
+		{Exclude: true, Pattern: "<default>"},
+		{Pattern: "refs/tags/(v*)"},
+	}, spec)
+	assert.Equal(t, "+:refs/heads/*\n-:<default>\n+:refs/tags/(v*)", spec.String())
+
+	_, err = ParseBranchSpec("+:refs/*/*")
+	assert.EqualError(t, err, `branch specification line 1: pattern "refs/*/*" has more than one *`)
+	_, err = ParseBranchSpec("+:refs/heads/(a)(b)")
+	assert.Error(t, err)
+}
+
+func TestBranchSpecMatch(t *testing.T) {
+	spec, err := ParseBranchSpec("+:refs/heads/*\n+:refs/heads/(feature-*)\n-:refs/heads/wip/*\n+:refs/(pull/*)/head\n+:refs/tags/v(*)-release")
+	require.NoError(t, err)
+
+	cases := []struct {
+		ref  string
+		name string
+		ok   bool
+	}{
+		{"refs/heads/master", "master", true},
+		{"refs/heads/develop", "develop", true},
+		{"refs/heads/feature-login", "feature-login", true},
+		{"refs/heads/wip/tmp", "", false},
+		{"refs/pull/42/head", "pull/42", true},
+		{"refs/tags/v1.2-release", "1.2", true},
+		{"refs/tags/v1.2", "", false},
+	}
+	for _, c := range cases {
+		name, ok := spec.Match(c.ref, "refs/heads/master")
+		assert.Equal(t, c.ok, ok, c.ref)
+		assert.Equal(t, c.name, name, c.ref)
+	}
+}
+
+func TestBranchSpecDefaultBranch(t *testing.T) {
+	spec, err := ParseBranchSpec("+:refs/heads/release-*")
+	require.NoError(t, err)
+	name, ok := spec.Match("refs/heads/master", "refs/heads/master")
+	assert.True(t, ok, "Expected default branch to always be monitored")
+	assert.Equal(t, "master", name)
+
+	spec, err = ParseBranchSpec("+:refs/heads/*\n-:<default>")
+	require.NoError(t, err)
+	_, ok = spec.Match("refs/heads/master", "refs/heads/master")
+	assert.False(t, ok, "Expected default branch to be excluded")
+	name, ok = spec.Match("refs/heads/other", "refs/heads/master")
+	assert.True(t, ok)
+	assert.Equal(t, "other", name)
+}
@@ -8,6 +8,17 @@ type BuildAgentRequirement struct {
 	ID         string     `json:"id"`
 	Type       string     `json:"type"`
 	Properties Properties `json:"properties"`
+	Disabled   bool       `json:"disabled,omitempty"`
+	Inherited  bool       `json:"inherited,omitempty"`
+}
+
+// MarshalJSON leaves out Inherited, which only the server sets
+func (r BuildAgentRequirement) MarshalJSON() ([]byte, error) {
+	type buildAgentRequirement BuildAgentRequirement
+	return json.Marshal(struct {
+		buildAgentRequirement
+		Inherited bool `json:"inherited,omitempty"`
+	}{buildAgentRequirement: buildAgentRequirement(r)})
 }
 
 type BuildAgentRequirements []BuildAgentRequirement
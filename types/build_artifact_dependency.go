@@ -9,6 +9,17 @@ type BuildArtifactDependency struct {
 	Type            string     `json:"type"`
 	Properties      Properties `json:"properties"`
 	SourceBuildType BuildType  `json:"source-buildType"`
+	Disabled        bool       `json:"disabled,omitempty"`
+	Inherited       bool       `json:"inherited,omitempty"`
+}
+
+// MarshalJSON leaves out Inherited, which only the server sets
+func (d BuildArtifactDependency) MarshalJSON() ([]byte, error) {
+	type buildArtifactDependency BuildArtifactDependency
+	return json.Marshal(struct {
+		buildArtifactDependency
+		Inherited bool `json:"inherited,omitempty"`
+	}{buildArtifactDependency: buildArtifactDependency(d)})
 }
 
 type BuildArtifactDependencies []BuildArtifactDependency
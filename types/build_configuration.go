@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"sort"
 )
 
 type BuildConfiguration struct {
@@ -9,6 +10,7 @@ type BuildConfiguration struct {
 	ProjectID            string                    `json:"projectId"`
 	TemplateFlag         bool                      `json:"templateFlag"`
 	TemplateID           TemplateId                `json:"template,omitempty"`
+	Templates            TemplateIds               `json:"templates,omitempty"`
 	Name                 string                    `json:"name"`
 	Description          string                    `json:"description,omitempty"`
 	VcsRootEntries       VcsRootEntries            `json:"vcs-root-entries,omitempty"`
@@ -24,6 +26,18 @@ type BuildConfiguration struct {
 
 type TemplateId string
 
+// AttachedTemplates returns the templates of the build configuration whether
+// the server reports them in the single or the multiple template form
+func (b BuildConfiguration) AttachedTemplates() TemplateIds {
+	if len(b.Templates) > 0 {
+		return b.Templates
+	}
+	if b.TemplateID != "" {
+		return TemplateIds{b.TemplateID}
+	}
+	return TemplateIds{}
+}
+
 type BuildConfigurationShort struct {
 	ID           string `json:"id"`
 	ProjectID    string `json:"projectId,omitempty"`
@@ -66,8 +80,8 @@ func (bc BuildConfigurations) MarshalJSON() ([]byte, error) {
 	bci := &buildConfigurationsInput{
 		BuildType: make([]BuildConfiguration, 0),
 	}
-	for _, value := range bc {
-		bci.BuildType = append(bci.BuildType, value)
+	for _, id := range sortedBuildConfigurationIDs(bc) {
+		bci.BuildType = append(bci.BuildType, bc[id])
 	}
 	return json.Marshal(bci)
 }
@@ -84,3 +98,12 @@ func (bc *BuildConfigurations) UnmarshalJSON(b []byte) error {
 	*bc = m
 	return nil
 }
+
+func sortedBuildConfigurationIDs(bc BuildConfigurations) []string {
+	ids := make([]string, 0, len(bc))
+	for id := range bc {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,111 @@
+package types
+
+// OwnSettings returns a copy of the build configuration holding only what is
+// defined on the configuration itself. Steps, triggers, features, agent
+// requirements, dependencies and parameters inherited from templates are
+// left out, so the result is safe to write back to the server.
+func (b BuildConfiguration) OwnSettings() BuildConfiguration {
+	own := b
+	own.Parameters = b.Parameters.Own()
+	own.Steps, _ = b.Steps.split()
+	own.Features, _ = b.Features.split()
+	own.Triggers, _ = b.Triggers.split()
+	own.SnapshotDependencies, _ = b.SnapshotDependencies.split()
+	own.ArtifactDependencies, _ = b.ArtifactDependencies.split()
+	own.AgentRequirements, _ = b.AgentRequirements.split()
+	return own
+}
+
+// InheritedSettings returns the steps, triggers, features, agent
+// requirements, dependencies and parameters the build configuration inherits
+// from its templates. Identity fields are kept, everything else is cleared.
+func (b BuildConfiguration) InheritedSettings() BuildConfiguration {
+	inherited := BuildConfiguration{
+		ID:           b.ID,
+		ProjectID:    b.ProjectID,
+		TemplateFlag: b.TemplateFlag,
+		TemplateID:   b.TemplateID,
+		Templates:    b.Templates,
+		Name:         b.Name,
+	}
+	_, inherited.Parameters = b.Parameters.split()
+	_, inherited.Steps = b.Steps.split()
+	_, inherited.Features = b.Features.split()
+	_, inherited.Triggers = b.Triggers.split()
+	_, inherited.SnapshotDependencies = b.SnapshotDependencies.split()
+	_, inherited.ArtifactDependencies = b.ArtifactDependencies.split()
+	_, inherited.AgentRequirements = b.AgentRequirements.split()
+	return inherited
+}
+
+func (bs BuildSteps) split() (own BuildSteps, inherited BuildSteps) {
+	own, inherited = make(BuildSteps, 0), make(BuildSteps, 0)
+	for _, step := range bs {
+		if step.Inherited {
+			inherited = append(inherited, step)
+		} else {
+			own = append(own, step)
+		}
+	}
+	return
+}
+
+func (bf BuildFeatures) split() (own BuildFeatures, inherited BuildFeatures) {
+	own, inherited = make(BuildFeatures, 0), make(BuildFeatures, 0)
+	for _, feature := range bf {
+		if feature.Inherited {
+			inherited = append(inherited, feature)
+		} else {
+			own = append(own, feature)
+		}
+	}
+	return
+}
+
+func (bt BuildTriggers) split() (own BuildTriggers, inherited BuildTriggers) {
+	own, inherited = make(BuildTriggers, 0), make(BuildTriggers, 0)
+	for _, trigger := range bt {
+		if trigger.Inherited {
+			inherited = append(inherited, trigger)
+		} else {
+			own = append(own, trigger)
+		}
+	}
+	return
+}
+
+func (bsd BuildSnapshotDependencies) split() (own BuildSnapshotDependencies, inherited BuildSnapshotDependencies) {
+	own, inherited = make(BuildSnapshotDependencies, 0), make(BuildSnapshotDependencies, 0)
+	for _, dependency := range bsd {
+		if dependency.Inherited {
+			inherited = append(inherited, dependency)
+		} else {
+			own = append(own, dependency)
+		}
+	}
+	return
+}
+
+func (bsd BuildArtifactDependencies) split() (own BuildArtifactDependencies, inherited BuildArtifactDependencies) {
+	own, inherited = make(BuildArtifactDependencies, 0), make(BuildArtifactDependencies, 0)
+	for _, dependency := range bsd {
+		if dependency.Inherited {
+			inherited = append(inherited, dependency)
+		} else {
+			own = append(own, dependency)
+		}
+	}
+	return
+}
+
+func (bf BuildAgentRequirements) split() (own BuildAgentRequirements, inherited BuildAgentRequirements) {
+	own, inherited = make(BuildAgentRequirements, 0), make(BuildAgentRequirements, 0)
+	for _, requirement := range bf {
+		if requirement.Inherited {
+			inherited = append(inherited, requirement)
+		} else {
+			own = append(own, requirement)
+		}
+	}
+	return
+}
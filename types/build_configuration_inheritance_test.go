@@ -0,0 +1,82 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var inheritedBuildConfiguration = []byte(`{
+	"id": "Single_Child",
+	"projectId": "Single",
+	"name": "Child",
+	"templates": {"count": 1, "buildType": [{"id": "Single_Tempy"}]},
+	"parameters": {"property": [
+		{"name": "env.OWN", "value": "mine"},
+		{"name": "env.FROM_TEMPLATE", "value": "theirs", "inherited": true}
+	]},
+	"steps": {"step": [
+		{"id": "RUNNER_1", "name": "Template step", "type": "simpleRunner", "inherited": true},
+		{"id": "RUNNER_2", "name": "Own step", "type": "simpleRunner", "disabled": true}
+	]},
+	"triggers": {"trigger": [
+		{"id": "TRIGGER_1", "type": "vcsTrigger", "inherited": true, "disabled": true}
+	]}
+}`)
+
+func TestBuildConfigurationInheritedFlags(t *testing.T) {
+	var config BuildConfiguration
+	err := json.Unmarshal(inheritedBuildConfiguration, &config)
+	require.NoError(t, err)
+
+	assert.True(t, config.Steps[0].Inherited)
+	assert.False(t, config.Steps[0].Disabled)
+	assert.False(t, config.Steps[1].Inherited)
+	assert.True(t, config.Steps[1].Disabled)
+	assert.True(t, config.Triggers[0].Inherited)
+	assert.True(t, config.Triggers[0].Disabled)
+	assert.True(t, config.Parameters["env.FROM_TEMPLATE"].Inherited)
+	assert.False(t, config.Parameters["env.OWN"].Inherited)
+}
+
+func TestBuildConfigurationOwnSettings(t *testing.T) {
+	var config BuildConfiguration
+	err := json.Unmarshal(inheritedBuildConfiguration, &config)
+	require.NoError(t, err)
+
+	own := config.OwnSettings()
+	assert.Equal(t, "Single_Child", own.ID)
+	assert.Equal(t, Parameters{"env.OWN": Parameter{Value: "mine"}}, own.Parameters)
+	require.Equal(t, 1, len(own.Steps))
+	assert.Equal(t, "RUNNER_2", own.Steps[0].ID)
+	assert.Equal(t, 0, len(own.Triggers))
+
+	inherited := config.InheritedSettings()
+	assert.Equal(t, TemplateIds{"Single_Tempy"}, inherited.Templates)
+	assert.Equal(t, Parameters{"env.FROM_TEMPLATE": Parameter{Value: "theirs", Inherited: true}}, inherited.Parameters)
+	require.Equal(t, 1, len(inherited.Steps))
+	assert.Equal(t, "RUNNER_1", inherited.Steps[0].ID)
+	require.Equal(t, 1, len(inherited.Triggers))
+	assert.Equal(t, "TRIGGER_1", inherited.Triggers[0].ID)
+}
+
+func TestBuildConfigurationInheritedNotWritten(t *testing.T) {
+	var config BuildConfiguration
+	err := json.Unmarshal(inheritedBuildConfiguration, &config)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "inherited")
+
+	data, err = json.Marshal(config.Steps[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "inherited")
+	assert.Contains(t, string(data), `"id":"RUNNER_1"`)
+
+	var step BuildStep
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"RUNNER_1","inherited":true}`), &step))
+	assert.True(t, step.Inherited)
+}
@@ -30,3 +30,44 @@ func TestTemplateIdWriting(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "{\"id\":\"Tempy\"}", string(b))
 }
+
+func TestTemplateIdsParsing(t *testing.T) {
+	var v TemplateIds
+
+	err := json.Unmarshal([]byte(`{"count":0}`), &v)
+	assert.NoError(t, err)
+	assert.Equal(t, TemplateIds{}, v)
+
+	err = json.Unmarshal([]byte(`{"count":2,"buildType":[{"id":"Tempy","name":"Tempy"},{"id":"Other"}]}`), &v)
+	assert.NoError(t, err)
+	assert.Equal(t, TemplateIds{"Tempy", "Other"}, v)
+}
+
+func TestTemplateIdsWriting(t *testing.T) {
+	v := TemplateIds{"Tempy", "Other"}
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"buildType":[{"id":"Tempy"},{"id":"Other"}]}`, string(b))
+}
+
+func TestBuildConfigurationsAndProjectsSortedMarshal(t *testing.T) {
+	bytes, err := json.Marshal(BuildConfigurations{
+		"B": BuildConfiguration{ID: "B", Name: "b"},
+		"A": BuildConfiguration{ID: "A", Name: "a"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"BuildType":[{"id":"A","projectId":"","templateFlag":false,"name":"a"},{"id":"B","projectId":"","templateFlag":false,"name":"b"}]}`, string(bytes))
+
+	bytes, err = json.Marshal(Projects{
+		"Y": Project{ID: "Y", Name: "y"},
+		"X": Project{ID: "X", Name: "x"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"project":[{"id":"X","name":"x"},{"id":"Y","name":"y"}]}`, string(bytes))
+}
+
+func TestAttachedTemplates(t *testing.T) {
+	assert.Equal(t, TemplateIds{"A", "B"}, BuildConfiguration{Templates: TemplateIds{"A", "B"}, TemplateID: "A"}.AttachedTemplates())
+	assert.Equal(t, TemplateIds{"A"}, BuildConfiguration{TemplateID: "A"}.AttachedTemplates())
+	assert.Equal(t, TemplateIds{}, BuildConfiguration{}.AttachedTemplates())
+}
@@ -8,6 +8,17 @@ type BuildFeature struct {
 	ID         string     `json:"id"`
 	Type       string     `json:"type"`
 	Properties Properties `json:"properties"`
+	Disabled   bool       `json:"disabled,omitempty"`
+	Inherited  bool       `json:"inherited,omitempty"`
+}
+
+// MarshalJSON leaves out Inherited, which only the server sets
+func (f BuildFeature) MarshalJSON() ([]byte, error) {
+	type buildFeature BuildFeature
+	return json.Marshal(struct {
+		buildFeature
+		Inherited bool `json:"inherited,omitempty"`
+	}{buildFeature: buildFeature(f)})
 }
 
 type BuildFeatures []BuildFeature
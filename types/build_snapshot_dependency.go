@@ -9,6 +9,17 @@ type BuildSnapshotDependency struct {
 	Type            string     `json:"type"`
 	Properties      Properties `json:"properties"`
 	SourceBuildType BuildType  `json:"source-buildType"`
+	Disabled        bool       `json:"disabled,omitempty"`
+	Inherited       bool       `json:"inherited,omitempty"`
+}
+
+// MarshalJSON leaves out Inherited, which only the server sets
+func (d BuildSnapshotDependency) MarshalJSON() ([]byte, error) {
+	type buildSnapshotDependency BuildSnapshotDependency
+	return json.Marshal(struct {
+		buildSnapshotDependency
+		Inherited bool `json:"inherited,omitempty"`
+	}{buildSnapshotDependency: buildSnapshotDependency(d)})
 }
 
 type BuildSnapshotDependencies []BuildSnapshotDependency
@@ -9,6 +9,17 @@ type BuildStep struct {
 	Type       string     `json:"type"`
 	Name       string     `json:"name"`
 	Properties Properties `json:"properties"`
+	Disabled   bool       `json:"disabled,omitempty"`
+	Inherited  bool       `json:"inherited,omitempty"`
+}
+
+// MarshalJSON leaves out Inherited, which only the server sets
+func (s BuildStep) MarshalJSON() ([]byte, error) {
+	type buildStep BuildStep
+	return json.Marshal(struct {
+		buildStep
+		Inherited bool `json:"inherited,omitempty"`
+	}{buildStep: buildStep(s)})
 }
 
 type BuildSteps []BuildStep
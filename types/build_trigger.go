@@ -8,6 +8,17 @@ type BuildTrigger struct {
 	ID         string     `json:"id"`
 	Type       string     `json:"type"`
 	Properties Properties `json:"properties"`
+	Disabled   bool       `json:"disabled,omitempty"`
+	Inherited  bool       `json:"inherited,omitempty"`
+}
+
+// MarshalJSON leaves out Inherited, which only the server sets
+func (t BuildTrigger) MarshalJSON() ([]byte, error) {
+	type buildTrigger BuildTrigger
+	return json.Marshal(struct {
+		buildTrigger
+		Inherited bool `json:"inherited,omitempty"`
+	}{buildTrigger: buildTrigger(t)})
 }
 
 type BuildTriggers []BuildTrigger
@@ -0,0 +1,147 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckoutRule is one line of a TeamCity checkout rule, e.g. "+:src=>target"
+// or "-:docs". From is the VCS path relative to the repository root, "."
+// for the root itself. To is the path on the agent and is empty when the
+// rule does not remap.
+type CheckoutRule struct {
+	Exclude bool
+	From    string
+	To      string
+}
+
+// CheckoutRules as found in VcsRootEntry.CheckoutRules. Wildcards are not
+// supported.
+type CheckoutRules []CheckoutRule
+
+// ParseCheckoutRules parses newline separated checkout rules. Lines without a
+// "+:" or "-:" prefix are include rules.
+func ParseCheckoutRules(s string) (CheckoutRules, error) {
+	rules := make(CheckoutRules, 0)
+	for n, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		rule := CheckoutRule{}
+		if strings.HasPrefix(line, "-:") {
+			rule.Exclude = true
+			line = line[2:]
+		} else if strings.HasPrefix(line, "+:") {
+			line = line[2:]
+		}
+		parts := strings.SplitN(line, "=>", 2)
+		rule.From = normalizeCheckoutPath(parts[0])
+		if len(parts) == 2 {
+			if rule.Exclude {
+				return nil, fmt.Errorf("checkout rule line %d: exclude rules cannot map paths", n+1)
+			}
+			rule.To = normalizeCheckoutPath(parts[1])
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func normalizeCheckoutPath(p string) string {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return "."
+	}
+	return p
+}
+
+func (r CheckoutRule) String() string {
+	prefix := "+:"
+	if r.Exclude {
+		prefix = "-:"
+	}
+	if r.To != "" {
+		return fmt.Sprintf("%s%s=>%s", prefix, r.From, r.To)
+	}
+	return prefix + r.From
+}
+
+func (r CheckoutRules) String() string {
+	lines := make([]string, len(r))
+	for idx, rule := range r {
+		lines[idx] = rule.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r CheckoutRule) matches(path string) bool {
+	return r.From == "." || path == r.From || strings.HasPrefix(path, r.From+"/")
+}
+
+func (r CheckoutRule) specificity() int {
+	if r.From == "." {
+		return 0
+	}
+	return len(r.From) + 1
+}
+
+// Map returns where a VCS path ends up in the checkout directory, or false
+// if the rules exclude it. The rule with the longest matching path wins, with
+// later lines winning ties. Without any include rules the whole repository
+// is included.
+func (r CheckoutRules) Map(path string) (string, bool) {
+	path = normalizeCheckoutPath(path)
+
+	var best *CheckoutRule
+	hasInclude := false
+	for idx := range r {
+		rule := &r[idx]
+		if !rule.Exclude {
+			hasInclude = true
+		}
+		if rule.matches(path) && (best == nil || rule.specificity() >= best.specificity()) {
+			best = rule
+		}
+	}
+
+	if best == nil {
+		if hasInclude {
+			return "", false
+		}
+		return path, true
+	}
+	if best.Exclude {
+		return "", false
+	}
+
+	rest := path
+	if best.From != "." {
+		rest = strings.TrimPrefix(strings.TrimPrefix(path, best.From), "/")
+	}
+	to := best.To
+	if to == "" {
+		to = best.From
+	}
+	switch {
+	case to == ".":
+		if rest == "" {
+			return ".", true
+		}
+		return rest, true
+	case rest == "" || rest == ".":
+		return to, true
+	}
+	return to + "/" + rest, true
+}
+
+// Includes tells whether a VCS path is checked out at all
+func (r CheckoutRules) Includes(path string) bool {
+	_, ok := r.Map(path)
+	return ok
+}
+
+// ParsedCheckoutRules parses the checkout rules of the entry
+func (vre VcsRootEntry) ParsedCheckoutRules() (CheckoutRules, error) {
+	return ParseCheckoutRules(vre.CheckoutRules)
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCheckoutRules(t *testing.T) {
+	rules, err := ParseCheckoutRules("+:src=>target\n-:docs\n\n  lib/  \n+:.=>root")
+	require.NoError(t, err)
+	assert.Equal(t, CheckoutRules{
+		{From: "src", To: "target"},
+		{Exclude: true, From: "docs"},
+		{From: "lib"},
+		{From: ".", To: "root"},
+	}, rules)
+	assert.Equal(t, "+:src=>target\n-:docs\n+:lib\n+:.=>root", rules.String())
+
+	_, err = ParseCheckoutRules("-:docs=>elsewhere")
+	assert.EqualError(t, err, "checkout rule line 1: exclude rules cannot map paths")
+}
+
+func TestCheckoutRulesMap(t *testing.T) {
+	rules, err := ParseCheckoutRules("+:src=>target\n+:src/vendor=>third_party\n-:src/tmp\n+:README.md")
+	require.NoError(t, err)
+
+	cases := []struct {
+		path   string
+		target string
+		ok     bool
+	}{
+		{"src/main.go", "target/main.go", true},
+		{"src", "target", true},
+		{"src/vendor/lib/a.go", "third_party/lib/a.go", true},
+		{"src/tmp/x", "", false},
+		{"README.md", "README.md", true},
+		{"docs/index.md", "", false},
+		{"srcfoo/a", "", false},
+	}
+	for _, c := range cases {
+		target, ok := rules.Map(c.path)
+		assert.Equal(t, c.ok, ok, c.path)
+		assert.Equal(t, c.target, target, c.path)
+	}
+}
+
+func TestCheckoutRulesOnlyExcludes(t *testing.T) {
+	rules, err := ParseCheckoutRules("-:docs")
+	require.NoError(t, err)
+
+	assert.True(t, rules.Includes("src/main.go"))
+	assert.False(t, rules.Includes("docs/index.md"))
+
+	empty, err := VcsRootEntry{}.ParsedCheckoutRules()
+	require.NoError(t, err)
+	assert.True(t, empty.Includes("anything"))
+}
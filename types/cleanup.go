@@ -0,0 +1,27 @@
+package types
+
+// CleanupSettings are the server-wide clean-up settings: whether clean-up
+// runs, when, and for how long at most. Exactly one of Daily and Cron is set.
+type CleanupSettings struct {
+	Enabled bool `json:"enabled"`
+	// MaxCleanupDuration is in minutes, 0 means unlimited
+	MaxCleanupDuration int           `json:"maxCleanupDuration"`
+	Daily              *CleanupDaily `json:"daily,omitempty"`
+	Cron               *CleanupCron  `json:"cron,omitempty"`
+}
+
+// CleanupDaily starts clean-up every day at the given time
+type CleanupDaily struct {
+	Hour   int `json:"hour"`
+	Minute int `json:"minute"`
+}
+
+// CleanupCron starts clean-up on a cron-like schedule, e.g. Minute "0",
+// Hour "3", Day "*", Month "*", DayWeek "?"
+type CleanupCron struct {
+	Minute  string `json:"minute"`
+	Hour    string `json:"hour"`
+	Day     string `json:"day"`
+	Month   string `json:"month"`
+	DayWeek string `json:"dayWeek"`
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"fmt"
+)
+
+// ParameterSource is the kind of entity a parameter value comes from
+type ParameterSource int
+
+const (
+	ProjectSource ParameterSource = iota
+	TemplateSource
+	BuildTypeSource
+)
+
+func (s ParameterSource) String() string {
+	if s == ProjectSource {
+		return "project"
+	}
+	if s == TemplateSource {
+		return "template"
+	}
+	return "build type"
+}
+
+// ParameterOrigin identifies the project, template or build configuration
+// that defines a parameter.
+type ParameterOrigin struct {
+	Source ParameterSource
+	ID     string
+}
+
+func (o ParameterOrigin) String() string {
+	return fmt.Sprintf("%s %s", o.Source, o.ID)
+}
+
+// ParameterLayer is the set of parameters defined directly on one entity
+type ParameterLayer struct {
+	Origin     ParameterOrigin
+	Parameters Parameters
+}
+
+// EffectiveParameter is the value a build sees together with where it was
+// defined. Overridden lists the origins whose definitions it replaced, from
+// closest to furthest away.
+type EffectiveParameter struct {
+	Parameter
+	Origin     ParameterOrigin
+	Overridden []ParameterOrigin
+}
+
+type EffectiveParameters map[string]EffectiveParameter
+
+// MergeParameterLayers merges parameter layers ordered from lowest to highest
+// priority, i.e. root project first and the build configuration last. Only
+// the parameters defined on each layer itself are considered, so the layers
+// can be taken straight from the REST API.
+func MergeParameterLayers(layers ...ParameterLayer) EffectiveParameters {
+	effective := make(EffectiveParameters)
+	for _, layer := range layers {
+		for name, parameter := range layer.Parameters.Own() {
+			merged := EffectiveParameter{
+				Parameter: parameter,
+				Origin:    layer.Origin,
+			}
+			if previous, ok := effective[name]; ok {
+				merged.Overridden = append([]ParameterOrigin{previous.Origin}, previous.Overridden...)
+				if merged.Spec == nil {
+					merged.Spec = previous.Spec
+				}
+			}
+			effective[name] = merged
+		}
+	}
+	return effective
+}
+
+// Parameters returns the effective parameters without origin information
+func (e EffectiveParameters) Parameters() Parameters {
+	parameters := make(Parameters)
+	for name, parameter := range e {
+		parameters[name] = parameter.Parameter
+	}
+	return parameters
+}
+
+// Resolver returns a resolver for references between effective parameters
+func (e EffectiveParameters) Resolver() *ParameterResolver {
+	return NewParameterResolver(e.Parameters())
+}
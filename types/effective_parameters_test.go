@@ -0,0 +1,48 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeParameterLayers(t *testing.T) {
+	root := ParameterOrigin{Source: ProjectSource, ID: "_Root"}
+	project := ParameterOrigin{Source: ProjectSource, ID: "Single"}
+	template := ParameterOrigin{Source: TemplateSource, ID: "Single_Template"}
+	buildType := ParameterOrigin{Source: BuildTypeSource, ID: "Single_Normal"}
+
+	spec := &ParameterSpec{Label: "Version", Type: TextType{ValidationMode: "any"}}
+	effective := MergeParameterLayers(
+		ParameterLayer{Origin: root, Parameters: Parameters{
+			"version": Parameter{Value: "1.0", Spec: spec},
+			"tools":   Parameter{Value: "/opt/tools"},
+		}},
+		ParameterLayer{Origin: project, Parameters: Parameters{
+			"version": Parameter{Value: "2.0"},
+			"tools":   Parameter{Value: "/opt/tools", Inherited: true},
+		}},
+		ParameterLayer{Origin: template, Parameters: Parameters{
+			"env.PATH": Parameter{Value: "%tools%/bin"},
+		}},
+		ParameterLayer{Origin: buildType, Parameters: Parameters{
+			"version":  Parameter{Value: "3.0"},
+			"env.PATH": Parameter{Value: "%tools%/bin", Inherited: true},
+		}},
+	)
+
+	assert.Equal(t, EffectiveParameter{
+		Parameter:  Parameter{Value: "3.0", Spec: spec},
+		Origin:     buildType,
+		Overridden: []ParameterOrigin{project, root},
+	}, effective["version"])
+	assert.Equal(t, root, effective["tools"].Origin)
+	assert.Empty(t, effective["tools"].Overridden)
+	assert.Equal(t, template, effective["env.PATH"].Origin)
+	assert.Equal(t, "template Single_Template", effective["env.PATH"].Origin.String())
+
+	value, err := effective.Resolver().Resolve("env.PATH")
+	require.NoError(t, err)
+	assert.Equal(t, "/opt/tools/bin", value)
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HealthSeverity is the severity of a server health item
+type HealthSeverity string
+
+const (
+	HealthInfo    HealthSeverity = "INFO"
+	HealthWarning HealthSeverity = "WARN"
+	HealthError   HealthSeverity = "ERROR"
+)
+
+var healthSeverityRank = map[HealthSeverity]int{
+	HealthInfo:    1,
+	HealthWarning: 2,
+	HealthError:   3,
+}
+
+// AtLeast tells whether s is as severe as other or more
+func (s HealthSeverity) AtLeast(other HealthSeverity) bool {
+	return healthSeverityRank[s] >= healthSeverityRank[other]
+}
+
+// HealthCategory is the kind of a health item, e.g. unused VCS roots or low
+// disk space
+type HealthCategory struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	HelpURL     string `json:"helpUrl,omitempty"`
+}
+
+// HealthItem is an item of the server health report. Items about a project,
+// build configuration, VCS root or agent refer to it.
+type HealthItem struct {
+	Identity  string                   `json:"identity"`
+	Severity  HealthSeverity           `json:"severity"`
+	Category  HealthCategory           `json:"healthCategory"`
+	Project   ProjectId                `json:"project,omitempty"`
+	BuildType *BuildConfigurationShort `json:"buildType,omitempty"`
+	VcsRoot   VcsRootId                `json:"vcsRoot,omitempty"`
+	Agent     *Agent                   `json:"agent,omitempty"`
+}
+
+// HealthFilter selects health items. Empty fields do not filter.
+type HealthFilter struct {
+	MinimumSeverity HealthSeverity
+	CategoryID      string
+	// ProjectID selects the items about the project and everything in it
+	ProjectID string
+}
+
+// Locator returns the health item locator for the filter
+func (f HealthFilter) Locator() string {
+	var dimensions []string
+	if f.MinimumSeverity != "" {
+		dimensions = append(dimensions, fmt.Sprintf("minimumSeverity:%s", f.MinimumSeverity))
+	}
+	if f.CategoryID != "" {
+		dimensions = append(dimensions, fmt.Sprintf("category:(id:%s)", f.CategoryID))
+	}
+	if f.ProjectID != "" {
+		dimensions = append(dimensions, fmt.Sprintf("affectedProject:(id:%s)", f.ProjectID))
+	}
+	return strings.Join(dimensions, ",")
+}
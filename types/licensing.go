@@ -0,0 +1,78 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// LicensingData summarises the licenses of the server. The Max and Left
+// counts are only meaningful when the matching Unlimited flag is false.
+type LicensingData struct {
+	LicenseUseExceeded         bool        `json:"licenseUseExceeded"`
+	MaxAgents                  int         `json:"maxAgents"`
+	UnlimitedAgents            bool        `json:"unlimitedAgents"`
+	AgentsLeft                 int         `json:"agentsLeft"`
+	MaxBuildTypes              int         `json:"maxBuildTypes"`
+	UnlimitedBuildTypes        bool        `json:"unlimitedBuildTypes"`
+	BuildTypesLeft             int         `json:"buildTypesLeft"`
+	ServerLicenseType          string      `json:"serverLicenseType"`
+	ServerEffectiveReleaseDate JSONTime    `json:"serverEffectiveReleaseDate,omitempty"`
+	LicenseKeys                LicenseKeys `json:"licenseKeys,omitempty"`
+}
+
+// LicenseKey is a license key entered on the server
+type LicenseKey struct {
+	Key                 string   `json:"key"`
+	Type                string   `json:"type,omitempty"`
+	RawType             string   `json:"rawType,omitempty"`
+	Valid               bool     `json:"valid"`
+	Active              bool     `json:"active"`
+	Expired             bool     `json:"expired"`
+	Obsolete            bool     `json:"obsolete"`
+	ExpirationDate      JSONTime `json:"expirationDate,omitempty"`
+	MaintenanceEndDate  JSONTime `json:"maintenanceEndDate,omitempty"`
+	Servers             int      `json:"servers,omitempty"`
+	Agents              int      `json:"agents,omitempty"`
+	UnlimitedAgents     bool     `json:"unlimitedAgents,omitempty"`
+	BuildTypes          int      `json:"buildTypes,omitempty"`
+	UnlimitedBuildTypes bool     `json:"unlimitedBuildTypes,omitempty"`
+	ErrorDetails        string   `json:"errorDetails,omitempty"`
+}
+
+type LicenseKeys []LicenseKey
+
+type licenseKeysJSON struct {
+	Count      int          `json:"count"`
+	LicenseKey []LicenseKey `json:"licenseKey"`
+}
+
+func (l LicenseKeys) MarshalJSON() ([]byte, error) {
+	keys := licenseKeysJSON{Count: len(l), LicenseKey: make([]LicenseKey, 0, len(l))}
+	keys.LicenseKey = append(keys.LicenseKey, l...)
+	return json.Marshal(keys)
+}
+
+func (l *LicenseKeys) UnmarshalJSON(b []byte) error {
+	var keys licenseKeysJSON
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return err
+	}
+	*l = LicenseKeys(keys.LicenseKey)
+	return nil
+}
+
+// Expiry returns the earliest expiration date of the active keys, or the
+// zero time if none of them expire
+func (l LicensingData) Expiry() time.Time {
+	var expiry time.Time
+	for _, key := range l.LicenseKeys {
+		if !key.Active || key.ExpirationDate.IsZero() {
+			continue
+		}
+		date := key.ExpirationDate.Time()
+		if expiry.IsZero() || date.Before(expiry) {
+			expiry = date
+		}
+	}
+	return expiry
+}
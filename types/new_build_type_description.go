@@ -0,0 +1,10 @@
+package types
+
+// NewBuildTypeDescription is the request body TeamCity accepts when creating
+// a build configuration or template as a copy of an existing one.
+type NewBuildTypeDescription struct {
+	Name                      string `json:"name"`
+	ID                        string `json:"id,omitempty"`
+	SourceBuildTypeLocator    string `json:"sourceBuildTypeLocator,omitempty"`
+	CopyAllAssociatedSettings bool   `json:"copyAllAssociatedSettings"`
+}
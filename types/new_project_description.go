@@ -0,0 +1,16 @@
+package types
+
+// ProjectLocator references a project by locator, e.g. "id:Empty"
+type ProjectLocator struct {
+	Locator string `json:"locator"`
+}
+
+// NewProjectDescription is the request body TeamCity accepts when creating a
+// project, optionally as a copy of an existing one.
+type NewProjectDescription struct {
+	Name                      string          `json:"name"`
+	ID                        string          `json:"id,omitempty"`
+	ParentProject             *ProjectLocator `json:"parentProject,omitempty"`
+	SourceProject             *ProjectLocator `json:"sourceProject,omitempty"`
+	CopyAllAssociatedSettings bool            `json:"copyAllAssociatedSettings"`
+}
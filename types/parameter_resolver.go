@@ -0,0 +1,322 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Parameter name prefixes with special meaning to TeamCity
+const (
+	EnvNamespace    = "env."
+	SystemNamespace = "system."
+	DepNamespace    = "dep."
+)
+
+// Parameters with these prefixes are supplied by TeamCity when the build runs
+var predefinedPrefixes = []string{"build.", "teamcity.", "agent.", "vcsroot.", "secure:"}
+
+var referenceName = regexp.MustCompile(`^[A-Za-z0-9_.:\-]+$`)
+
+// scanReferences calls found for every %name% reference and literal for the
+// text between them. "%%" is an escaped percent sign and a lone "%" is kept
+// as is.
+func scanReferences(value string, literal func(string), found func(string)) {
+	for len(value) > 0 {
+		start := strings.Index(value, "%")
+		if start < 0 {
+			literal(value)
+			return
+		}
+		literal(value[:start])
+		value = value[start:]
+		if strings.HasPrefix(value, "%%") {
+			literal("%")
+			value = value[2:]
+			continue
+		}
+		end := strings.Index(value[1:], "%")
+		if end < 0 || !referenceName.MatchString(value[1:end+1]) {
+			literal("%")
+			value = value[1:]
+			continue
+		}
+		found(value[1 : end+1])
+		value = value[end+2:]
+	}
+}
+
+// ParameterReferences returns the names referenced as %name% in a value, in
+// order of first appearance.
+func ParameterReferences(value string) []string {
+	seen := make(map[string]bool)
+	refs := make([]string, 0)
+	scanReferences(value, func(string) {}, func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			refs = append(refs, name)
+		}
+	})
+	return refs
+}
+
+// ParseDependencyReference splits a reference such as
+// "dep.Project_Build.version" into the build configuration ID and the
+// parameter name.
+func ParseDependencyReference(name string) (buildTypeID string, parameter string, ok bool) {
+	if !strings.HasPrefix(name, DepNamespace) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(name, DepNamespace), ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// IsPredefinedParameter tells whether TeamCity provides the parameter itself
+// when the build runs, e.g. build.number or teamcity.build.id.
+func IsPredefinedParameter(name string) bool {
+	for _, prefix := range predefinedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAgentParameter tells whether an undefined parameter may be provided by
+// the agent environment.
+func isAgentParameter(name string) bool {
+	return strings.HasPrefix(name, EnvNamespace) || strings.HasPrefix(name, SystemNamespace)
+}
+
+// UndefinedParameterError is returned when a reference has no definition and
+// TeamCity would not supply one either.
+type UndefinedParameterError struct {
+	Name         string
+	ReferencedBy string
+}
+
+func (e *UndefinedParameterError) Error() string {
+	if e.ReferencedBy == "" {
+		return fmt.Sprintf("parameter %s is not defined", e.Name)
+	}
+	return fmt.Sprintf("parameter %s referenced by %s is not defined", e.Name, e.ReferencedBy)
+}
+
+// ParameterCycleError is returned when parameters reference each other in a
+// loop. Cycle starts and ends with the same name.
+type ParameterCycleError struct {
+	Cycle []string
+}
+
+func (e *ParameterCycleError) Error() string {
+	return fmt.Sprintf("parameter reference cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// ParameterResolver expands %name% references against layered parameter
+// sets, for example the project chain followed by the build configuration and
+// the build. Later layers override earlier ones.
+type ParameterResolver struct {
+	layers []Parameters
+	// Dependencies holds the parameters of snapshot dependencies by build
+	// configuration ID, used to resolve dep.<buildTypeID>.<name> references.
+	Dependencies map[string]Parameters
+}
+
+func NewParameterResolver(layers ...Parameters) *ParameterResolver {
+	return &ParameterResolver{
+		layers:       layers,
+		Dependencies: make(map[string]Parameters),
+	}
+}
+
+// Lookup returns the effective definition of a parameter
+func (r *ParameterResolver) Lookup(name string) (Parameter, bool) {
+	if buildTypeID, dependencyParameter, ok := ParseDependencyReference(name); ok {
+		parameter, ok := r.Dependencies[buildTypeID][dependencyParameter]
+		return parameter, ok
+	}
+	for idx := len(r.layers) - 1; idx >= 0; idx-- {
+		if parameter, ok := r.layers[idx][name]; ok {
+			return parameter, true
+		}
+	}
+	return Parameter{}, false
+}
+
+// Names returns every parameter defined in any layer, sorted
+func (r *ParameterResolver) Names() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, layer := range r.layers {
+		for name := range layer {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve returns the fully expanded value of a parameter
+func (r *ParameterResolver) Resolve(name string) (string, error) {
+	parameter, ok := r.Lookup(name)
+	if !ok {
+		return "", &UndefinedParameterError{Name: name}
+	}
+	return r.expand(parameter.Value, name, []string{name})
+}
+
+// Expand substitutes all references in value. References TeamCity or the
+// agent provide at build time, such as %build.number% or an undefined
+// %env.HOME%, are left in place.
+func (r *ParameterResolver) Expand(value string) (string, error) {
+	return r.expand(value, "", nil)
+}
+
+func (r *ParameterResolver) expand(value, owner string, stack []string) (string, error) {
+	var out strings.Builder
+	var err error
+	scanReferences(value, func(text string) {
+		out.WriteString(text)
+	}, func(name string) {
+		if err != nil {
+			return
+		}
+		var expanded string
+		expanded, err = r.expandReference(name, owner, stack)
+		out.WriteString(expanded)
+	})
+	return out.String(), err
+}
+
+func (r *ParameterResolver) expandReference(name, owner string, stack []string) (string, error) {
+	for idx, seen := range stack {
+		if seen == name {
+			cycle := append(append([]string{}, stack[idx:]...), name)
+			return "", &ParameterCycleError{Cycle: cycle}
+		}
+	}
+
+	if buildTypeID, dependencyParameter, ok := ParseDependencyReference(name); ok {
+		dependency, ok := r.Dependencies[buildTypeID]
+		if !ok {
+			return "%" + name + "%", nil
+		}
+		if _, ok := dependency[dependencyParameter]; !ok {
+			return "", &UndefinedParameterError{Name: name, ReferencedBy: owner}
+		}
+		return NewParameterResolver(dependency).Resolve(dependencyParameter)
+	}
+
+	parameter, ok := r.Lookup(name)
+	if !ok {
+		if IsPredefinedParameter(name) || isAgentParameter(name) {
+			return "%" + name + "%", nil
+		}
+		return "", &UndefinedParameterError{Name: name, ReferencedBy: owner}
+	}
+	return r.expand(parameter.Value, name, append(stack, name))
+}
+
+// ParameterRequirements lists what a set of parameters needs from outside
+type ParameterRequirements struct {
+	// Undefined references with no definition that TeamCity will not supply
+	Undefined []string
+	// Agent references to env. and system. parameters that are not defined
+	// and so must come from the agent, becoming implicit agent requirements
+	Agent []string
+	// Dependencies maps build configuration IDs to the parameters referenced
+	// through dep.<buildTypeID>.<name>
+	Dependencies map[string][]string
+}
+
+// Requirements collects the references made by all defined parameters and by
+// any additional values, such as build step properties, and reports those the
+// resolver cannot satisfy.
+func (r *ParameterResolver) Requirements(values ...string) *ParameterRequirements {
+	req := &ParameterRequirements{
+		Undefined:    make([]string, 0),
+		Agent:        make([]string, 0),
+		Dependencies: make(map[string][]string),
+	}
+	seen := make(map[string]bool)
+
+	var visit func(value string)
+	visit = func(value string) {
+		for _, name := range ParameterReferences(value) {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if buildTypeID, dependencyParameter, ok := ParseDependencyReference(name); ok {
+				req.Dependencies[buildTypeID] = append(req.Dependencies[buildTypeID], dependencyParameter)
+				continue
+			}
+			if parameter, ok := r.Lookup(name); ok {
+				visit(parameter.Value)
+				continue
+			}
+			if isAgentParameter(name) {
+				req.Agent = append(req.Agent, name)
+			} else if !IsPredefinedParameter(name) {
+				req.Undefined = append(req.Undefined, name)
+			}
+		}
+	}
+
+	for _, name := range r.Names() {
+		parameter, _ := r.Lookup(name)
+		visit(parameter.Value)
+	}
+	for _, value := range values {
+		visit(value)
+	}
+
+	sort.Strings(req.Undefined)
+	sort.Strings(req.Agent)
+	for buildTypeID := range req.Dependencies {
+		sort.Strings(req.Dependencies[buildTypeID])
+	}
+	return req
+}
+
+// ReferencingValues returns every setting and property value of the build
+// configuration that may contain parameter references, for use with
+// ParameterResolver.Requirements.
+func (b BuildConfiguration) ReferencingValues() []string {
+	values := make([]string, 0)
+	addProperties := func(properties Properties) {
+		for _, value := range properties {
+			values = append(values, value)
+		}
+	}
+	for _, setting := range b.Settings {
+		values = append(values, setting.Value)
+	}
+	for _, entry := range b.VcsRootEntries {
+		values = append(values, entry.CheckoutRules)
+	}
+	for _, step := range b.Steps {
+		addProperties(step.Properties)
+	}
+	for _, feature := range b.Features {
+		addProperties(feature.Properties)
+	}
+	for _, trigger := range b.Triggers {
+		addProperties(trigger.Properties)
+	}
+	for _, requirement := range b.AgentRequirements {
+		addProperties(requirement.Properties)
+	}
+	for _, dependency := range b.ArtifactDependencies {
+		addProperties(dependency.Properties)
+	}
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterReferences(t *testing.T) {
+	assert.Equal(t, []string{"env.HOME", "dep.Project_Build.version"},
+		ParameterReferences("%env.HOME%/bin:%dep.Project_Build.version%:%env.HOME%"))
+	assert.Equal(t, []string{}, ParameterReferences("100%% done, 50% left"))
+	assert.Equal(t, []string{"secure:teamcity.password.MUH"}, ParameterReferences("%secure:teamcity.password.MUH%"))
+}
+
+func TestParseDependencyReference(t *testing.T) {
+	buildTypeID, name, ok := ParseDependencyReference("dep.Project_Build.build.number")
+	assert.True(t, ok)
+	assert.Equal(t, "Project_Build", buildTypeID)
+	assert.Equal(t, "build.number", name)
+
+	_, _, ok = ParseDependencyReference("dep.Project_Build")
+	assert.False(t, ok)
+	_, _, ok = ParseDependencyReference("env.HOME")
+	assert.False(t, ok)
+}
+
+func TestParameterResolverLayers(t *testing.T) {
+	root := Parameters{
+		"tools":   Parameter{Value: "/opt/tools"},
+		"version": Parameter{Value: "1.0"},
+	}
+	buildType := Parameters{
+		"version":  Parameter{Value: "2.%build.counter%"},
+		"env.PATH": Parameter{Value: "%tools%/bin:%env.HOME%/bin"},
+		"artifact": Parameter{Value: "app-%version%.zip 100%%"},
+	}
+	r := NewParameterResolver(root, buildType)
+
+	value, err := r.Resolve("env.PATH")
+	require.NoError(t, err)
+	assert.Equal(t, "/opt/tools/bin:%env.HOME%/bin", value)
+
+	value, err = r.Resolve("artifact")
+	require.NoError(t, err)
+	assert.Equal(t, "app-2.%build.counter%.zip 100%", value)
+}
+
+func TestParameterResolverDependencies(t *testing.T) {
+	r := NewParameterResolver(Parameters{
+		"upstream": Parameter{Value: "v%dep.Project_Build.version%"},
+		"missing":  Parameter{Value: "%dep.Project_Build.nope%"},
+	})
+	value, err := r.Resolve("upstream")
+	require.NoError(t, err)
+	assert.Equal(t, "v%dep.Project_Build.version%", value)
+
+	r.Dependencies["Project_Build"] = Parameters{
+		"major":   Parameter{Value: "3"},
+		"version": Parameter{Value: "%major%.1"},
+	}
+	value, err = r.Resolve("upstream")
+	require.NoError(t, err)
+	assert.Equal(t, "v3.1", value)
+
+	_, err = r.Resolve("missing")
+	assert.EqualError(t, err, "parameter dep.Project_Build.nope referenced by missing is not defined")
+}
+
+func TestParameterResolverErrors(t *testing.T) {
+	r := NewParameterResolver(Parameters{
+		"a":     Parameter{Value: "%b%"},
+		"b":     Parameter{Value: "x%c%"},
+		"c":     Parameter{Value: "%a%"},
+		"typo":  Parameter{Value: "%verison%"},
+		"valid": Parameter{Value: "fine"},
+	})
+
+	_, err := r.Resolve("a")
+	require.Error(t, err)
+	cycle, ok := err.(*ParameterCycleError)
+	require.True(t, ok, "Expected a cycle error")
+	assert.Equal(t, []string{"a", "b", "c", "a"}, cycle.Cycle)
+
+	_, err = r.Resolve("typo")
+	assert.EqualError(t, err, "parameter verison referenced by typo is not defined")
+
+	_, err = r.Resolve("nothing")
+	assert.EqualError(t, err, "parameter nothing is not defined")
+}
+
+func TestParameterResolverRequirements(t *testing.T) {
+	r := NewParameterResolver(Parameters{
+		"path":    Parameter{Value: "%env.JAVA_HOME%/bin"},
+		"version": Parameter{Value: "%dep.Project_Build.version%-%build.number%"},
+	})
+	config := BuildConfiguration{
+		Steps: BuildSteps{
+			BuildStep{Properties: Properties{"script.content": "deploy %path% %target.host% %system.user%"}},
+		},
+	}
+
+	req := r.Requirements(config.ReferencingValues()...)
+	assert.Equal(t, []string{"target.host"}, req.Undefined)
+	assert.Equal(t, []string{"env.JAVA_HOME", "system.user"}, req.Agent)
+	assert.Equal(t, map[string][]string{"Project_Build": {"version"}}, req.Dependencies)
+}
@@ -58,17 +58,72 @@ type ParameterType interface {
 	Values() map[string]string
 }
 
+// ParameterValidator is implemented by parameter types that can check a
+// value. Types without it accept any value.
+type ParameterValidator interface {
+	Validate(value string) error
+}
+
 type ParameterSpec struct {
 	Label       string
 	Description string
 	Display     Display
 	ReadOnly    ReadOnly
 	Type        ParameterType
+	// Extra holds spec attributes this package does not know about so that
+	// they survive a round trip to the server
+	Extra map[string]string
+}
+
+// ParameterValidationError is returned when a value does not satisfy its
+// parameter spec
+type ParameterValidationError struct {
+	Value   string
+	Message string
+}
+
+func (e *ParameterValidationError) Error() string {
+	return e.Message
+}
+
+func invalidValue(value, message string, args ...interface{}) error {
+	return &ParameterValidationError{
+		Value:   value,
+		Message: fmt.Sprintf(message, args...),
+	}
+}
+
+// Validate checks value against the spec. References such as %env.HOME% are
+// not resolved, so values containing them should be resolved first.
+func (s ParameterSpec) Validate(value string) error {
+	validator, ok := s.Type.(ParameterValidator)
+	if !ok {
+		return nil
+	}
+	return validator.Validate(value)
+}
+
+// SpecString returns the raw spec of the parameter, or an empty string for a
+// parameter without a spec
+func (p Parameter) SpecString() string {
+	if p.Spec == nil {
+		return ""
+	}
+	return p.Spec.String()
+}
+
+// Validate checks the parameter value against its spec, if it has one
+func (p Parameter) Validate() error {
+	if p.Spec == nil {
+		return nil
+	}
+	return p.Spec.Validate(p.Value)
 }
 
 type Parameter struct {
-	Value string
-	Spec  *ParameterSpec
+	Value     string
+	Spec      *ParameterSpec
+	Inherited bool
 }
 
 type PasswordType struct {
@@ -102,6 +157,22 @@ func (t CheckboxType) Values() map[string]string {
 	return ret
 }
 
+// Validate accepts the checked and unchecked values, which TeamCity defaults
+// to "true" and "false"
+func (t CheckboxType) Validate(value string) error {
+	checked, unchecked := t.Checked, t.Unchecked
+	if checked == "" {
+		checked = "true"
+	}
+	if unchecked == "" {
+		unchecked = "false"
+	}
+	if value != checked && value != unchecked {
+		return invalidValue(value, "value %q must be %q or %q", value, checked, unchecked)
+	}
+	return nil
+}
+
 type SelectItem struct {
 	Label string
 	Value string
@@ -121,7 +192,9 @@ func (t SelectType) Values() map[string]string {
 	ret := make(map[string]string)
 	if t.AllowMultiple {
 		ret["multiple"] = "true"
-		ret["valueSeparator"] = t.ValueSeparator
+		if t.ValueSeparator != "" {
+			ret["valueSeparator"] = t.ValueSeparator
+		}
 	}
 	for idx, item := range t.Items {
 		ret[fmt.Sprintf("data_%d", idx+1)] = item.Value
@@ -132,8 +205,46 @@ func (t SelectType) Values() map[string]string {
 	return ret
 }
 
+// Separator TeamCity uses between selected values when none is configured
+const DefaultValueSeparator = ","
+
+// Validate accepts the value of one of the items. When multiple items may be
+// selected the value is split on the separator and every part checked.
+func (t SelectType) Validate(value string) error {
+	allowed := make(map[string]bool)
+	for _, item := range t.Items {
+		allowed[item.Value] = true
+	}
+	values := []string{value}
+	if t.AllowMultiple {
+		if value == "" {
+			return nil
+		}
+		separator := t.ValueSeparator
+		if separator == "" {
+			separator = DefaultValueSeparator
+		}
+		values = strings.Split(value, separator)
+	}
+	for _, v := range values {
+		if !allowed[v] {
+			return invalidValue(value, "value %q is not one of the allowed items", v)
+		}
+	}
+	return nil
+}
+
+// Validation modes of text parameters
+const (
+	ValidationModeAny      = "any"
+	ValidationModeNotEmpty = "not_empty"
+	ValidationModeRegex    = "regex"
+)
+
 type TextType struct {
-	ValidationMode string
+	ValidationMode    string
+	Regex             string
+	ValidationMessage string
 }
 
 func (t TextType) TypeName() string {
@@ -143,15 +254,89 @@ func (t TextType) TypeName() string {
 func (t TextType) Values() map[string]string {
 	ret := make(map[string]string)
 	ret["validationMode"] = t.ValidationMode
+	if t.Regex != "" {
+		ret["regexp"] = t.Regex
+	}
+	if t.ValidationMessage != "" {
+		ret["validationMessage"] = t.ValidationMessage
+	}
+	return ret
+}
+
+// Validate applies the validation mode. Like TeamCity, a regex must match the
+// whole value.
+func (t TextType) Validate(value string) error {
+	var err error
+	switch t.ValidationMode {
+	case ValidationModeNotEmpty:
+		if value == "" {
+			err = invalidValue(value, "value must not be empty")
+		}
+	case ValidationModeRegex:
+		re, compileErr := regexp.Compile("^(?:" + t.Regex + ")$")
+		if compileErr != nil {
+			return fmt.Errorf("invalid regex %q: %s", t.Regex, compileErr)
+		}
+		if !re.MatchString(value) {
+			err = invalidValue(value, "value %q does not match %s", value, t.Regex)
+		}
+	}
+	if err != nil && t.ValidationMessage != "" {
+		err.(*ParameterValidationError).Message = t.ValidationMessage
+	}
+	return err
+}
+
+// UnknownType keeps the name and attributes of a spec type this package does
+// not model, so it can be written back unchanged
+type UnknownType struct {
+	Name       string
+	Attributes map[string]string
+}
+
+func (t UnknownType) TypeName() string {
+	return t.Name
+}
+
+func (t UnknownType) Values() map[string]string {
+	ret := make(map[string]string)
+	for name, value := range t.Attributes {
+		ret[name] = value
+	}
 	return ret
 }
 
 var dataRegex = regexp.MustCompile("^data_(\\d+)$")
+var labelRegex = regexp.MustCompile("^label_\\d+$")
+
+var commonSpecKeys = map[string]bool{
+	"label":       true,
+	"description": true,
+	"display":     true,
+	"readOnly":    true,
+}
+
+// isTypeSpecKey tells whether a spec attribute is modelled by the type
+func isTypeSpecKey(typeName, key string) bool {
+	switch typeName {
+	case "text":
+		return key == "validationMode" || key == "regexp" || key == "validationMessage"
+	case "checkbox":
+		return key == "checkedValue" || key == "uncheckedValue"
+	case "select":
+		return key == "multiple" || key == "valueSeparator" || dataRegex.MatchString(key) || labelRegex.MatchString(key)
+	case "password":
+		return false
+	}
+	return true
+}
 
 func parseParameterType(s string, v map[string]string) ParameterType {
 	if s == "text" {
 		return TextType{
-			ValidationMode: v["validationMode"],
+			ValidationMode:    v["validationMode"],
+			Regex:             v["regexp"],
+			ValidationMessage: v["validationMessage"],
 		}
 	}
 	if s == "select" {
@@ -188,7 +373,19 @@ func parseParameterType(s string, v map[string]string) ParameterType {
 			Unchecked: v["uncheckedValue"],
 		}
 	}
-	return PasswordType{}
+	if s == "password" {
+		return PasswordType{}
+	}
+	attributes := make(map[string]string)
+	for name, value := range v {
+		if !commonSpecKeys[name] {
+			attributes[name] = value
+		}
+	}
+	return UnknownType{
+		Name:       s,
+		Attributes: attributes,
+	}
 }
 
 type Parameters map[string]Parameter
@@ -202,9 +399,10 @@ type oneParameterType struct {
 }
 
 type oneParameter struct {
-	Name  string            `json:"name"`
-	Value string            `json:"value"`
-	Type  *oneParameterType `json:"type,omitempty"`
+	Name      string            `json:"name"`
+	Value     string            `json:"value"`
+	Type      *oneParameterType `json:"type,omitempty"`
+	Inherited bool              `json:"inherited,omitempty"`
 }
 
 func (p Parameter) rawTypeValue() *oneParameterType {
@@ -226,8 +424,17 @@ func (s ParameterSpec) String() string {
 	if s.Description != "" {
 		values["description"] = s.Description
 	}
+	for name, value := range s.Extra {
+		if _, ok := values[name]; !ok {
+			values[name] = value
+		}
+	}
 	values["display"] = s.Display.String()
-	values["readOnly"] = s.ReadOnly.String()
+	// TeamCity treats a missing readOnly as false and does not write it
+	// either, so only a read only spec carries the attribute
+	if s.ReadOnly {
+		values["readOnly"] = s.ReadOnly.String()
+	}
 
 	// Sort keys so that raw text is deterministic and testable
 	valueKeys := make([]string, 0)
@@ -252,33 +459,56 @@ func (opt *oneParameterType) parseRawValue() *ParameterSpec {
 	if opt == nil {
 		return nil
 	}
-	sp := strings.SplitN(opt.RawValue, " ", 2)
-	values := keyValue.FindAllString(sp[1], -1)
+	spec, err := ParseParameterSpec(opt.RawValue)
+	if err != nil {
+		log.Printf("[WARN] %s\n", err)
+		return nil
+	}
+	return spec
+}
+
+// ParseParameterSpec parses the raw spec TeamCity stores for a typed
+// parameter, e.g. "text display='prompt' validationMode='not_empty'"
+func ParseParameterSpec(raw string) (*ParameterSpec, error) {
+	sp := strings.SplitN(strings.TrimSpace(raw), " ", 2)
+	if sp[0] == "" {
+		return nil, fmt.Errorf("empty parameter spec")
+	}
 	specValue := make(map[string]string)
-	for _, value := range values {
-		kv := strings.SplitN(value, "=", 2)
-		aValue := strings.Replace(strings.Replace(kv[1][1:len(kv[1])-1], "|'", "'", -1), "||", "|", -1)
-		specValue[kv[0]] = aValue
+	if len(sp) > 1 {
+		for _, value := range keyValue.FindAllString(sp[1], -1) {
+			kv := strings.SplitN(value, "=", 2)
+			aValue := strings.Replace(strings.Replace(kv[1][1:len(kv[1])-1], "|'", "'", -1), "||", "|", -1)
+			specValue[kv[0]] = aValue
+		}
 	}
+
+	var extra map[string]string
+	for name, value := range specValue {
+		if !commonSpecKeys[name] && !isTypeSpecKey(sp[0], name) {
+			if extra == nil {
+				extra = make(map[string]string)
+			}
+			extra[name] = value
+		}
+	}
+
 	return &ParameterSpec{
 		Label:       specValue["label"],
 		Description: specValue["description"],
 		Display:     parseDisplay(specValue["display"]),
 		ReadOnly:    parseReadOnly(specValue["readOnly"]),
 		Type:        parseParameterType(sp[0], specValue),
-	}
+		Extra:       extra,
+	}, nil
 }
 
 func (p Parameters) MarshalJSON() ([]byte, error) {
 	pi := &parametersInput{
 		Parameter: make([]oneParameter, 0),
 	}
-	for name, parameter := range p {
-		pi.Parameter = append(pi.Parameter, oneParameter{
-			Name:  name,
-			Value: parameter.Value,
-			Type:  parameter.rawTypeValue(),
-		})
+	for _, parameter := range p.Ordered() {
+		pi.Parameter = append(pi.Parameter, parameter.oneParameter())
 	}
 	return json.Marshal(pi)
 }
@@ -290,32 +520,110 @@ func (p *Parameters) UnmarshalJSON(b []byte) error {
 	}
 	m := make(Parameters)
 	for _, prop := range pi.Parameter {
-		spec := prop.Type.parseRawValue()
-		/*
-		   pvt := PasswordType{}
-		   if prop.Value == "" && spec != nil && spec.Type == pvt {
-		      prop.Value = fmt.Sprintf("%%secure:teamcity.password.%s%%", prop.Name)
-		   }
-		*/
-		m[prop.Name] = Parameter{
-			Value: prop.Value,
-			Spec:  spec,
-		}
+		m[prop.Name] = prop.parameter()
 	}
 	*p = m
 	return nil
 }
 
+func (prop oneParameter) parameter() Parameter {
+	spec := prop.Type.parseRawValue()
+	/*
+	   pvt := PasswordType{}
+	   if prop.Value == "" && spec != nil && spec.Type == pvt {
+	      prop.Value = fmt.Sprintf("%%secure:teamcity.password.%s%%", prop.Name)
+	   }
+	*/
+	return Parameter{
+		Value:     prop.Value,
+		Spec:      spec,
+		Inherited: prop.Inherited,
+	}
+}
+
+// Ordered returns the parameters sorted by name
+func (p Parameters) Ordered() OrderedParameters {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ordered := make(OrderedParameters, 0, len(p))
+	for _, name := range names {
+		ordered = append(ordered, NamedParameter{Name: name, Parameter: p[name]})
+	}
+	return ordered
+}
+
+// OrderedParameters holds parameters in a fixed order. Unmarshaling keeps the
+// order the server sent, so a round trip writes them back unchanged.
+type OrderedParameters []NamedParameter
+
+// Parameters returns the parameters as a map. Later entries win when a name
+// appears more than once.
+func (p OrderedParameters) Parameters() Parameters {
+	m := make(Parameters)
+	for _, parameter := range p {
+		m[parameter.Name] = parameter.Parameter
+	}
+	return m
+}
+
+func (p OrderedParameters) MarshalJSON() ([]byte, error) {
+	pi := &parametersInput{
+		Parameter: make([]oneParameter, 0, len(p)),
+	}
+	for _, parameter := range p {
+		pi.Parameter = append(pi.Parameter, parameter.oneParameter())
+	}
+	return json.Marshal(pi)
+}
+
+func (p *OrderedParameters) UnmarshalJSON(b []byte) error {
+	var pi parametersInput
+	if err := json.Unmarshal(b, &pi); err != nil {
+		return err
+	}
+	ordered := make(OrderedParameters, 0, len(pi.Parameter))
+	for _, prop := range pi.Parameter {
+		ordered = append(ordered, NamedParameter{Name: prop.Name, Parameter: prop.parameter()})
+	}
+	*p = ordered
+	return nil
+}
+
+// Own returns the parameters defined directly on the project or build
+// configuration, leaving out those inherited from parents and templates.
+func (p Parameters) Own() Parameters {
+	own, _ := p.split()
+	return own
+}
+
+func (p Parameters) split() (own Parameters, inherited Parameters) {
+	own, inherited = make(Parameters), make(Parameters)
+	for name, parameter := range p {
+		if parameter.Inherited {
+			inherited[name] = parameter
+		} else {
+			own[name] = parameter
+		}
+	}
+	return
+}
+
 type NamedParameter struct {
 	Name string
 	Parameter
 }
 
-func (p NamedParameter) MarshalJSON() ([]byte, error) {
-	pi := &oneParameter{
+func (p NamedParameter) oneParameter() oneParameter {
+	return oneParameter{
 		Name:  p.Name,
 		Value: p.Value,
 		Type:  p.rawTypeValue(),
 	}
-	return json.Marshal(pi)
+}
+
+func (p NamedParameter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.oneParameter())
 }
@@ -43,7 +43,7 @@ func TestProperties(t *testing.T) {
 			"env.TEST_RUNNER": Parameter{
 				Value: "l",
 				Spec: &ParameterSpec{
-					Type:        TextType{"not_empty"},
+					Type:        TextType{ValidationMode: "not_empty"},
 					Description: "What test runner are we going to use",
 					Display:     Prompt,
 				},
@@ -76,3 +76,151 @@ func TestProperties(t *testing.T) {
 			},
 		})
 }
+
+func TestPropertiesFullSpec(t *testing.T) {
+	MarshalAndUnmarhalMatch(t, `{"property":[{"name":"version","value":"1.0","type":{"rawValue":"text data_1='custom' display='normal' readOnly='true' regexp='\\d+(\\.\\d+)*' validationMessage='Not a version' validationMode='regex'"}}]}`,
+		&Parameters{
+			"version": Parameter{
+				Value: "1.0",
+				Spec: &ParameterSpec{
+					Type: TextType{
+						ValidationMode:    ValidationModeRegex,
+						Regex:             `\d+(\.\d+)*`,
+						ValidationMessage: "Not a version",
+					},
+					ReadOnly: true,
+					Extra:    map[string]string{"data_1": "custom"},
+				},
+			},
+		})
+
+	MarshalAndUnmarhalMatch(t, `{"property":[{"name":"os","value":"linux;mac","type":{"rawValue":"select data_1='linux' data_2='mac' display='normal' multiple='true' valueSeparator=';'"}}]}`,
+		&Parameters{
+			"os": Parameter{
+				Value: "linux;mac",
+				Spec: &ParameterSpec{
+					Type: SelectType{
+						AllowMultiple:  true,
+						ValueSeparator: ";",
+						Items:          []SelectItem{{Value: "linux"}, {Value: "mac"}},
+					},
+				},
+			},
+		})
+
+	MarshalAndUnmarhalMatch(t, `{"property":[{"name":"token","value":"x","type":{"rawValue":"webhook display='normal' format='json'"}}]}`,
+		&Parameters{
+			"token": Parameter{
+				Value: "x",
+				Spec: &ParameterSpec{
+					Type: UnknownType{
+						Name:       "webhook",
+						Attributes: map[string]string{"format": "json"},
+					},
+				},
+			},
+		})
+}
+
+func TestParseParameterSpec(t *testing.T) {
+	spec, err := ParseParameterSpec("password")
+	assert.NoError(t, err)
+	assert.Equal(t, &ParameterSpec{Type: PasswordType{}}, spec)
+
+	_, err = ParseParameterSpec(" ")
+	assert.EqualError(t, err, "empty parameter spec")
+}
+
+func TestParameterSpecValidate(t *testing.T) {
+	version := ParameterSpec{Type: TextType{ValidationMode: ValidationModeRegex, Regex: `\d+(\.\d+)*`}}
+	assert.NoError(t, version.Validate("1.2.3"))
+	assert.EqualError(t, version.Validate("v1.2"), `value "v1.2" does not match \d+(\.\d+)*`)
+
+	required := ParameterSpec{Type: TextType{ValidationMode: ValidationModeNotEmpty, ValidationMessage: "Required"}}
+	assert.NoError(t, required.Validate("x"))
+	assert.EqualError(t, required.Validate(""), "Required")
+
+	checkbox := ParameterSpec{Type: CheckboxType{Checked: "yes"}}
+	assert.NoError(t, checkbox.Validate("yes"))
+	assert.NoError(t, checkbox.Validate("false"))
+	assert.Error(t, checkbox.Validate("true"))
+
+	multiple := ParameterSpec{Type: SelectType{
+		AllowMultiple: true,
+		Items:         []SelectItem{{Value: "linux"}, {Value: "mac"}},
+	}}
+	assert.NoError(t, multiple.Validate("linux,mac"))
+	assert.NoError(t, multiple.Validate(""))
+	assert.EqualError(t, multiple.Validate("linux,windows"), `value "windows" is not one of the allowed items`)
+
+	single := Parameter{Value: "windows", Spec: &ParameterSpec{Type: SelectType{Items: []SelectItem{{Value: "linux"}}}}}
+	assert.Error(t, single.Validate())
+	assert.NoError(t, Parameter{Value: "anything"}.Validate())
+
+	// types without a Validate method accept any value
+	assert.NoError(t, ParameterSpec{Type: legacyType{}}.Validate("anything"))
+	assert.NoError(t, ParameterSpec{Type: PasswordType{}}.Validate("anything"))
+	assert.NoError(t, ParameterSpec{Type: UnknownType{Name: "webhook"}}.Validate("anything"))
+}
+
+func TestParameterSpecString(t *testing.T) {
+	assert.Equal(t, "", Parameter{Value: "x"}.SpecString())
+	assert.Equal(t, "password display='normal'", Parameter{Spec: &ParameterSpec{Type: PasswordType{}}}.SpecString())
+}
+
+// legacyType is a ParameterType written before ParameterValidator existed
+type legacyType struct{}
+
+func (t legacyType) TypeName() string {
+	return "legacy"
+}
+
+func (t legacyType) Values() map[string]string {
+	return map[string]string{}
+}
+
+func TestParametersSortedMarshal(t *testing.T) {
+	props := Parameters{
+		"zeta":  Parameter{Value: "z"},
+		"alpha": Parameter{Value: "a"},
+		"mid":   Parameter{Value: "m"},
+	}
+	for i := 0; i < 10; i++ {
+		bytes, err := json.Marshal(props)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"property":[{"name":"alpha","value":"a"},{"name":"mid","value":"m"},{"name":"zeta","value":"z"}]}`, string(bytes))
+	}
+}
+
+func TestOrderedParametersRoundTrip(t *testing.T) {
+	jsonValue := `{"property":[{"name":"zeta","value":"z"},{"name":"alpha","value":"a","type":{"rawValue":"password display='normal'"}}]}`
+
+	var ordered OrderedParameters
+	err := json.Unmarshal([]byte(jsonValue), &ordered)
+	assert.NoError(t, err)
+	assert.Equal(t, OrderedParameters{
+		{Name: "zeta", Parameter: Parameter{Value: "z"}},
+		{Name: "alpha", Parameter: Parameter{Value: "a", Spec: &ParameterSpec{Type: PasswordType{}}}},
+	}, ordered)
+
+	bytes, err := json.Marshal(ordered)
+	assert.NoError(t, err)
+	assert.Equal(t, jsonValue, string(bytes))
+
+	assert.Equal(t, ordered.Parameters().Ordered(), OrderedParameters{ordered[1], ordered[0]})
+}
+
+func TestPropertiesSortedMarshal(t *testing.T) {
+	bytes, err := json.Marshal(Properties{"b": "2", "a": "1", "c": "3"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"property":[{"name":"a","value":"1"},{"name":"b","value":"2"},{"name":"c","value":"3"}]}`, string(bytes))
+
+	var ordered OrderedProperties
+	err = json.Unmarshal([]byte(`{"property":[{"name":"b","value":"2"},{"name":"a","value":"1"}]}`), &ordered)
+	assert.NoError(t, err)
+	assert.Equal(t, OrderedProperties{{Name: "b", Value: "2"}, {Name: "a", Value: "1"}}, ordered)
+	bytes, err = json.Marshal(ordered)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"property":[{"name":"b","value":"2"},{"name":"a","value":"1"}]}`, string(bytes))
+	assert.Equal(t, Properties{"a": "1", "b": "2"}, ordered.Properties())
+}
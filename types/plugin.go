@@ -0,0 +1,10 @@
+package types
+
+// Plugin is a plugin installed on the server
+type Plugin struct {
+	Name        string     `json:"name"`
+	DisplayName string     `json:"displayName,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	LoadPath    string     `json:"loadPath,omitempty"`
+	Parameters  Properties `json:"parameters,omitempty"`
+}
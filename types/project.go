@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"sort"
 )
 
 type Project struct {
@@ -11,10 +12,12 @@ type Project struct {
 	Href                string              `json:"href,omitempty"`
 	WebUrl              string              `json:"webUrl,omitempty"`
 	ParentProjectID     ProjectId           `json:"parentProject,omitempty"`
+	Archived            bool                `json:"archived,omitempty"`
 	BuildConfigurations BuildConfigurations `json:"buildTypes,omitempty"`
 	Templates           BuildConfigurations `json:"templates,omitempty"`
 	Parameters          Parameters          `json:"parameters,omitempty"`
 	Projects            Projects            `json:"projects,omitempty"`
+	Features            ProjectFeatures     `json:"projectFeatures,omitempty"`
 }
 
 type ProjectId string
@@ -54,8 +57,8 @@ func (p Projects) MarshalJSON() ([]byte, error) {
 	pi := &projectsInput{
 		Project: make([]Project, 0),
 	}
-	for _, value := range p {
-		pi.Project = append(pi.Project, value)
+	for _, id := range sortedProjectIDs(p) {
+		pi.Project = append(pi.Project, p[id])
 	}
 	return json.Marshal(pi)
 }
@@ -72,3 +75,12 @@ func (p *Projects) UnmarshalJSON(b []byte) error {
 	*p = m
 	return nil
 }
+
+func sortedProjectIDs(p Projects) []string {
+	ids := make([]string, 0, len(p))
+	for id := range p {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"encoding/json"
+)
+
+// ProjectFeature is a project level setting such as an OAuth connection,
+// Docker registry, issue tracker or the versioned settings configuration.
+type ProjectFeature struct {
+	ID         string     `json:"id,omitempty"`
+	Type       string     `json:"type"`
+	Properties Properties `json:"properties"`
+	Inherited  bool       `json:"inherited,omitempty"`
+}
+
+// MarshalJSON leaves out Inherited, which only the server sets
+func (f ProjectFeature) MarshalJSON() ([]byte, error) {
+	type projectFeature ProjectFeature
+	return json.Marshal(struct {
+		projectFeature
+		Inherited bool `json:"inherited,omitempty"`
+	}{projectFeature: projectFeature(f)})
+}
+
+type ProjectFeatures []ProjectFeature
+
+type projectFeaturesInput struct {
+	ProjectFeature []ProjectFeature `json:"projectFeature"`
+}
+
+func (pf ProjectFeatures) MarshalJSON() ([]byte, error) {
+	pfi := &projectFeaturesInput{
+		ProjectFeature: pf,
+	}
+	return json.Marshal(pfi)
+}
+
+func (pf *ProjectFeatures) UnmarshalJSON(b []byte) error {
+	var pfi projectFeaturesInput
+	if err := json.Unmarshal(b, &pfi); err != nil {
+		return err
+	}
+	if pfi.ProjectFeature != nil {
+		*pf = pfi.ProjectFeature
+	} else {
+		*pf = make(ProjectFeatures, 0)
+	}
+	return nil
+}
+
+// OfType returns the features with the given type, e.g. "OAuthProvider"
+func (pf ProjectFeatures) OfType(featureType string) ProjectFeatures {
+	ret := make(ProjectFeatures, 0)
+	for _, feature := range pf {
+		if feature.Type == featureType {
+			ret = append(ret, feature)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"errors"
+	"sort"
+)
+
+// ProjectTree is a project linked to its parent and its fully loaded
+// subprojects. Build configurations and templates of each level are found in
+// Project.BuildConfigurations and Project.Templates.
+type ProjectTree struct {
+	Project  *Project
+	Parent   *ProjectTree
+	Children []*ProjectTree
+}
+
+// WalkFunc is called for every node visited by Walk. depth is 0 for the node
+// Walk was started on.
+type WalkFunc func(node *ProjectTree, depth int) error
+
+// SkipChildren can be returned from a pre-order WalkFunc to skip the
+// subprojects of the current node. The post-order callback is still called.
+var SkipChildren = errors.New("skip children")
+
+// NewProjectTree links a project to its parent node. Children are added with
+// AddChild.
+func NewProjectTree(project *Project, parent *ProjectTree) *ProjectTree {
+	return &ProjectTree{
+		Project:  project,
+		Parent:   parent,
+		Children: make([]*ProjectTree, 0),
+	}
+}
+
+// AddChild appends a subproject, keeping children sorted by project ID
+func (t *ProjectTree) AddChild(child *ProjectTree) {
+	child.Parent = t
+	t.Children = append(t.Children, child)
+	sort.Slice(t.Children, func(i, j int) bool {
+		return t.Children[i].Project.ID < t.Children[j].Project.ID
+	})
+}
+
+// Walk visits the tree depth first, calling pre before and post after the
+// children of a node. Either callback may be nil. Any error other than
+// SkipChildren stops the walk and is returned.
+func (t *ProjectTree) Walk(pre, post WalkFunc) error {
+	return t.walk(pre, post, 0)
+}
+
+func (t *ProjectTree) walk(pre, post WalkFunc, depth int) error {
+	skip := false
+	if pre != nil {
+		if err := pre(t, depth); err != nil {
+			if err != SkipChildren {
+				return err
+			}
+			skip = true
+		}
+	}
+	if !skip {
+		for _, child := range t.Children {
+			if err := child.walk(pre, post, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	if post != nil {
+		return post(t, depth)
+	}
+	return nil
+}
+
+// Find returns the node for projectID within the tree, or nil
+func (t *ProjectTree) Find(projectID string) *ProjectTree {
+	var found *ProjectTree
+	t.Walk(func(node *ProjectTree, depth int) error {
+		if found != nil {
+			return SkipChildren
+		}
+		if node.Project.ID == projectID {
+			found = node
+			return SkipChildren
+		}
+		return nil
+	}, nil)
+	return found
+}
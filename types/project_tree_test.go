@@ -0,0 +1,82 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testProjectTree() *ProjectTree {
+	root := NewProjectTree(&Project{ID: "_Root"}, nil)
+	single := NewProjectTree(&Project{ID: "Single"}, nil)
+	empty := NewProjectTree(&Project{ID: "Empty"}, nil)
+	emptyHello := NewProjectTree(&Project{ID: "Empty_Hello"}, nil)
+	empty.AddChild(emptyHello)
+	root.AddChild(single)
+	root.AddChild(empty)
+	return root
+}
+
+func TestProjectTreeWalk(t *testing.T) {
+	root := testProjectTree()
+	visits := make([]string, 0)
+
+	err := root.Walk(func(node *ProjectTree, depth int) error {
+		visits = append(visits, fmt.Sprintf("pre %s %d", node.Project.ID, depth))
+		return nil
+	}, func(node *ProjectTree, depth int) error {
+		visits = append(visits, fmt.Sprintf("post %s %d", node.Project.ID, depth))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"pre _Root 0",
+		"pre Empty 1",
+		"pre Empty_Hello 2",
+		"post Empty_Hello 2",
+		"post Empty 1",
+		"pre Single 1",
+		"post Single 1",
+		"post _Root 0",
+	}, visits)
+}
+
+func TestProjectTreeWalkSkipChildren(t *testing.T) {
+	root := testProjectTree()
+	visits := make([]string, 0)
+
+	err := root.Walk(func(node *ProjectTree, depth int) error {
+		visits = append(visits, node.Project.ID)
+		if node.Project.ID == "Empty" {
+			return SkipChildren
+		}
+		return nil
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"_Root", "Empty", "Single"}, visits)
+}
+
+func TestProjectTreeWalkError(t *testing.T) {
+	root := testProjectTree()
+	stop := errors.New("stop")
+
+	err := root.Walk(nil, func(node *ProjectTree, depth int) error {
+		if node.Project.ID == "Empty_Hello" {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+}
+
+func TestProjectTreeFind(t *testing.T) {
+	root := testProjectTree()
+
+	node := root.Find("Empty_Hello")
+	if assert.NotNil(t, node) {
+		assert.Equal(t, "Empty", node.Parent.Project.ID)
+	}
+	assert.Nil(t, root.Find("Missing"))
+}
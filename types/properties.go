@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"sort"
 )
 
 type Properties map[string]string
@@ -19,15 +20,25 @@ func (p Properties) MarshalJSON() ([]byte, error) {
 	pi := &propertiesInput{
 		Property: make([]oneProperty, 0),
 	}
-	for name, property := range p {
+	for _, name := range p.names() {
 		pi.Property = append(pi.Property, oneProperty{
 			Name:  name,
-			Value: property,
+			Value: p[name],
 		})
 	}
 	return json.Marshal(pi)
 }
 
+// names returns the property names sorted, so that marshaled output is stable
+func (p Properties) names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (p *Properties) UnmarshalJSON(b []byte) error {
 	var pi propertiesInput
 	if err := json.Unmarshal(b, &pi); err != nil {
@@ -40,3 +51,58 @@ func (p *Properties) UnmarshalJSON(b []byte) error {
 	*p = m
 	return nil
 }
+
+// Property is one entry of OrderedProperties
+type Property struct {
+	Name  string
+	Value string
+}
+
+// OrderedProperties holds properties in a fixed order. Unmarshaling keeps the
+// order the server sent, so a round trip writes them back unchanged.
+type OrderedProperties []Property
+
+// Ordered returns the properties sorted by name
+func (p Properties) Ordered() OrderedProperties {
+	ordered := make(OrderedProperties, 0, len(p))
+	for _, name := range p.names() {
+		ordered = append(ordered, Property{Name: name, Value: p[name]})
+	}
+	return ordered
+}
+
+// Properties returns the properties as a map. Later entries win when a name
+// appears more than once.
+func (p OrderedProperties) Properties() Properties {
+	m := make(Properties)
+	for _, property := range p {
+		m[property.Name] = property.Value
+	}
+	return m
+}
+
+func (p OrderedProperties) MarshalJSON() ([]byte, error) {
+	pi := &propertiesInput{
+		Property: make([]oneProperty, 0, len(p)),
+	}
+	for _, property := range p {
+		pi.Property = append(pi.Property, oneProperty{
+			Name:  property.Name,
+			Value: property.Value,
+		})
+	}
+	return json.Marshal(pi)
+}
+
+func (p *OrderedProperties) UnmarshalJSON(b []byte) error {
+	var pi propertiesInput
+	if err := json.Unmarshal(b, &pi); err != nil {
+		return err
+	}
+	ordered := make(OrderedProperties, 0, len(pi.Property))
+	for _, prop := range pi.Property {
+		ordered = append(ordered, Property{Name: prop.Name, Value: prop.Value})
+	}
+	*p = ordered
+	return nil
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// SecureTokenPrefix starts a value holding a secure token in place of
+	// a secret
+	SecureTokenPrefix = "credentialsJSON:"
+	// SecurePropertyPrefix starts the names of properties whose values the
+	// server never returns, e.g. secure:password of a VCS root
+	SecurePropertyPrefix  = "secure:"
+	secureReferencePrefix = "%secure:"
+)
+
+// IsSecureProperty tells whether the named property holds a secret the
+// server does not return
+func IsSecureProperty(name string) bool {
+	return strings.HasPrefix(name, SecurePropertyPrefix)
+}
+
+// SecureValue is a token referring to a secret stored by TeamCity. Settings
+// and parameters hold the token in place of the secret, so the plain value
+// never has to leave the server.
+type SecureValue string
+
+// ParseSecureValue accepts a token with or without the credentialsJSON:
+// prefix
+func ParseSecureValue(value string) (SecureValue, error) {
+	token := strings.TrimPrefix(strings.TrimSpace(value), SecureTokenPrefix)
+	if token == "" || strings.ContainsAny(token, " %") {
+		return "", fmt.Errorf("invalid secure token %q", value)
+	}
+	return SecureValue(token), nil
+}
+
+// String returns the token in the credentialsJSON:<token> form used in
+// parameter values and settings
+func (s SecureValue) String() string {
+	return SecureTokenPrefix + string(s)
+}
+
+func (s SecureValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *SecureValue) UnmarshalJSON(b []byte) error {
+	var value string
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseSecureValue(value)
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// Parameter returns a password parameter holding the token
+func (s SecureValue) Parameter() Parameter {
+	return Parameter{
+		Value: s.String(),
+		Spec:  &ParameterSpec{Type: PasswordType{}},
+	}
+}
+
+// IsSecureReference tells whether a value refers to a secret instead of
+// holding it, either as a credentialsJSON: token or as a %secure:...%
+// reference.
+func IsSecureReference(value string) bool {
+	if strings.HasPrefix(value, SecureTokenPrefix) {
+		return true
+	}
+	return strings.HasPrefix(value, secureReferencePrefix) && strings.HasSuffix(value, "%") &&
+		strings.Count(value, "%") == 2
+}
+
+// SecurePasswordReference returns the reference TeamCity substitutes for the
+// value of the named password parameter, e.g.
+// %secure:teamcity.password.env.TOKEN%
+func SecurePasswordReference(name string) string {
+	return fmt.Sprintf("%steamcity.password.%s%%", secureReferencePrefix, name)
+}
+
+// IsSecure tells whether the parameter is a password or refers to a secret
+func (p Parameter) IsSecure() bool {
+	if p.Spec != nil {
+		if _, ok := p.Spec.Type.(PasswordType); ok {
+			return true
+		}
+	}
+	return IsSecureReference(p.Value)
+}
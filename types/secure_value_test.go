@@ -0,0 +1,40 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureValueJSON(t *testing.T) {
+	value := SecureValue("c3e1f2d0-17a4")
+	bytes, err := json.Marshal(value)
+	require.NoError(t, err)
+	assert.Equal(t, `"credentialsJSON:c3e1f2d0-17a4"`, string(bytes))
+
+	var parsed SecureValue
+	require.NoError(t, json.Unmarshal(bytes, &parsed))
+	assert.Equal(t, value, parsed)
+
+	assert.Error(t, json.Unmarshal([]byte(`"credentialsJSON:"`), &parsed))
+}
+
+func TestIsSecureReference(t *testing.T) {
+	assert.True(t, IsSecureReference("credentialsJSON:c3e1f2d0-17a4"))
+	assert.True(t, IsSecureReference(SecurePasswordReference("env.MUH")))
+	assert.Equal(t, "%secure:teamcity.password.env.MUH%", SecurePasswordReference("env.MUH"))
+	assert.False(t, IsSecureReference("hunter2"))
+	assert.False(t, IsSecureReference("%secure:teamcity.password.a% and %b%"))
+
+	assert.True(t, SecureValue("abc").Parameter().IsSecure())
+	assert.True(t, Parameter{Spec: &ParameterSpec{Type: PasswordType{}}}.IsSecure())
+	assert.False(t, Parameter{Value: "plain"}.IsSecure())
+}
+
+func TestIsSecureProperty(t *testing.T) {
+	assert.True(t, IsSecureProperty("secure:password"))
+	assert.False(t, IsSecureProperty("password"))
+	assert.False(t, IsSecureProperty("url"))
+}
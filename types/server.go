@@ -8,4 +8,15 @@ type Server struct {
 	CurrentTime  JSONTime
 	BuildNumber  string
 	BuildDate    JSONTime
+
+	// WebURL is the server URL from the global settings
+	WebURL string `json:"webUrl,omitempty"`
+	// ArtifactsURL is the separate domain artifacts are served from, if
+	// configured in the global settings
+	ArtifactsURL string `json:"artifactsUrl,omitempty"`
+	// InternalID identifies the server installation
+	InternalID string `json:"internalId,omitempty"`
+	// Role is the role of the node answering in a multi-node setup, e.g.
+	// main_node
+	Role string `json:"role,omitempty"`
 }
@@ -1,6 +1,44 @@
 package types
 
+import (
+	"encoding/json"
+)
+
 type TemplateID struct {
 	ID        string `json:"id"`
 	ProjectID string `json:"projectId,omitempty"`
 }
+
+// TemplateIds is the ordered list of templates attached to a build
+// configuration. TeamCity 2017.2 and later allow more than one template;
+// settings from earlier templates take priority over later ones.
+type TemplateIds []TemplateId
+
+type templateIdsInput struct {
+	BuildType []BuildConfigurationShort `json:"buildType"`
+}
+
+func (t TemplateIds) MarshalJSON() ([]byte, error) {
+	ti := &templateIdsInput{
+		BuildType: make([]BuildConfigurationShort, 0),
+	}
+	for _, id := range t {
+		ti.BuildType = append(ti.BuildType, BuildConfigurationShort{
+			ID: string(id),
+		})
+	}
+	return json.Marshal(ti)
+}
+
+func (t *TemplateIds) UnmarshalJSON(b []byte) error {
+	var ti templateIdsInput
+	if err := json.Unmarshal(b, &ti); err != nil {
+		return err
+	}
+	ids := make(TemplateIds, 0)
+	for _, bt := range ti.BuildType {
+		ids = append(ids, TemplateId(bt.ID))
+	}
+	*t = ids
+	return nil
+}
@@ -0,0 +1,150 @@
+package types
+
+const GitVcsName = "jetbrains.git"
+
+type GitAuthMethod string
+
+const (
+	GitAuthAnonymous         GitAuthMethod = "ANONYMOUS"
+	GitAuthPassword          GitAuthMethod = "PASSWORD"
+	GitAuthPrivateKeyDefault GitAuthMethod = "PRIVATE_KEY_DEFAULT"
+	GitAuthPrivateKeyFile    GitAuthMethod = "PRIVATE_KEY_FILE"
+	GitAuthUploadedKey       GitAuthMethod = "TEAMCITY_SSH_KEY"
+)
+
+// GitAgentCleanPolicy decides when the agent runs git clean
+type GitAgentCleanPolicy string
+
+const (
+	GitCleanOnBranchChange GitAgentCleanPolicy = "ON_BRANCH_CHANGE"
+	GitCleanAlways         GitAgentCleanPolicy = "ALWAYS"
+	GitCleanNever          GitAgentCleanPolicy = "NEVER"
+)
+
+// GitAgentCleanFilesPolicy decides which files git clean removes
+type GitAgentCleanFilesPolicy string
+
+const (
+	GitCleanAllUntracked   GitAgentCleanFilesPolicy = "ALL_UNTRACKED"
+	GitCleanIgnoredOnly    GitAgentCleanFilesPolicy = "IGNORED_ONLY"
+	GitCleanNonIgnoredOnly GitAgentCleanFilesPolicy = "NON_IGNORED_ONLY"
+)
+
+// GitVcsRoot is a typed view of a jetbrains.git VCS root
+type GitVcsRoot struct {
+	ID        string
+	Name      string
+	ProjectID string
+
+	URL        string
+	PushURL    string
+	Branch     string
+	BranchSpec string
+
+	AuthMethod     GitAuthMethod
+	Username       string
+	Password       string
+	PrivateKeyPath string
+	UploadedKey    string
+	Passphrase     string
+
+	AgentCleanPolicy      GitAgentCleanPolicy
+	AgentCleanFilesPolicy GitAgentCleanFilesPolicy
+	IgnoreSubmodules      bool
+	ReportTagRevisions    bool
+	IgnoreKnownHosts      bool
+
+	// Extra holds any other properties, which are passed through unchanged
+	Extra Properties
+}
+
+var gitProperties = []string{
+	"url", "push_url", "branch", "teamcity:branchSpec", "authMethod", "username",
+	"secure:password", "privateKeyPath", "teamcitySshKey", "secure:passphrase",
+	"agentCleanPolicy", "agentCleanFilesPolicy", "submoduleCheckout",
+	"reportTagRevisions", "ignoreKnownHosts",
+}
+
+func (g *GitVcsRoot) Validate() error {
+	v := &vcsRootValidator{vcsName: GitVcsName}
+	v.required("name", g.Name)
+	v.required("project", g.ProjectID)
+	v.required("url", g.URL)
+	v.required("branch", g.Branch)
+	v.oneOf("authMethod", string(g.AuthMethod), string(GitAuthAnonymous), string(GitAuthPassword),
+		string(GitAuthPrivateKeyDefault), string(GitAuthPrivateKeyFile), string(GitAuthUploadedKey))
+	switch g.AuthMethod {
+	case GitAuthPassword:
+		v.required("username", g.Username)
+		v.required("password", g.Password)
+	case GitAuthPrivateKeyFile:
+		v.required("privateKeyPath", g.PrivateKeyPath)
+	case GitAuthUploadedKey:
+		v.required("uploaded key", g.UploadedKey)
+	}
+	v.oneOf("agentCleanPolicy", string(g.AgentCleanPolicy), string(GitCleanOnBranchChange),
+		string(GitCleanAlways), string(GitCleanNever))
+	v.oneOf("agentCleanFilesPolicy", string(g.AgentCleanFilesPolicy), string(GitCleanAllUntracked),
+		string(GitCleanIgnoredOnly), string(GitCleanNonIgnoredOnly))
+	return v.err()
+}
+
+func (g *GitVcsRoot) VcsRoot() *VcsRoot {
+	p := make(vcsProperties)
+	for name, value := range g.Extra {
+		p[name] = value
+	}
+	p.set("url", g.URL)
+	p.set("push_url", g.PushURL)
+	p.set("branch", g.Branch)
+	p.set("teamcity:branchSpec", g.BranchSpec)
+	p.set("authMethod", string(g.AuthMethod))
+	p.set("username", g.Username)
+	p.set("secure:password", g.Password)
+	p.set("privateKeyPath", g.PrivateKeyPath)
+	p.set("teamcitySshKey", g.UploadedKey)
+	p.set("secure:passphrase", g.Passphrase)
+	p.set("agentCleanPolicy", string(g.AgentCleanPolicy))
+	p.set("agentCleanFilesPolicy", string(g.AgentCleanFilesPolicy))
+	if g.IgnoreSubmodules {
+		p["submoduleCheckout"] = "IGNORE"
+	}
+	p.setBool("reportTagRevisions", g.ReportTagRevisions)
+	p.setBool("ignoreKnownHosts", g.IgnoreKnownHosts)
+	return &VcsRoot{
+		ID:         g.ID,
+		Name:       g.Name,
+		VcsName:    GitVcsName,
+		ProjectID:  ProjectId(g.ProjectID),
+		Properties: Properties(p),
+	}
+}
+
+// GitVcsRootFromVcsRoot reads a jetbrains.git VCS root into the typed model
+func GitVcsRootFromVcsRoot(v *VcsRoot) (*GitVcsRoot, error) {
+	if err := checkVcsName(v, GitVcsName); err != nil {
+		return nil, err
+	}
+	p := v.Properties
+	return &GitVcsRoot{
+		ID:                    v.ID,
+		Name:                  v.Name,
+		ProjectID:             string(v.ProjectID),
+		URL:                   p["url"],
+		PushURL:               p["push_url"],
+		Branch:                p["branch"],
+		BranchSpec:            p["teamcity:branchSpec"],
+		AuthMethod:            GitAuthMethod(p["authMethod"]),
+		Username:              p["username"],
+		Password:              p["secure:password"],
+		PrivateKeyPath:        p["privateKeyPath"],
+		UploadedKey:           p["teamcitySshKey"],
+		Passphrase:            p["secure:passphrase"],
+		AgentCleanPolicy:      GitAgentCleanPolicy(p["agentCleanPolicy"]),
+		AgentCleanFilesPolicy: GitAgentCleanFilesPolicy(p["agentCleanFilesPolicy"]),
+		IgnoreSubmodules:      p["submoduleCheckout"] == "IGNORE",
+		ReportTagRevisions:    p["reportTagRevisions"] == "true",
+		IgnoreKnownHosts:      p["ignoreKnownHosts"] == "true",
+		Extra:                 extraProperties(p, gitProperties),
+	}, nil
+}
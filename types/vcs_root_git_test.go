@@ -0,0 +1,77 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitVcsRootRoundTrip(t *testing.T) {
+	git := &GitVcsRoot{
+		ID:               "Single_SdkGit",
+		Name:             "SDK",
+		ProjectID:        "Single",
+		URL:              "https://github.com/icelander/teamcity-sdk-go",
+		Branch:           "refs/heads/master",
+		BranchSpec:       "+:refs/heads/*",
+		AuthMethod:       GitAuthPassword,
+		Username:         "bot",
+		Password:         "hunter2",
+		AgentCleanPolicy: GitCleanAlways,
+		IgnoreSubmodules: true,
+		Extra: Properties{
+			"usernameStyle": "USERID",
+		},
+	}
+	require.NoError(t, git.Validate())
+
+	vcs := git.VcsRoot()
+	assert.Equal(t, GitVcsName, vcs.VcsName)
+	assert.Equal(t, ProjectId("Single"), vcs.ProjectID)
+	assert.Equal(t, Properties{
+		"url":                 "https://github.com/icelander/teamcity-sdk-go",
+		"branch":              "refs/heads/master",
+		"teamcity:branchSpec": "+:refs/heads/*",
+		"authMethod":          "PASSWORD",
+		"username":            "bot",
+		"secure:password":     "hunter2",
+		"agentCleanPolicy":    "ALWAYS",
+		"submoduleCheckout":   "IGNORE",
+		"usernameStyle":       "USERID",
+	}, vcs.Properties)
+
+	back, err := GitVcsRootFromVcsRoot(vcs)
+	require.NoError(t, err)
+	assert.Equal(t, git, back)
+}
+
+func TestGitVcsRootValidate(t *testing.T) {
+	git := &GitVcsRoot{
+		Name:             "SDK",
+		URL:              "https://github.com/icelander/teamcity-sdk-go",
+		AuthMethod:       GitAuthPrivateKeyFile,
+		AgentCleanPolicy: "SOMETIMES",
+	}
+	err := git.Validate()
+	require.Error(t, err)
+	verr, ok := err.(*VcsRootValidationError)
+	require.True(t, ok, "Expected a VcsRootValidationError")
+	assert.Equal(t, []string{
+		"project is required",
+		"branch is required",
+		"privateKeyPath is required",
+		`agentCleanPolicy "SOMETIMES" is not one of ON_BRANCH_CHANGE, ALWAYS, NEVER`,
+	}, verr.Problems)
+}
+
+func TestTypedVcsRootWrongVcsName(t *testing.T) {
+	_, err := MercurialVcsRootFromVcsRoot(&VcsRoot{ID: "Single_SdkGit", VcsName: GitVcsName})
+	assert.EqualError(t, err, `VCS root Single_SdkGit has vcsName "jetbrains.git", not "mercurial"`)
+
+	svn := &SubversionVcsRoot{Name: "Trunk", ProjectID: "Single", URL: "svn://example.com/trunk", ExternalsMode: SubversionExternalsNone}
+	assert.NoError(t, svn.Validate())
+	back, err := SubversionVcsRootFromVcsRoot(svn.VcsRoot())
+	require.NoError(t, err)
+	assert.Equal(t, svn, back)
+}
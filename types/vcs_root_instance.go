@@ -0,0 +1,13 @@
+package types
+
+// VcsRootInstance is a VCS root resolved for a particular build
+// configuration, with all parameter references substituted. TeamCity checks
+// for changes per instance.
+type VcsRootInstance struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name,omitempty"`
+	VcsRootID           string `json:"vcs-root-id,omitempty"`
+	Href                string `json:"href,omitempty"`
+	LastVersion         string `json:"lastVersion,omitempty"`
+	LastVersionInternal string `json:"lastVersionInternal,omitempty"`
+}
@@ -0,0 +1,80 @@
+package types
+
+const MercurialVcsName = "mercurial"
+
+// MercurialVcsRoot is a typed view of a mercurial VCS root
+type MercurialVcsRoot struct {
+	ID        string
+	Name      string
+	ProjectID string
+
+	RepositoryPath string
+	Branch         string
+	BranchSpec     string
+	Username       string
+	Password       string
+	HgCommandPath  string
+
+	UseArchiveForPatch   bool
+	DetectSubrepoChanges bool
+
+	// Extra holds any other properties, which are passed through unchanged
+	Extra Properties
+}
+
+var mercurialProperties = []string{
+	"repositoryPath", "branchName", "teamcity:branchSpec", "username",
+	"secure:password", "hgCommandPath", "useArchiveForPatch", "detectSubrepoChanges",
+}
+
+func (m *MercurialVcsRoot) Validate() error {
+	v := &vcsRootValidator{vcsName: MercurialVcsName}
+	v.required("name", m.Name)
+	v.required("project", m.ProjectID)
+	v.required("repositoryPath", m.RepositoryPath)
+	return v.err()
+}
+
+func (m *MercurialVcsRoot) VcsRoot() *VcsRoot {
+	p := make(vcsProperties)
+	for name, value := range m.Extra {
+		p[name] = value
+	}
+	p.set("repositoryPath", m.RepositoryPath)
+	p.set("branchName", m.Branch)
+	p.set("teamcity:branchSpec", m.BranchSpec)
+	p.set("username", m.Username)
+	p.set("secure:password", m.Password)
+	p.set("hgCommandPath", m.HgCommandPath)
+	p.setBool("useArchiveForPatch", m.UseArchiveForPatch)
+	p.setBool("detectSubrepoChanges", m.DetectSubrepoChanges)
+	return &VcsRoot{
+		ID:         m.ID,
+		Name:       m.Name,
+		VcsName:    MercurialVcsName,
+		ProjectID:  ProjectId(m.ProjectID),
+		Properties: Properties(p),
+	}
+}
+
+// MercurialVcsRootFromVcsRoot reads a mercurial VCS root into the typed model
+func MercurialVcsRootFromVcsRoot(v *VcsRoot) (*MercurialVcsRoot, error) {
+	if err := checkVcsName(v, MercurialVcsName); err != nil {
+		return nil, err
+	}
+	p := v.Properties
+	return &MercurialVcsRoot{
+		ID:                   v.ID,
+		Name:                 v.Name,
+		ProjectID:            string(v.ProjectID),
+		RepositoryPath:       p["repositoryPath"],
+		Branch:               p["branchName"],
+		BranchSpec:           p["teamcity:branchSpec"],
+		Username:             p["username"],
+		Password:             p["secure:password"],
+		HgCommandPath:        p["hgCommandPath"],
+		UseArchiveForPatch:   p["useArchiveForPatch"] == "true",
+		DetectSubrepoChanges: p["detectSubrepoChanges"] == "true",
+		Extra:                extraProperties(p, mercurialProperties),
+	}, nil
+}
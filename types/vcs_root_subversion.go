@@ -0,0 +1,83 @@
+package types
+
+const SubversionVcsName = "svn"
+
+// SubversionExternalsMode decides how svn:externals are handled
+type SubversionExternalsMode string
+
+const (
+	SubversionExternalsFull     SubversionExternalsMode = "externals-full"
+	SubversionExternalsCheckout SubversionExternalsMode = "externals-checkout"
+	SubversionExternalsNone     SubversionExternalsMode = "externals-none"
+)
+
+// SubversionVcsRoot is a typed view of a svn VCS root
+type SubversionVcsRoot struct {
+	ID        string
+	Name      string
+	ProjectID string
+
+	URL               string
+	Username          string
+	Password          string
+	ExternalsMode     SubversionExternalsMode
+	WorkingCopyFormat string
+	LabelingRules     string
+
+	// Extra holds any other properties, which are passed through unchanged
+	Extra Properties
+}
+
+var subversionProperties = []string{
+	"url", "user", "secure:svn-password", "externals-mode", "working-copy-format", "labelingPatterns",
+}
+
+func (s *SubversionVcsRoot) Validate() error {
+	v := &vcsRootValidator{vcsName: SubversionVcsName}
+	v.required("name", s.Name)
+	v.required("project", s.ProjectID)
+	v.required("url", s.URL)
+	v.oneOf("externals-mode", string(s.ExternalsMode), string(SubversionExternalsFull),
+		string(SubversionExternalsCheckout), string(SubversionExternalsNone))
+	return v.err()
+}
+
+func (s *SubversionVcsRoot) VcsRoot() *VcsRoot {
+	p := make(vcsProperties)
+	for name, value := range s.Extra {
+		p[name] = value
+	}
+	p.set("url", s.URL)
+	p.set("user", s.Username)
+	p.set("secure:svn-password", s.Password)
+	p.set("externals-mode", string(s.ExternalsMode))
+	p.set("working-copy-format", s.WorkingCopyFormat)
+	p.set("labelingPatterns", s.LabelingRules)
+	return &VcsRoot{
+		ID:         s.ID,
+		Name:       s.Name,
+		VcsName:    SubversionVcsName,
+		ProjectID:  ProjectId(s.ProjectID),
+		Properties: Properties(p),
+	}
+}
+
+// SubversionVcsRootFromVcsRoot reads a svn VCS root into the typed model
+func SubversionVcsRootFromVcsRoot(v *VcsRoot) (*SubversionVcsRoot, error) {
+	if err := checkVcsName(v, SubversionVcsName); err != nil {
+		return nil, err
+	}
+	p := v.Properties
+	return &SubversionVcsRoot{
+		ID:                v.ID,
+		Name:              v.Name,
+		ProjectID:         string(v.ProjectID),
+		URL:               p["url"],
+		Username:          p["user"],
+		Password:          p["secure:svn-password"],
+		ExternalsMode:     SubversionExternalsMode(p["externals-mode"]),
+		WorkingCopyFormat: p["working-copy-format"],
+		LabelingRules:     p["labelingPatterns"],
+		Extra:             extraProperties(p, subversionProperties),
+	}, nil
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypedVcsRoot is implemented by the typed VCS root models, which know the
+// property keys of a particular VCS plugin.
+type TypedVcsRoot interface {
+	// Validate checks that required fields are set and enums hold known values
+	Validate() error
+	// VcsRoot converts the model into the generic form used by the REST API
+	VcsRoot() *VcsRoot
+}
+
+// VcsRootValidationError lists every problem found in a typed VCS root
+type VcsRootValidationError struct {
+	VcsName  string
+	Problems []string
+}
+
+func (e *VcsRootValidationError) Error() string {
+	return fmt.Sprintf("invalid %s VCS root: %s", e.VcsName, strings.Join(e.Problems, "; "))
+}
+
+type vcsRootValidator struct {
+	vcsName  string
+	problems []string
+}
+
+func (v *vcsRootValidator) required(name, value string) {
+	if value == "" {
+		v.problems = append(v.problems, fmt.Sprintf("%s is required", name))
+	}
+}
+
+func (v *vcsRootValidator) oneOf(name, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.problems = append(v.problems, fmt.Sprintf("%s %q is not one of %s", name, value, strings.Join(allowed, ", ")))
+}
+
+func (v *vcsRootValidator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &VcsRootValidationError{
+		VcsName:  v.vcsName,
+		Problems: v.problems,
+	}
+}
+
+// vcsProperties collects VCS root properties, skipping empty values so that
+// the plugin defaults apply.
+type vcsProperties Properties
+
+func (p vcsProperties) set(name, value string) {
+	if value != "" {
+		p[name] = value
+	}
+}
+
+func (p vcsProperties) setBool(name string, value bool) {
+	if value {
+		p[name] = "true"
+	}
+}
+
+// extraProperties returns the properties that are not in known
+func extraProperties(properties Properties, known []string) Properties {
+	extra := make(Properties)
+	for name, value := range properties {
+		extra[name] = value
+	}
+	for _, name := range known {
+		delete(extra, name)
+	}
+	if len(extra) == 0 {
+		return nil
+	}
+	return extra
+}
+
+func checkVcsName(v *VcsRoot, vcsName string) error {
+	if v.VcsName != vcsName {
+		return fmt.Errorf("VCS root %s has vcsName %q, not %q", v.ID, v.VcsName, vcsName)
+	}
+	return nil
+}
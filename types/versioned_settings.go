@@ -0,0 +1,107 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// VersionedSettingsFeatureType is the project feature type holding the
+// versioned settings configuration of a project.
+const VersionedSettingsFeatureType = "versionedSettings"
+
+type VersionedSettingsFormat string
+
+const (
+	VersionedSettingsKotlin VersionedSettingsFormat = "kotlin"
+	VersionedSettingsXML    VersionedSettingsFormat = "xml"
+)
+
+// VersionedSettingsSyncMode controls which settings TeamCity uses when a build
+// starts: the ones on the server or the ones committed to VCS.
+type VersionedSettingsSyncMode string
+
+const (
+	AlwaysUseCurrent VersionedSettingsSyncMode = "ALWAYS_USE_CURRENT"
+	PreferCurrent    VersionedSettingsSyncMode = "PREFER_CURRENT"
+	PreferVcs        VersionedSettingsSyncMode = "PREFER_VCS"
+)
+
+// VersionedSettings is a typed view of the versionedSettings project feature
+type VersionedSettings struct {
+	Enabled                  bool
+	VcsRootID                string
+	Format                   VersionedSettingsFormat
+	SyncMode                 VersionedSettingsSyncMode
+	ShowChanges              bool
+	UseRelativeIds           bool
+	StoreSecureValuesOutside bool
+}
+
+// ProjectFeature converts the settings into a project feature. id is the
+// feature ID to update, or empty to create a new feature.
+func (v VersionedSettings) ProjectFeature(id string) ProjectFeature {
+	properties := Properties{
+		"enabled":        strconv.FormatBool(v.Enabled),
+		"showChanges":    strconv.FormatBool(v.ShowChanges),
+		"useRelativeIds": strconv.FormatBool(v.UseRelativeIds),
+	}
+	if v.VcsRootID != "" {
+		properties["rootId"] = v.VcsRootID
+	}
+	if v.Format != "" {
+		properties["format"] = string(v.Format)
+	}
+	if v.SyncMode != "" {
+		properties["buildSettings"] = string(v.SyncMode)
+	}
+	if v.StoreSecureValuesOutside {
+		properties["credentialsStorageType"] = "credentialsJSON"
+	}
+	return ProjectFeature{
+		ID:         id,
+		Type:       VersionedSettingsFeatureType,
+		Properties: properties,
+	}
+}
+
+// versionedSettingsProperties are the feature properties VersionedSettings
+// models
+var versionedSettingsProperties = []string{
+	"enabled", "showChanges", "useRelativeIds", "rootId", "format", "buildSettings", "credentialsStorageType",
+}
+
+// UpdateProjectFeature applies the settings to an existing versioned settings
+// feature. Properties VersionedSettings does not model, such as
+// twoWaySynchronization or allowUIEditing, are kept.
+func (v VersionedSettings) UpdateProjectFeature(feature ProjectFeature) ProjectFeature {
+	properties := make(Properties)
+	for name, value := range feature.Properties {
+		properties[name] = value
+	}
+	for _, name := range versionedSettingsProperties {
+		delete(properties, name)
+	}
+	updated := v.ProjectFeature(feature.ID)
+	for name, value := range updated.Properties {
+		properties[name] = value
+	}
+	updated.Properties = properties
+	return updated
+}
+
+// ParseVersionedSettings reads the versioned settings from a project feature
+func ParseVersionedSettings(feature ProjectFeature) (*VersionedSettings, error) {
+	if feature.Type != VersionedSettingsFeatureType {
+		return nil, fmt.Errorf("project feature %s has type %q, not %q", feature.ID, feature.Type, VersionedSettingsFeatureType)
+	}
+	p := feature.Properties
+	return &VersionedSettings{
+		Enabled:                  p["enabled"] == "true",
+		VcsRootID:                p["rootId"],
+		Format:                   VersionedSettingsFormat(p["format"]),
+		SyncMode:                 VersionedSettingsSyncMode(p["buildSettings"]),
+		ShowChanges:              p["showChanges"] == "true",
+		UseRelativeIds:           p["useRelativeIds"] == "true",
+		StoreSecureValuesOutside: p["credentialsStorageType"] == "credentialsJSON",
+	}, nil
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionedSettingsRoundTrip(t *testing.T) {
+	var features ProjectFeatures
+	err := json.Unmarshal([]byte(`{"count":2,"projectFeature":[
+		{"id":"PROJECT_EXT_1","type":"OAuthProvider","properties":{"property":[{"name":"providerType","value":"GitHub"}]}},
+		{"id":"PROJECT_EXT_2","type":"versionedSettings","properties":{"property":[
+			{"name":"enabled","value":"true"},
+			{"name":"rootId","value":"Single_Settings"},
+			{"name":"format","value":"kotlin"},
+			{"name":"buildSettings","value":"PREFER_VCS"},
+			{"name":"showChanges","value":"false"},
+			{"name":"useRelativeIds","value":"true"},
+			{"name":"credentialsStorageType","value":"credentialsJSON"}
+		]}}
+	]}`), &features)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(features.OfType(VersionedSettingsFeatureType)))
+
+	feature := features.OfType(VersionedSettingsFeatureType)[0]
+	settings, err := ParseVersionedSettings(feature)
+	require.NoError(t, err)
+	assert.Equal(t, &VersionedSettings{
+		Enabled:                  true,
+		VcsRootID:                "Single_Settings",
+		Format:                   VersionedSettingsKotlin,
+		SyncMode:                 PreferVcs,
+		UseRelativeIds:           true,
+		StoreSecureValuesOutside: true,
+	}, settings)
+	assert.Equal(t, feature, settings.ProjectFeature("PROJECT_EXT_2"))
+
+	_, err = ParseVersionedSettings(features[0])
+	assert.Error(t, err)
+}
+
+func TestVersionedSettingsUpdateProjectFeature(t *testing.T) {
+	feature := ProjectFeature{
+		ID:   "PROJECT_EXT_2",
+		Type: VersionedSettingsFeatureType,
+		Properties: Properties{
+			"enabled":                "true",
+			"rootId":                 "Single_Settings",
+			"format":                 "kotlin",
+			"credentialsStorageType": "credentialsJSON",
+			"twoWaySynchronization":  "false",
+			"allowUIEditing":         "true",
+		},
+	}
+	settings := VersionedSettings{Enabled: true, VcsRootID: "Single_Other", Format: VersionedSettingsXML}
+
+	assert.Equal(t, ProjectFeature{
+		ID:   "PROJECT_EXT_2",
+		Type: VersionedSettingsFeatureType,
+		Properties: Properties{
+			"enabled":               "true",
+			"rootId":                "Single_Other",
+			"format":                "xml",
+			"showChanges":           "false",
+			"useRelativeIds":        "false",
+			"twoWaySynchronization": "false",
+			"allowUIEditing":        "true",
+		},
+	}, settings.UpdateProjectFeature(feature))
+	assert.Equal(t, "kotlin", feature.Properties["format"])
+}
+
+func TestProjectFeatureInheritedNotWritten(t *testing.T) {
+	var feature ProjectFeature
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"PROJECT_EXT_2","type":"versionedSettings","inherited":true}`), &feature))
+	assert.True(t, feature.Inherited)
+
+	data, err := json.Marshal(feature)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"PROJECT_EXT_2","type":"versionedSettings","properties":{"property":[]}}`, string(data))
+}